@@ -0,0 +1,103 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+/*
+Command dw-contract checks that a data.world API host is still compatible with the dwapi package.
+
+	DW_AUTH_TOKEN=... dw-contract -base-url https://api.data.world/v0 -report report.json
+
+It runs the checks of the `dwapitest/contract` package in a scratch dataset and project owned by the
+authenticated user, or by -owner, prints a line per check, writes the JSON report to -report, or to
+stdout if it's "-", and exits with status 1 if any call failed. The base URL defaults to the one the
+dwapi client uses, so DW_API_HOST and DW_ENVIRONMENT are honoured.
+*/
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/datadotworld/dwapi-go/dwapi"
+	"github.com/datadotworld/dwapi-go/dwapitest/contract"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("dw-contract", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	baseURL := flags.String("base-url", dwapi.NewClient("").BaseURL, "base URL of the API, including /v0")
+	token := flags.String("token", os.Getenv("DW_AUTH_TOKEN"), "API token; defaults to $DW_AUTH_TOKEN")
+	owner := flags.String("owner", "", "owner of the scratch dataset and project; the authenticated user if empty")
+	id := flags.String("id", "", "id of the scratch dataset; a new one based on the time if empty")
+	report := flags.String("report", "", `file to write the JSON report to, or "-" for stdout`)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *token == "" {
+		fmt.Fprintln(stderr, "dw-contract: no token; set -token or DW_AUTH_TOKEN")
+		return 2
+	}
+
+	r := contract.Run(contract.Config{BaseURL: *baseURL, Token: *token, Owner: *owner, ScratchID: *id})
+	for _, res := range r.Results {
+		fmt.Fprintf(stderr, "%-4s %s", res.Status, res.Check)
+		if res.Error != "" {
+			fmt.Fprintf(stderr, ": %s", res.Error)
+		}
+		if len(res.UnknownFields) > 0 {
+			fmt.Fprintf(stderr, " unknown fields %v", res.UnknownFields)
+		}
+		if len(res.MissingFields) > 0 {
+			fmt.Fprintf(stderr, " missing fields %v", res.MissingFields)
+		}
+		fmt.Fprintln(stderr)
+	}
+	fmt.Fprintf(stderr, "%d passed, %d with warnings, %d failed, %d skipped\n",
+		r.Summary[contract.Pass], r.Summary[contract.Warn], r.Summary[contract.Fail], r.Summary[contract.Skip])
+
+	if err := writeReport(r, *report, stdout); err != nil {
+		fmt.Fprintf(stderr, "dw-contract: %s\n", err)
+		return 2
+	}
+	if !r.Compatible() {
+		return 1
+	}
+	return 0
+}
+
+func writeReport(r *contract.Report, path string, stdout io.Writer) error {
+	switch path {
+	case "":
+		return nil
+	case "-":
+		return r.WriteJSON(stdout)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err = r.WriteJSON(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
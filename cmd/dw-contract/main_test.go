@@ -0,0 +1,52 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/datadotworld/dwapi-go/dwapitest"
+	"github.com/datadotworld/dwapi-go/dwapitest/contract"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	srv := dwapitest.NewServer()
+	defer srv.Close()
+	srv.SetSQLResult(contract.SQLQuery, "text/csv", []byte("id,name\n1,contract\n"))
+	srv.SetSPARQLResult(contract.SPARQLQuery, "application/sparql-results+json", []byte(`{}`))
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"-base-url", srv.URL, "-token", "token", "-report", "-"}, &stdout, &stderr)
+	assert.Equal(t, 0, code, stderr.String())
+	assert.Contains(t, stderr.String(), "pass Dataset.CreateOrReplace\n")
+	assert.Contains(t, stderr.String(), "skip DOI.Associate: ")
+
+	var r contract.Report
+	assert.NoError(t, json.Unmarshal(stdout.Bytes(), &r))
+	assert.Equal(t, "dwapitest", r.Owner)
+	assert.True(t, r.Compatible())
+
+	srv.RequireToken("secret")
+	stdout.Reset()
+	assert.Equal(t, 1, run([]string{"-base-url", srv.URL, "-token", "token"}, &stdout, &stderr))
+	assert.Empty(t, stdout.String())
+	assert.Equal(t, 2, run([]string{"-base-url", srv.URL, "-token", ""}, &stdout, &stderr))
+}
@@ -0,0 +1,189 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+/*
+Command dw-emulator serves a local stand-in for the data.world API, for services and manual testing
+that can't use the in-process dwapitest package.
+
+	dw-emulator -addr localhost:8080 -data ./emulator-state -seed fixtures/seed.json
+
+It serves the endpoints used by the dwapi package (/datasets, /projects, /uploads, /file_download,
+/download, /sql, /sparql, /streams, /insights, /user/webhooks, ...), with or without the /v0 prefix,
+so clients can be pointed at it with DW_API_HOST=http://localhost:8080.
+
+State is kept in memory and, when -data is set, saved to state.json in that directory after every
+change. Seed fixtures (see `dwapitest.Fixture`) are loaded on start-up when there's no saved state,
+or always with -reset. Query results are not computed: SQL and SPARQL queries return the canned
+results registered in the fixtures for the same query text.
+*/
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/datadotworld/dwapi-go/dwapitest"
+)
+
+const stateFile = "state.json"
+
+type seeds []string
+
+func (s *seeds) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *seeds) Set(v string) error {
+	*s = append(*s, v)
+	return nil
+}
+
+func main() {
+	var fixtures seeds
+	addr := flag.String("addr", "localhost:8080", "address to listen on")
+	dir := flag.String("data", "", "directory to persist state to; state is kept in memory only if empty")
+	token := flag.String("token", "", "API token to require; any bearer token is accepted if empty")
+	pageSize := flag.Int("page-size", 0, "records per page for paginated endpoints; 0 for no limit")
+	reset := flag.Bool("reset", false, "discard saved state and load the seed fixtures again")
+	flag.Var(&fixtures, "seed", "JSON fixture to load on start-up; may be repeated")
+	flag.Parse()
+
+	e, err := newEmulator(*dir, fixtures, *reset)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *token != "" {
+		e.backend.RequireToken(*token)
+	}
+	e.backend.SetPageSize(*pageSize)
+
+	log.Printf("data.world emulator listening on http://%s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, e))
+}
+
+// emulator serves a dwapitest.Backend and saves its state after every successful change.
+type emulator struct {
+	backend *dwapitest.Backend
+	dir     string
+	logger  *log.Logger
+
+	mu sync.Mutex
+}
+
+func newEmulator(dir string, fixtures []string, reset bool) (*emulator, error) {
+	e := &emulator{
+		backend: dwapitest.NewBackend(),
+		dir:     dir,
+		logger:  log.New(os.Stderr, "", log.LstdFlags),
+	}
+
+	restored := false
+	if dir != "" && !reset {
+		f, err := os.Open(filepath.Join(dir, stateFile))
+		switch {
+		case err == nil:
+			err = e.backend.Restore(f)
+			f.Close()
+			if err != nil {
+				return nil, fmt.Errorf("restoring %s: %s", f.Name(), err)
+			}
+			restored = true
+		case !os.IsNotExist(err):
+			return nil, err
+		}
+	}
+
+	if !restored {
+		for _, path := range fixtures {
+			if err := e.backend.LoadFixtureFile(path); err != nil {
+				return nil, err
+			}
+		}
+		if err := e.save(); err != nil {
+			return nil, err
+		}
+	}
+	return e, nil
+}
+
+// save writes the backend state to the data directory, replacing the previous state atomically.
+func (e *emulator) save() error {
+	if e.dir == "" {
+		return nil
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if err := os.MkdirAll(e.dir, 0755); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(e.dir, stateFile+".*")
+	if err != nil {
+		return err
+	}
+	if err = e.backend.Snapshot(tmp); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(e.dir, stateFile))
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (e *emulator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	e.backend.ServeHTTP(rec, r)
+	e.logger.Printf("%s %s %d", r.Method, r.URL.RequestURI(), rec.status)
+
+	if rec.status/100 != 2 || !changesState(r) {
+		return
+	}
+	if err := e.save(); err != nil {
+		e.logger.Printf("saving state: %s", err)
+	}
+}
+
+// changesState reports whether a request may have changed the backend state. Syncs are GETs, and
+// query executions are POSTs that don't change anything.
+func changesState(r *http.Request) bool {
+	path := strings.TrimPrefix(r.URL.Path, "/v0")
+	if r.Method == http.MethodGet {
+		return strings.HasSuffix(path, "/sync")
+	}
+	return !strings.HasPrefix(path, "/sql/") && !strings.HasPrefix(path, "/sparql/") &&
+		!(strings.HasPrefix(path, "/queries/") && strings.HasSuffix(path, "/results"))
+}
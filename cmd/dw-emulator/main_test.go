@@ -0,0 +1,93 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package main
+
+import (
+	"io/ioutil"
+	"log"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/datadotworld/dwapi-go/dwapi"
+	"github.com/stretchr/testify/assert"
+)
+
+const seed = `{
+	"user": {"id": "tim-notes"},
+	"datasets": [{"owner": "tim-notes", "id": "my-awesome-dataset", "title": "My Awesome Dataset"}],
+	"files": [{"owner": "tim-notes", "id": "my-awesome-dataset", "name": "data.csv", "path": "data.csv"}],
+	"results": [{"language": "SQL", "query": "SELECT * FROM data", "acceptType": "text/csv", "path": "data.csv"}]
+}`
+
+func startEmulator(t *testing.T, dir string, fixtures []string, reset bool) (*httptest.Server, *dwapi.Client) {
+	e, err := newEmulator(dir, fixtures, reset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.logger = log.New(ioutil.Discard, "", 0)
+	srv := httptest.NewServer(e)
+	dw := dwapi.NewClient("token")
+	dw.BaseURL = srv.URL + "/v0"
+	return srv, dw
+}
+
+func TestEmulator(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dw-emulator")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	fixture := filepath.Join(dir, "seed.json")
+	_ = ioutil.WriteFile(fixture, []byte(seed), 0644)
+	_ = ioutil.WriteFile(filepath.Join(dir, "data.csv"), []byte("a,b\n1,2\n"), 0644)
+	stateDir := filepath.Join(dir, "state")
+
+	srv, dw := startEmulator(t, stateDir, []string{fixture}, false)
+	r, err := dw.Query.ExecuteSQL("tim-notes", "my-awesome-dataset", "text/csv",
+		&dwapi.SQLQueryRequest{Query: "SELECT * FROM data"})
+	if assert.NoError(t, err) {
+		b, _ := ioutil.ReadAll(r)
+		assert.Equal(t, "a,b\n1,2\n", string(b))
+		r.Close()
+	}
+	_, err = dw.Dataset.Update("tim-notes", "my-awesome-dataset", &dwapi.DatasetUpdateRequest{Summary: "Persisted"})
+	assert.NoError(t, err)
+	srv.Close()
+
+	assert.FileExists(t, filepath.Join(stateDir, stateFile))
+
+	// The saved state takes precedence over the fixtures on restart...
+	srv, dw = startEmulator(t, stateDir, []string{fixture}, false)
+	d, err := dw.Dataset.Retrieve("tim-notes", "my-awesome-dataset")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "Persisted", d.Summary)
+		assert.Len(t, d.Files, 1)
+	}
+	srv.Close()
+
+	// ...unless it is reset.
+	srv, dw = startEmulator(t, stateDir, []string{fixture}, true)
+	d, err = dw.Dataset.Retrieve("tim-notes", "my-awesome-dataset")
+	if assert.NoError(t, err) {
+		assert.Empty(t, d.Summary)
+	}
+	srv.Close()
+}
@@ -49,6 +49,15 @@ type Client struct {
 	BaseURL string
 	Token   string
 
+	// HTTPClient sends the requests to the API. It can be replaced to use a custom transport, e.g. for
+	// recording or replaying API responses in tests. If nil, a client with a 60 second timeout is used.
+	HTTPClient *http.Client
+
+	// StrictMode reports the fields of API responses that the models don't know about, to detect
+	// changes to the API. They're passed to OnUnknownFields, and ignored if it's nil.
+	StrictMode      bool
+	OnUnknownFields func(method, endpoint string, fields []string)
+
 	Dataset *DatasetService
 	DOI     *DoiService
 	File    *FileService
@@ -73,17 +82,26 @@ type paginatedResponse struct {
 	Records       []interface{} `json:"records"`
 }
 
+// defaultTimeout bounds each request of a client.
+const defaultTimeout = 60 * time.Second
+
+// defaultHTTPClient is used by clients whose HTTPClient is nil.
+var defaultHTTPClient = &http.Client{Timeout: defaultTimeout}
+
 func NewClient(token string) *Client {
 	c := &Client{
 		BaseURL: getBaseURL(),
 		Token:   token,
+		HTTPClient: &http.Client{
+			Timeout: defaultTimeout,
+		},
 	}
 	c.Dataset = &DatasetService{c}
 	c.DOI = &DoiService{c}
 	c.File = &FileService{c}
 	c.Insight = &InsightService{c}
 	c.Project = &ProjectService{c}
-	c.Query = &QueryService{c}
+	c.Query = &QueryService{client: c}
 	c.Stream = &StreamService{c}
 	c.User = &UserService{c}
 	c.Webhook = &WebhookService{c}
@@ -136,8 +154,9 @@ func (c *Client) rawRequest(headers *headers, body io.Reader) (io.ReadCloser, er
 		r.Header.Add("Accept", headers.AcceptType)
 	}
 
-	h := &http.Client{
-		Timeout: 60 * time.Second,
+	h := c.HTTPClient
+	if h == nil {
+		h = defaultHTTPClient
 	}
 	response, err := h.Do(r)
 	if err != nil {
@@ -145,6 +164,7 @@ func (c *Client) rawRequest(headers *headers, body io.Reader) (io.ReadCloser, er
 	}
 
 	if string(response.Status[0]) != "2" {
+		response.Body.Close()
 		return nil, errors.New(response.Status)
 	}
 	return response.Body, nil
@@ -163,6 +183,9 @@ func (c *Client) request(headers *headers, body, response interface{}) (err erro
 
 	err = c.unmarshal(r, response)
 	r.Close()
+	if err == nil {
+		c.reportUnknownFields(headers.Method, headers.Endpoint, response)
+	}
 	return
 }
 
@@ -196,6 +219,7 @@ func (c *Client) requestMultiplePages(endpoint string, response interface{}) err
 	if err = json.Unmarshal(b, &response); err != nil {
 		return err
 	}
+	c.reportUnknownFields(GET, endpoint, response)
 	return nil
 }
 
@@ -206,6 +230,7 @@ func (c *Client) saveToFile(path string, contents io.Reader) (err error) {
 	}
 
 	if _, err = io.Copy(f, contents); err != nil {
+		f.Close()
 		return
 	}
 
@@ -26,6 +26,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -136,6 +137,38 @@ func TestNewClient(t *testing.T) {
 	assert.Equal(t, dw.Token, "secret.token")
 }
 
+type roundTripFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestClient_HTTPClient(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var got []string
+	dw.HTTPClient = &http.Client{
+		Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			got = append(got, r.Method+" "+r.URL.Path)
+			return http.DefaultTransport.RoundTrip(r)
+		}),
+	}
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"id": "%s"}`, testClientOwner)
+	})
+	_, err := dw.User.Self()
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"GET /user"}, got)
+	}
+
+	// Without an HTTPClient, requests still time out.
+	dw.HTTPClient = nil
+	_, err = dw.User.Self()
+	assert.NoError(t, err)
+	assert.Equal(t, 60*time.Second, defaultHTTPClient.Timeout)
+}
+
 func TestGetBaseURL(t *testing.T) {
 	dw := getTestClient()
 	assert.Equal(t, dw.BaseURL, defaultBaseURL+"/v0")
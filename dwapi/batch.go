@@ -0,0 +1,105 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package dwapi
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"sync"
+	"time"
+)
+
+// defaultConcurrency is the number of queries of a batch that run at once if BatchOptions doesn't say.
+const defaultConcurrency = 4
+
+// BatchQuery is a query of a batch run by `QueryService.ExecuteBatch`: a SQL or a SPARQL query, with
+// any parameters bound in its request, against a dataset or project.
+type BatchQuery struct {
+	Owner      string
+	ID         string
+	AcceptType string
+
+	// Exactly one of SQL and SPARQL is set.
+	SQL    *SQLQueryRequest
+	SPARQL *SPARQLQueryRequest
+}
+
+// BatchResult is the outcome of a query of a batch.
+type BatchResult struct {
+	// Body is the whole response, in the accept type of the query.
+	Body []byte
+	Err  error
+	// Duration is the time taken by the request and the reading of the response, not counting the
+	// time spent waiting for a turn.
+	Duration time.Duration
+}
+
+// BatchOptions control how the queries of a batch run.
+type BatchOptions struct {
+	// Concurrency is the most queries that run at once; 4 if zero.
+	Concurrency int
+	// RateLimiter, if set, spaces out the requests. It can be shared between batches, and with other
+	// code, to keep them all under one rate.
+	RateLimiter *RateLimiter
+}
+
+// RateLimiter spaces out events so that at most n happen per period.
+type RateLimiter struct {
+	interval time.Duration
+	mu       sync.Mutex
+	next     time.Time
+}
+
+// NewRateLimiter returns a limiter of n events per period.
+func NewRateLimiter(n int, per time.Duration) *RateLimiter {
+	if n <= 0 {
+		n = 1
+	}
+	return &RateLimiter{interval: per / time.Duration(n)}
+}
+
+// Wait blocks until the next event is allowed.
+func (l *RateLimiter) Wait() {
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	wait := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+	time.Sleep(wait)
+}
+
+func (s *QueryService) executeBatchQuery(q BatchQuery) (body []byte, err error) {
+	var r io.ReadCloser
+	switch {
+	case (q.SQL == nil) == (q.SPARQL == nil):
+		return nil, errors.New("dwapi: a batch query needs exactly one of a SQL and a SPARQL request")
+	case q.SQL != nil:
+		r, err = s.ExecuteSQL(q.Owner, q.ID, q.AcceptType, q.SQL)
+	default:
+		r, err = s.ExecuteSPARQL(q.Owner, q.ID, q.AcceptType, q.SPARQL)
+	}
+	if err != nil {
+		return
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
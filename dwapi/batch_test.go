@@ -0,0 +1,36 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package dwapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter(t *testing.T) {
+	l := NewRateLimiter(4, 100*time.Millisecond)
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		l.Wait()
+	}
+	elapsed := time.Since(start)
+	assert.True(t, elapsed >= 100*time.Millisecond, "elapsed %s", elapsed)
+	assert.True(t, elapsed < time.Second, "elapsed %s", elapsed)
+}
@@ -0,0 +1,357 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package dwapi
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ResultCache keeps the results of SQL and SPARQL queries on disk, so that a query that was already
+// run against the same version of a dataset or project is answered without running it again. Set
+// it as the Cache of the QueryService to use it:
+//
+//	cache, err := dwapi.NewResultCache(filepath.Join(os.TempDir(), "dw-results"))
+//	cache.MaxSize = 1 << 30
+//	cache.TTL = 24 * time.Hour
+//	dw.Query.Cache = cache
+//
+// Results are keyed by the owner and id of the dataset or project, its version, the query with its
+// parameters and options, and the accept type. The version of a project includes the versions of its
+// linked datasets. The version is looked up before each query, which costs an API call, and one
+// more for each dataset linked to a project, and results of other versions are removed from the
+// cache.
+//
+// A ResultCache is safe for concurrent use, but its directory must not be shared by several caches.
+type ResultCache struct {
+	// MaxSize is the total size of the cached results in bytes. The least recently used results are
+	// evicted to stay below it. Zero means no limit.
+	MaxSize int64
+	// MaxEntrySize is the size above which results aren't cached. Zero means MaxSize.
+	MaxEntrySize int64
+	// TTL is how long results are kept. Zero means until they are evicted or invalidated.
+	TTL time.Duration
+
+	dir     string
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+	size    int64
+	now     func() time.Time
+}
+
+// cacheEntry describes a cached result, stored as <key>.body with its metadata in <key>.json.
+type cacheEntry struct {
+	Owner   string    `json:"owner"`
+	ID      string    `json:"id"`
+	Version string    `json:"version"`
+	Created time.Time `json:"created"`
+	Size    int64     `json:"size"`
+	used    time.Time
+}
+
+// NewResultCache returns a cache that keeps results in dir, creating it if needed. Results cached in
+// dir earlier are reused.
+func NewResultCache(dir string) (*ResultCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	c := &ResultCache{dir: dir, entries: make(map[string]*cacheEntry), now: time.Now}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		key := strings.TrimSuffix(filepath.Base(path), ".json")
+		b, err := ioutil.ReadFile(path)
+		var e cacheEntry
+		if err == nil {
+			err = json.Unmarshal(b, &e)
+		}
+		info, statErr := os.Stat(c.path(key, ".body"))
+		if err != nil || statErr != nil || info.Size() != e.Size {
+			c.remove(key)
+			continue
+		}
+		e.used = info.ModTime()
+		c.entries[key] = &e
+		c.size += e.Size
+	}
+	// Results left over from interrupted writes are removed.
+	if tmp, err := filepath.Glob(filepath.Join(dir, "*.tmp*")); err == nil {
+		for _, path := range tmp {
+			os.Remove(path)
+		}
+	}
+	return c, nil
+}
+
+// Len returns the number of cached results.
+func (c *ResultCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// Size returns the total size of the cached results in bytes.
+func (c *ResultCache) Size() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.size
+}
+
+// Invalidate removes the results of a dataset or project.
+func (c *ResultCache) Invalidate(owner, id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, e := range c.entries {
+		if e.Owner == owner && e.ID == id {
+			c.remove(key)
+		}
+	}
+}
+
+// Clear removes all the results.
+func (c *ResultCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.entries {
+		c.remove(key)
+	}
+}
+
+func (c *ResultCache) path(key, ext string) string {
+	return filepath.Join(c.dir, key+ext)
+}
+
+// remove deletes an entry. The mutex must be held, or the cache not yet shared.
+func (c *ResultCache) remove(key string) {
+	if e, ok := c.entries[key]; ok {
+		c.size -= e.Size
+		delete(c.entries, key)
+	}
+	os.Remove(c.path(key, ".json"))
+	os.Remove(c.path(key, ".body"))
+}
+
+// cacheKey identifies the result of a request to an endpoint against a version of a dataset or
+// project.
+func cacheKey(version, endpoint, acceptType string, body []byte) string {
+	h := sha256.New()
+	for _, s := range []string{version, endpoint, acceptType} {
+		io.WriteString(h, s)
+		h.Write([]byte{0})
+	}
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// get opens a cached result, removing the results of other versions of the dataset or project and
+// expired results on the way.
+func (c *ResultCache) get(owner, id, version, key string) (io.ReadCloser, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	for k, e := range c.entries {
+		if e.Owner == owner && e.ID == id && e.Version != version || c.TTL > 0 && now.Sub(e.Created) > c.TTL {
+			c.remove(k)
+		}
+	}
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	f, err := os.Open(c.path(key, ".body"))
+	if err != nil {
+		c.remove(key)
+		return nil, false
+	}
+	e.used = now
+	os.Chtimes(f.Name(), now, now)
+	return f, true
+}
+
+// put stores the result read from tmp, a file that it takes over.
+func (c *ResultCache) put(key string, e *cacheEntry, tmp string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.fits(e.Size) {
+		os.Remove(tmp)
+		return
+	}
+	c.remove(key)
+	meta, err := json.Marshal(e)
+	if err == nil {
+		err = os.Rename(tmp, c.path(key, ".body"))
+	}
+	if err == nil {
+		err = ioutil.WriteFile(c.path(key, ".json"), meta, 0644)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		os.Remove(c.path(key, ".body"))
+		return
+	}
+	e.used = c.now()
+	c.entries[key] = e
+	c.size += e.Size
+	c.evict()
+}
+
+// fits reports whether a result of the given size may be cached.
+func (c *ResultCache) fits(size int64) bool {
+	max := c.MaxEntrySize
+	if max == 0 {
+		max = c.MaxSize
+	}
+	return max == 0 || size <= max
+}
+
+// evict removes the least recently used results until the cache is below its maximum size.
+func (c *ResultCache) evict() {
+	if c.MaxSize == 0 || c.size <= c.MaxSize {
+		return
+	}
+	keys := make([]string, 0, len(c.entries))
+	for key := range c.entries {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return c.entries[keys[i]].used.Before(c.entries[keys[j]].used) })
+	for _, key := range keys {
+		if c.size <= c.MaxSize {
+			return
+		}
+		c.remove(key)
+	}
+}
+
+// cachingReader copies a result into a temporary file as it's read, and caches it once it has been
+// read to the end and closed. Results that are only partly read, or that grow too large, aren't
+// cached.
+type cachingReader struct {
+	io.ReadCloser
+	cache *ResultCache
+	key   string
+	entry *cacheEntry
+	tmp   *os.File
+	done  bool
+}
+
+func (r *cachingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if r.tmp != nil && n > 0 {
+		r.entry.Size += int64(n)
+		if _, werr := r.tmp.Write(p[:n]); werr != nil || !r.cache.fits(r.entry.Size) {
+			r.discard()
+		}
+	}
+	if err == io.EOF {
+		r.done = true
+	}
+	return n, err
+}
+
+func (r *cachingReader) Close() error {
+	err := r.ReadCloser.Close()
+	if r.tmp == nil {
+		return err
+	}
+	if !r.done || err != nil {
+		r.discard()
+		return err
+	}
+	if cerr := r.tmp.Close(); cerr != nil {
+		os.Remove(r.tmp.Name())
+	} else {
+		r.cache.put(r.key, r.entry, r.tmp.Name())
+	}
+	r.tmp = nil
+	return err
+}
+
+func (r *cachingReader) discard() {
+	r.tmp.Close()
+	os.Remove(r.tmp.Name())
+	r.tmp = nil
+}
+
+// cachedRequest answers a query from the cache, or else makes the request and caches its result. The
+// cache is bypassed if the version of the dataset or project can't be looked up.
+func (s *QueryService) cachedRequest(owner, id string, headers *headers, body io.Reader) (io.ReadCloser, error) {
+	c := s.Cache
+	version, ok := s.version(owner, id)
+	if !ok {
+		return s.client.rawRequest(headers, body)
+	}
+	b, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	key := cacheKey(version, headers.Endpoint, headers.AcceptType, b)
+	if r, ok := c.get(owner, id, version, key); ok {
+		return r, nil
+	}
+
+	r, err := s.client.rawRequest(headers, bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	tmp, err := ioutil.TempFile(c.dir, key+".tmp")
+	if err != nil {
+		return r, nil
+	}
+	return &cachingReader{
+		ReadCloser: r,
+		cache:      c,
+		key:        key,
+		entry:      &cacheEntry{Owner: owner, ID: id, Version: version, Created: c.now()},
+		tmp:        tmp,
+	}, nil
+}
+
+// version looks up the version of a dataset, or of a project with the versions of its linked
+// datasets, which change without changing the project's version.
+func (s *QueryService) version(owner, id string) (string, bool) {
+	if d, err := s.client.Dataset.Retrieve(owner, id); err == nil && d.Version != "" {
+		return d.Version, true
+	}
+	p, err := s.client.Project.Retrieve(owner, id)
+	if err != nil || p.Version == "" {
+		return "", false
+	}
+	versions := []string{p.Version}
+	for _, l := range p.LinkedDatasets {
+		d, err := s.client.Dataset.Retrieve(l.Owner, l.ID)
+		if err != nil || d.Version == "" {
+			return "", false
+		}
+		versions = append(versions, l.Owner+"/"+l.ID+"@"+d.Version)
+	}
+	sort.Strings(versions[1:])
+	return strings.Join(versions, " "), true
+}
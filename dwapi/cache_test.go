@@ -0,0 +1,215 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package dwapi
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// cacheServer serves a dataset whose version can be changed, and counts the SQL queries run against
+// it. Each query returns its own text.
+type cacheServer struct {
+	version string
+	queries int
+}
+
+func setupCache(t *testing.T) (*cacheServer, *ResultCache, func()) {
+	setup()
+	dir, err := ioutil.TempDir("", "dwapi-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache, err := NewResultCache(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dw.Query.Cache = cache
+
+	s := &cacheServer{version: "v1"}
+	mux.HandleFunc("/datasets/owner/dataset", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"owner": "owner", "id": "dataset", "version": %q}`, s.version)
+	})
+	mux.HandleFunc("/projects/owner/project", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"owner": "owner", "id": "project", "version": "p1",
+			"linkedDatasets": [{"owner": "owner", "id": "dataset", "version": "v1"}]}`)
+	})
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		s.queries++
+		b, _ := ioutil.ReadAll(r.Body)
+		fmt.Fprintf(w, "%s %s", r.Header.Get("Accept"), strings.TrimSpace(string(b)))
+	}
+	mux.HandleFunc("/sql/", handler)
+	mux.HandleFunc("/sparql/", handler)
+	return s, cache, func() {
+		teardown()
+		os.RemoveAll(dir)
+	}
+}
+
+func readQuery(t *testing.T, owner, id, acceptType string, req *SQLQueryRequest) string {
+	r, err := dw.Query.ExecuteSQL(owner, id, acceptType, req)
+	if !assert.NoError(t, err) {
+		return ""
+	}
+	defer r.Close()
+	b, err := ioutil.ReadAll(r)
+	assert.NoError(t, err)
+	return string(b)
+}
+
+func TestResultCache(t *testing.T) {
+	s, cache, done := setupCache(t)
+	defer done()
+
+	req := &SQLQueryRequest{Query: "SELECT * FROM t"}
+	want := `text/csv {"query":"SELECT * FROM t"}`
+	assert.Equal(t, want, readQuery(t, "owner", "dataset", "text/csv", req))
+	assert.Equal(t, want, readQuery(t, "owner", "dataset", "text/csv", req))
+	assert.Equal(t, 1, s.queries)
+	assert.Equal(t, 1, cache.Len())
+	assert.Equal(t, int64(len(want)), cache.Size())
+
+	// Other accept types, parameters and query languages are cached separately.
+	readQuery(t, "owner", "dataset", "application/json", req)
+	readQuery(t, "owner", "dataset", "text/csv", &SQLQueryRequest{Query: req.Query, Parameters: map[string]string{"$p": "1"}})
+	r, err := dw.Query.ExecuteSPARQL("owner", "dataset", "text/csv", &SPARQLQueryRequest{Query: req.Query})
+	if assert.NoError(t, err) {
+		ioutil.ReadAll(r)
+		r.Close()
+	}
+	assert.Equal(t, 4, s.queries)
+	assert.Equal(t, 4, cache.Len())
+
+	// A new version of the dataset invalidates its results.
+	s.version = "v2"
+	assert.Equal(t, want, readQuery(t, "owner", "dataset", "text/csv", req))
+	assert.Equal(t, 5, s.queries)
+	assert.Equal(t, 1, cache.Len())
+
+	// Projects are versioned too, with the versions of their linked datasets.
+	readQuery(t, "owner", "project", "text/csv", req)
+	readQuery(t, "owner", "project", "text/csv", req)
+	assert.Equal(t, 6, s.queries)
+	s.version = "v3"
+	readQuery(t, "owner", "project", "text/csv", req)
+	assert.Equal(t, 7, s.queries)
+
+	cache.Invalidate("owner", "project")
+	assert.Equal(t, 1, cache.Len())
+	cache.Clear()
+	assert.Equal(t, 0, cache.Len())
+	assert.Equal(t, int64(0), cache.Size())
+}
+
+func TestResultCache_Uncached(t *testing.T) {
+	s, cache, done := setupCache(t)
+	defer done()
+	req := &SQLQueryRequest{Query: "SELECT * FROM t"}
+
+	// Without a version to key them by, results aren't cached.
+	readQuery(t, "owner", "unknown", "text/csv", req)
+	assert.Equal(t, 0, cache.Len())
+
+	// Nor are results that weren't read to the end.
+	r, err := dw.Query.ExecuteSQL("owner", "dataset", "text/csv", req)
+	if assert.NoError(t, err) {
+		r.Read(make([]byte, 4))
+		r.Close()
+	}
+	assert.Equal(t, 0, cache.Len())
+
+	// Nor results that are too large.
+	cache.MaxEntrySize = 10
+	readQuery(t, "owner", "dataset", "text/csv", req)
+	assert.Equal(t, 0, cache.Len())
+	assert.Equal(t, 3, s.queries)
+
+	files, _ := ioutil.ReadDir(cache.dir)
+	assert.Empty(t, files)
+}
+
+func TestResultCache_Eviction(t *testing.T) {
+	s, cache, done := setupCache(t)
+	defer done()
+
+	now := time.Date(2018, 8, 3, 0, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time {
+		now = now.Add(time.Minute)
+		return now
+	}
+	queries := []*SQLQueryRequest{{Query: "SELECT 1"}, {Query: "SELECT 2"}, {Query: "SELECT 3"}}
+	size := int64(len(readQuery(t, "owner", "dataset", "text/csv", queries[0])))
+	cache.MaxSize = 2 * size
+	readQuery(t, "owner", "dataset", "text/csv", queries[1])
+	readQuery(t, "owner", "dataset", "text/csv", queries[0])
+
+	// The least recently used result is evicted.
+	readQuery(t, "owner", "dataset", "text/csv", queries[2])
+	assert.Equal(t, 2, cache.Len())
+	assert.Equal(t, 2*size, cache.Size())
+	n := s.queries
+	readQuery(t, "owner", "dataset", "text/csv", queries[0])
+	readQuery(t, "owner", "dataset", "text/csv", queries[2])
+	assert.Equal(t, n, s.queries)
+	readQuery(t, "owner", "dataset", "text/csv", queries[1])
+	assert.Equal(t, n+1, s.queries)
+
+	// Expired results are removed.
+	cache.TTL = time.Hour
+	now = now.Add(2 * time.Hour)
+	readQuery(t, "owner", "dataset", "text/csv", queries[0])
+	assert.Equal(t, n+2, s.queries)
+	assert.Equal(t, 1, cache.Len())
+}
+
+func TestNewResultCache_Reload(t *testing.T) {
+	s, cache, done := setupCache(t)
+	defer done()
+
+	req := &SQLQueryRequest{Query: "SELECT * FROM t"}
+	readQuery(t, "owner", "dataset", "text/csv", req)
+	readQuery(t, "owner", "dataset", "application/json", req)
+	ioutil.WriteFile(cache.path("leftover", ".body.tmp123"), []byte("x"), 0644)
+
+	// A broken entry is dropped when the cache is reopened.
+	for key := range cache.entries {
+		os.Remove(cache.path(key, ".body"))
+		break
+	}
+
+	reopened, err := NewResultCache(cache.dir)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, 1, reopened.Len())
+	dw.Query.Cache = reopened
+	readQuery(t, "owner", "dataset", "text/csv", req)
+	readQuery(t, "owner", "dataset", "application/json", req)
+	assert.Equal(t, 3, s.queries)
+
+	files, _ := ioutil.ReadDir(cache.dir)
+	assert.Len(t, files, 4)
+}
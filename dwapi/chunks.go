@@ -0,0 +1,266 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package dwapi
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/datadotworld/dwapi-go/rdf"
+)
+
+// defaultChunkSize is the number of rows of a chunk if ChunkOptions doesn't say.
+const defaultChunkSize = 10000
+
+// ChunkOptions control how `QueryService.ExecuteSQLChunked` splits a query into chunks. The rows must
+// have a stable order for the chunks to neither skip nor repeat rows: either OrderBy columns that
+// sort them uniquely, paged with LIMIT and OFFSET, or a KeyColumn of unique values, paged by the last
+// key read. Keyset paging stays fast deep into the results, but its chunks can't be fetched in
+// parallel.
+type ChunkOptions struct {
+	// ChunkSize is the number of rows fetched by each request; 10000 if zero.
+	ChunkSize int
+	// OrderBy are the columns that order the rows for LIMIT/OFFSET chunks.
+	OrderBy []string
+	// KeyColumn, if set, is the column of unique values whose order pages the rows instead. A null
+	// key is an error, as the rows after it can't be selected by it.
+	KeyColumn string
+	// Parallel is the number of LIMIT/OFFSET chunks fetched at once; chunks are fetched one at a time
+	// if it's zero or one.
+	Parallel int
+	// Resume, if set, continues an earlier run from its checkpoint.
+	Resume *Checkpoint
+}
+
+// Checkpoint is how far the rows of a chunked query have been read. It can be saved, e.g. as JSON, and
+// passed back as ChunkOptions.Resume to continue an interrupted run after the last row read.
+type Checkpoint struct {
+	// Rows is the number of rows read.
+	Rows int64 `json:"rows"`
+	// LastKey is the value of the key column of the last row read, as an RDF literal, for keyset
+	// chunks.
+	LastKey string `json:"lastKey,omitempty"`
+}
+
+// ChunkedRows are the rows of a query fetched in chunks, read as one result.
+type ChunkedRows struct {
+	*Rows
+	chunks *chunkReader
+}
+
+// Checkpoint returns how far the rows have been read, counting the current row.
+func (r *ChunkedRows) Checkpoint() Checkpoint {
+	c := Checkpoint{Rows: r.chunks.start.Rows + int64(r.n), LastKey: r.chunks.start.LastKey}
+	if r.chunks.key >= 0 && r.row != nil {
+		c.LastKey = literalOf(r.row[r.chunks.key])
+	}
+	return c
+}
+
+// literalOf writes a value of results as an RDF literal, for a query parameter.
+func literalOf(t *ResultTerm) string {
+	if t == nil {
+		return ""
+	}
+	if t.Datatype == "" || t.Datatype == xsd+"string" {
+		return rdf.Quote(t.Value)
+	}
+	return rdf.Quote(t.Value) + "^^<" + t.Datatype + ">"
+}
+
+type chunk struct {
+	rows [][]*ResultTerm
+	err  error
+}
+
+// chunkReader reads the rows of a query a chunk at a time, keeping up to Parallel chunks in flight
+// for LIMIT/OFFSET chunks.
+type chunkReader struct {
+	fetch func(offset int64, lastKey string) (columns []string, rows [][]*ResultTerm, err error)
+	opts  ChunkOptions
+	start Checkpoint
+	cols  []string
+	key   int
+
+	buf     [][]*ResultTerm
+	offset  int64
+	lastKey string
+	pending []chan chunk
+	last    bool
+	closed  bool
+}
+
+func (c *chunkReader) columns() []string {
+	return c.cols
+}
+
+func (c *chunkReader) next() ([]*ResultTerm, error) {
+	for len(c.buf) == 0 {
+		if c.last || c.closed {
+			return nil, io.EOF
+		}
+		rows, err := c.nextChunk()
+		if err != nil {
+			return nil, err
+		}
+		if err = c.take(rows); err != nil {
+			return nil, err
+		}
+	}
+	row := c.buf[0]
+	c.buf = c.buf[1:]
+	return row, nil
+}
+
+// take makes rows the current chunk.
+func (c *chunkReader) take(rows [][]*ResultTerm) error {
+	if c.key >= 0 {
+		for _, row := range rows {
+			if row[c.key] == nil {
+				return fmt.Errorf("dwapi: key column %q is null in a row, so the rows can't be paged by it",
+					c.opts.KeyColumn)
+			}
+		}
+		if len(rows) > 0 {
+			c.lastKey = literalOf(rows[len(rows)-1][c.key])
+		}
+	}
+	c.buf = rows
+	if len(rows) < c.opts.ChunkSize {
+		c.last = true
+	}
+	return nil
+}
+
+func (c *chunkReader) nextChunk() ([][]*ResultTerm, error) {
+	if c.key >= 0 {
+		_, rows, err := c.fetch(0, c.lastKey)
+		return rows, err
+	}
+	for len(c.pending) < c.opts.Parallel {
+		result := make(chan chunk, 1)
+		go func(offset int64) {
+			_, rows, err := c.fetch(offset, "")
+			result <- chunk{rows: rows, err: err}
+		}(c.offset)
+		c.pending = append(c.pending, result)
+		c.offset += int64(c.opts.ChunkSize)
+	}
+	result := <-c.pending[0]
+	c.pending = c.pending[1:]
+	return result.rows, result.err
+}
+
+// Close stops fetching chunks. Chunks in flight are left to finish.
+func (c *chunkReader) Close() error {
+	c.closed = true
+	return nil
+}
+
+// chunkQuery rewrites a query to fetch one chunk of its rows, ordered as the options say.
+func chunkQuery(body *SQLQueryRequest, opts ChunkOptions, offset int64, lastKey string) *SQLQueryRequest {
+	query := strings.TrimRight(strings.TrimSpace(body.Query), ";")
+	req := *body
+	req.Query = "SELECT * FROM (" + query + ") AS chunk"
+	if opts.KeyColumn != "" {
+		if lastKey != "" {
+			req.Query += " WHERE " + SQLIdent(opts.KeyColumn) + " > ?"
+			req.Parameters = make(map[string]string, len(body.Parameters)+1)
+			for k, v := range body.Parameters {
+				req.Parameters[k] = v
+			}
+			req.Parameters["$data_world_param"+strconv.Itoa(CountPlaceholders(query))] = lastKey
+		}
+		req.Query += " ORDER BY " + SQLIdent(opts.KeyColumn) + " LIMIT " + strconv.Itoa(opts.ChunkSize)
+		return &req
+	}
+	order := make([]string, len(opts.OrderBy))
+	for i, c := range opts.OrderBy {
+		order[i] = SQLIdent(c)
+	}
+	req.Query += " ORDER BY " + strings.Join(order, ", ") + " LIMIT " + strconv.Itoa(opts.ChunkSize)
+	if offset > 0 {
+		req.Query += " OFFSET " + strconv.FormatInt(offset, 10)
+	}
+	return &req
+}
+
+// fetchChunk runs the query of a chunk and reads all its rows.
+func (s *QueryService) fetchChunk(owner, id string, body *SQLQueryRequest) (
+	columns []string, rows [][]*ResultTerm, err error) {
+	r, err := s.ExecuteSQLRows(owner, id, "", body)
+	if err != nil {
+		return
+	}
+	defer r.Close()
+	for r.Next() {
+		rows = append(rows, r.row)
+	}
+	return r.Columns(), rows, r.Err()
+}
+
+// newChunkReader checks the options and fetches the first chunk, to learn the columns.
+func (s *QueryService) newChunkReader(owner, id string, body *SQLQueryRequest, options *ChunkOptions) (
+	*chunkReader, error) {
+	var opts ChunkOptions
+	if options != nil {
+		opts = *options
+	}
+	if opts.ChunkSize <= 0 {
+		opts.ChunkSize = defaultChunkSize
+	}
+	if opts.Parallel <= 0 || opts.KeyColumn != "" {
+		opts.Parallel = 1
+	}
+	if len(opts.OrderBy) == 0 && opts.KeyColumn == "" {
+		return nil, errors.New("dwapi: chunked queries need OrderBy columns or a KeyColumn for a stable order")
+	}
+
+	c := &chunkReader{opts: opts, key: -1}
+	if opts.Resume != nil {
+		c.start = *opts.Resume
+	}
+	c.offset, c.lastKey = c.start.Rows, c.start.LastKey
+	c.fetch = func(offset int64, lastKey string) ([]string, [][]*ResultTerm, error) {
+		return s.fetchChunk(owner, id, chunkQuery(body, opts, offset, lastKey))
+	}
+
+	columns, rows, err := c.fetch(c.offset, c.lastKey)
+	if err != nil {
+		return nil, err
+	}
+	c.cols = columns
+	c.offset += int64(opts.ChunkSize)
+	if opts.KeyColumn != "" {
+		for i, col := range columns {
+			if col == opts.KeyColumn {
+				c.key = i
+			}
+		}
+		if c.key < 0 {
+			return nil, fmt.Errorf("dwapi: key column %q is not a column of the results", opts.KeyColumn)
+		}
+	}
+	if err = c.take(rows); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
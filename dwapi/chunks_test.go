@@ -0,0 +1,182 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package dwapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// handleChunks serves the chunks of a table of n rows with ids 1 to n, counting the requests.
+func handleChunks(t *testing.T, n int, requests *int32) {
+	window := regexp.MustCompile(`LIMIT (\d+)(?: OFFSET (\d+))?$`)
+	mux.HandleFunc("/sql/acme/big", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		var body SQLQueryRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		m := window.FindStringSubmatch(body.Query)
+		if !assert.NotNil(t, m, body.Query) {
+			return
+		}
+		limit, _ := strconv.Atoi(m[1])
+		offset, _ := strconv.Atoi(m[2])
+		first := offset + 1
+		if strings.Contains(body.Query, "`id` > ?") {
+			// The last key is the last parameter.
+			key := body.Parameters["$data_world_param"+strconv.Itoa(len(body.Parameters)-1)]
+			var after int
+			fmt.Sscanf(key, `"%d"`, &after)
+			first = after + 1
+		}
+		var bindings []string
+		for id := first; id <= n && id < first+limit; id++ {
+			bindings = append(bindings, fmt.Sprintf(`{"id": {"type": "literal", "value": "%d", `+
+				`"datatype": "http://www.w3.org/2001/XMLSchema#integer"}, "name": {"type": "literal", "value": "row %d"}}`, id, id))
+		}
+		fmt.Fprintf(w, `{"head": {"vars": ["id", "name"]}, "results": {"bindings": [%s]}}`, strings.Join(bindings, ","))
+	})
+}
+
+func readIDs(t *testing.T, rows *ChunkedRows, most int) (ids []int) {
+	for len(ids) < most && rows.Next() {
+		var id int
+		var name string
+		if assert.NoError(t, rows.Scan(&id, &name)) {
+			ids = append(ids, id)
+		}
+	}
+	assert.NoError(t, rows.Err())
+	return
+}
+
+func span(from, to int) (ids []int) {
+	for i := from; i <= to; i++ {
+		ids = append(ids, i)
+	}
+	return
+}
+
+func TestChunkQuery(t *testing.T) {
+	body := &SQLQueryRequest{Query: "SELECT * FROM sales WHERE region = ?;",
+		Parameters: map[string]string{"$data_world_param0": `"EMEA"`}}
+
+	got := chunkQuery(body, ChunkOptions{ChunkSize: 100, OrderBy: []string{"day", "order id"}}, 200, "")
+	assert.Equal(t, "SELECT * FROM (SELECT * FROM sales WHERE region = ?) AS chunk ORDER BY `day`, `order id` LIMIT 100 OFFSET 200",
+		got.Query)
+	assert.Equal(t, body.Parameters, got.Parameters)
+
+	got = chunkQuery(body, ChunkOptions{ChunkSize: 100, KeyColumn: "id"}, 0, `"7"^^<http://www.w3.org/2001/XMLSchema#integer>`)
+	assert.Equal(t, "SELECT * FROM (SELECT * FROM sales WHERE region = ?) AS chunk WHERE `id` > ? ORDER BY `id` LIMIT 100",
+		got.Query)
+	assert.Equal(t, map[string]string{
+		"$data_world_param0": `"EMEA"`,
+		"$data_world_param1": `"7"^^<http://www.w3.org/2001/XMLSchema#integer>`,
+	}, got.Parameters)
+	assert.Len(t, body.Parameters, 1)
+}
+
+func TestChunkedRows_Checkpoint(t *testing.T) {
+	setup()
+	defer teardown()
+	var requests int32
+	handleChunks(t, 25, &requests)
+	body := &SQLQueryRequest{Query: "SELECT id, name FROM big WHERE id > ?", Parameters: map[string]string{"$data_world_param0": `"0"`}}
+
+	for _, opts := range []ChunkOptions{
+		{ChunkSize: 10, OrderBy: []string{"id"}},
+		{ChunkSize: 10, OrderBy: []string{"id"}, Parallel: 3},
+		{ChunkSize: 10, KeyColumn: "id"},
+	} {
+		rows, err := dw.Query.ExecuteSQLChunked("acme", "big", body, &opts)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		assert.Equal(t, span(1, 13), readIDs(t, rows, 13))
+		checkpoint := rows.Checkpoint()
+		assert.Equal(t, int64(13), checkpoint.Rows)
+		rows.Close()
+
+		// Resume after the interruption.
+		opts.Resume = &checkpoint
+		rows, err = dw.Query.ExecuteSQLChunked("acme", "big", body, &opts)
+		if assert.NoError(t, err) {
+			assert.Equal(t, span(14, 25), readIDs(t, rows, 100), "%+v", opts)
+			assert.Equal(t, int64(25), rows.Checkpoint().Rows)
+			rows.Close()
+		}
+	}
+}
+
+func TestChunkedRows_NullKey(t *testing.T) {
+	setup()
+	defer teardown()
+	mux.HandleFunc("/sql/acme/sparse", func(w http.ResponseWriter, r *http.Request) {
+		var body SQLQueryRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		bindings := `{"id": {"type": "literal", "value": "1"}}, {"id": {"type": "literal", "value": "2"}}`
+		if strings.Contains(body.Query, "`id` > ?") {
+			bindings = `{}`
+		}
+		fmt.Fprintf(w, `{"head": {"vars": ["id"]}, "results": {"bindings": [%s]}}`, bindings)
+	})
+
+	rows, err := dw.Query.ExecuteSQLChunked("acme", "sparse", &SQLQueryRequest{Query: "SELECT id FROM sparse"},
+		&ChunkOptions{ChunkSize: 2, KeyColumn: "id"})
+	if assert.NoError(t, err) {
+		defer rows.Close()
+		n := 0
+		for rows.Next() {
+			n++
+		}
+		assert.Equal(t, 2, n)
+		assert.EqualError(t, rows.Err(), `dwapi: key column "id" is null in a row, so the rows can't be paged by it`)
+	}
+}
+
+func TestRowsReader(t *testing.T) {
+	setup()
+	defer teardown()
+	var requests int32
+	handleChunks(t, 5, &requests)
+
+	rows, err := dw.Query.ExecuteSQLChunked("acme", "big", &SQLQueryRequest{Query: "SELECT id, name FROM big"},
+		&ChunkOptions{ChunkSize: 2, KeyColumn: "id"})
+	if !assert.NoError(t, err) {
+		return
+	}
+	r, err := RowsReader(rows.Rows, FormatCSV)
+	if assert.NoError(t, err) {
+		got, err := ioutil.ReadAll(r)
+		assert.NoError(t, err)
+		assert.Equal(t, "id,name\n1,row 1\n2,row 2\n3,row 3\n4,row 4\n5,row 5\n", string(got))
+		r.Close()
+	}
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+
+	_, err = RowsReader(rows.Rows, "image/png")
+	assert.EqualError(t, err, `dwapi: can't convert results to type "image/png"`)
+}
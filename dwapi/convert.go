@@ -0,0 +1,360 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package dwapi
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"strconv"
+	"strings"
+)
+
+// ConvertResults converts query results from one content type to another as they're read, e.g. to
+// serve the CSV of `QueryService.ExecuteSQL` as a Markdown table. The results are read as for
+// `DecodeResults`; they're written as CSV, TSV, a JSON array of objects, JSON lines
+// (application/x-ndjson), a Markdown table (text/markdown) or an HTML table (text/html). Closing the
+// returned reader closes r if it's an io.Closer.
+//
+// JSON output keeps numbers and booleans that have a datatype, and writes other values as strings.
+// TSV escapes tabs, line breaks and backslashes in values as \t, \n, \r and \\.
+func ConvertResults(r io.Reader, from, to Format) (io.ReadCloser, error) {
+	reader, err := newResultReader(r, string(from))
+	if err != nil {
+		return nil, err
+	}
+	newWriter, err := resultWriterFor(to)
+	if err != nil {
+		return nil, err
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(convertResults(reader, newWriter(pw)))
+	}()
+	closer, _ := r.(io.Closer)
+	return &convertedResults{PipeReader: pr, src: closer}, nil
+}
+
+// RowsReader writes rows out in one of the formats of ConvertResults as they're read, e.g. to
+// save the rows of `QueryService.ExecuteSQLChunked` as one file. The rows are closed once they've been
+// written, or once the reader is closed.
+func RowsReader(rows *Rows, format Format) (io.ReadCloser, error) {
+	newWriter, err := resultWriterFor(format)
+	if err != nil {
+		return nil, err
+	}
+	pr, pw := io.Pipe()
+	go func() {
+		// The rows are closed here rather than by Close, which would race with reading them. Once
+		// the reader is closed, the next write fails and ends the loop.
+		defer rows.Close()
+		pw.CloseWithError(writeRows(rows, newWriter(pw)))
+	}()
+	return pr, nil
+}
+
+func writeRows(rows *Rows, w resultWriter) error {
+	if err := w.header(rows.Columns()); err != nil {
+		return err
+	}
+	for rows.Next() {
+		if err := w.row(rows.row); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	return w.end()
+}
+
+type convertedResults struct {
+	*io.PipeReader
+	src io.Closer
+}
+
+// Close stops the conversion and closes the source of the results.
+func (c *convertedResults) Close() error {
+	c.PipeReader.Close()
+	if c.src != nil {
+		return c.src.Close()
+	}
+	return nil
+}
+
+func convertResults(reader resultReader, w resultWriter) error {
+	if err := w.header(reader.columns()); err != nil {
+		return err
+	}
+	for {
+		row, err := reader.next()
+		if err == io.EOF {
+			return w.end()
+		}
+		if err != nil {
+			return err
+		}
+		if err = w.row(row); err != nil {
+			return err
+		}
+	}
+}
+
+// resultWriter writes query results a row at a time, with nil for unbound values.
+type resultWriter interface {
+	header(columns []string) error
+	row(values []*ResultTerm) error
+	end() error
+}
+
+func resultWriterFor(format Format) (func(io.Writer) resultWriter, error) {
+	mediaType, _, err := mime.ParseMediaType(string(format))
+	if err != nil {
+		mediaType = string(format)
+	}
+	switch Format(mediaType) {
+	case FormatCSV:
+		return func(w io.Writer) resultWriter { return &csvWriter{w: csv.NewWriter(w)} }, nil
+	case FormatTSV:
+		return func(w io.Writer) resultWriter { return &tsvWriter{w: bufio.NewWriter(w)} }, nil
+	case FormatJSON:
+		return func(w io.Writer) resultWriter { return &jsonWriter{w: bufio.NewWriter(w), array: true} }, nil
+	case FormatNDJSON, FormatJSONLines:
+		return func(w io.Writer) resultWriter { return &jsonWriter{w: bufio.NewWriter(w)} }, nil
+	case FormatMarkdown:
+		return func(w io.Writer) resultWriter { return &markdownWriter{w: bufio.NewWriter(w)} }, nil
+	case FormatHTML:
+		return func(w io.Writer) resultWriter { return &htmlWriter{w: bufio.NewWriter(w)} }, nil
+	}
+	return nil, fmt.Errorf("dwapi: can't convert results to type %q", format)
+}
+
+// value returns the lexical form of a value, or "" for an unbound one.
+func (t *ResultTerm) value() string {
+	if t == nil {
+		return ""
+	}
+	return t.Value
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvWriter) header(columns []string) error {
+	return c.w.Write(columns)
+}
+
+func (c *csvWriter) row(values []*ResultTerm) error {
+	record := make([]string, len(values))
+	for i, t := range values {
+		record[i] = t.value()
+	}
+	return c.w.Write(record)
+}
+
+func (c *csvWriter) end() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+type tsvWriter struct {
+	w *bufio.Writer
+}
+
+var tsvEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+func (t *tsvWriter) header(columns []string) error {
+	return t.line(columns)
+}
+
+func (t *tsvWriter) row(values []*ResultTerm) error {
+	fields := make([]string, len(values))
+	for i, v := range values {
+		fields[i] = v.value()
+	}
+	return t.line(fields)
+}
+
+func (t *tsvWriter) line(fields []string) error {
+	for i, f := range fields {
+		if i > 0 {
+			t.w.WriteByte('\t')
+		}
+		t.w.WriteString(tsvEscaper.Replace(f))
+	}
+	return t.w.WriteByte('\n')
+}
+
+func (t *tsvWriter) end() error {
+	return t.w.Flush()
+}
+
+// jsonWriter writes rows as objects whose keys are in the order of the columns, in an array or one
+// per line.
+type jsonWriter struct {
+	w       *bufio.Writer
+	array   bool
+	columns []string
+	n       int
+}
+
+func (j *jsonWriter) header(columns []string) error {
+	j.columns = columns
+	if j.array {
+		_, err := j.w.WriteString("[")
+		return err
+	}
+	return nil
+}
+
+func (j *jsonWriter) row(values []*ResultTerm) error {
+	switch {
+	case j.array && j.n > 0:
+		j.w.WriteString(",\n")
+	case j.array:
+		j.w.WriteString("\n")
+	}
+	j.n++
+	j.w.WriteByte('{')
+	for i, column := range j.columns {
+		if i > 0 {
+			j.w.WriteByte(',')
+		}
+		j.w.Write(jsonString(column))
+		j.w.WriteByte(':')
+		var t *ResultTerm
+		if i < len(values) {
+			t = values[i]
+		}
+		j.w.Write(jsonValue(t))
+	}
+	j.w.WriteByte('}')
+	if !j.array {
+		j.w.WriteByte('\n')
+	}
+	return nil
+}
+
+func (j *jsonWriter) end() error {
+	if j.array {
+		if j.n > 0 {
+			j.w.WriteString("\n")
+		}
+		j.w.WriteString("]\n")
+	}
+	return j.w.Flush()
+}
+
+// jsonValue writes numbers and booleans as such if their datatype says so and JSON can represent them.
+func jsonValue(t *ResultTerm) []byte {
+	if t == nil {
+		return []byte("null")
+	}
+	switch shortDatatype(t.Datatype) {
+	case "xsd:boolean":
+		if b, err := strconv.ParseBool(t.Value); err == nil {
+			return []byte(strconv.FormatBool(b))
+		}
+	case "xsd:integer", "xsd:long", "xsd:int", "xsd:short", "xsd:decimal", "xsd:double", "xsd:float":
+		if json.Valid([]byte(t.Value)) {
+			if _, err := strconv.ParseFloat(t.Value, 64); err == nil {
+				return []byte(t.Value)
+			}
+		}
+	}
+	return jsonString(t.Value)
+}
+
+// jsonString quotes a string for JSON, leaving the characters of HTML as they are.
+func jsonString(s string) []byte {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	return bytes.TrimSuffix(b.Bytes(), []byte("\n"))
+}
+
+type markdownWriter struct {
+	w *bufio.Writer
+}
+
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, "|", `\|`, "\r\n", "<br>", "\n", "<br>", "\r", "<br>")
+
+func (m *markdownWriter) header(columns []string) error {
+	names := make([]string, len(columns))
+	rule := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = markdownEscaper.Replace(c)
+		rule[i] = "---"
+	}
+	if err := m.line(names); err != nil {
+		return err
+	}
+	return m.line(rule)
+}
+
+func (m *markdownWriter) row(values []*ResultTerm) error {
+	cells := make([]string, len(values))
+	for i, t := range values {
+		cells[i] = markdownEscaper.Replace(t.value())
+	}
+	return m.line(cells)
+}
+
+func (m *markdownWriter) line(cells []string) error {
+	_, err := m.w.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	return err
+}
+
+func (m *markdownWriter) end() error {
+	return m.w.Flush()
+}
+
+type htmlWriter struct {
+	w *bufio.Writer
+}
+
+func (h *htmlWriter) header(columns []string) error {
+	h.w.WriteString("<table>\n<thead>\n<tr>")
+	for _, c := range columns {
+		h.w.WriteString("<th>" + html.EscapeString(c) + "</th>")
+	}
+	_, err := h.w.WriteString("</tr>\n</thead>\n<tbody>\n")
+	return err
+}
+
+func (h *htmlWriter) row(values []*ResultTerm) error {
+	h.w.WriteString("<tr>")
+	for _, t := range values {
+		h.w.WriteString("<td>" + html.EscapeString(t.value()) + "</td>")
+	}
+	_, err := h.w.WriteString("</tr>\n")
+	return err
+}
+
+func (h *htmlWriter) end() error {
+	h.w.WriteString("</tbody>\n</table>\n")
+	return h.w.Flush()
+}
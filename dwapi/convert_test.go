@@ -0,0 +1,109 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package dwapi
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const convertCSV = "name,visits,note\nAda,3,\"likes | pipes, <tags>\"\nGrace,,\"two\nlines\"\n"
+
+func TestConvertResults(t *testing.T) {
+	tests := map[Format]string{
+		FormatCSV: convertCSV,
+		FormatTSV: "name\tvisits\tnote\nAda\t3\tlikes | pipes, <tags>\nGrace\t\ttwo\\nlines\n",
+		FormatJSON: `[
+{"name":"Ada","visits":"3","note":"likes | pipes, <tags>"},
+{"name":"Grace","visits":null,"note":"two\nlines"}
+]
+`,
+		FormatNDJSON + "; charset=utf-8": `{"name":"Ada","visits":"3","note":"likes | pipes, <tags>"}
+{"name":"Grace","visits":null,"note":"two\nlines"}
+`,
+		FormatMarkdown: "| name | visits | note |\n| --- | --- | --- |\n| Ada | 3 | likes \\| pipes, <tags> |\n| Grace |  | two<br>lines |\n",
+		FormatHTML: "<table>\n<thead>\n<tr><th>name</th><th>visits</th><th>note</th></tr>\n</thead>\n<tbody>\n" +
+			"<tr><td>Ada</td><td>3</td><td>likes | pipes, &lt;tags&gt;</td></tr>\n" +
+			"<tr><td>Grace</td><td></td><td>two\nlines</td></tr>\n</tbody>\n</table>\n",
+	}
+	for to, want := range tests {
+		r, err := ConvertResults(strings.NewReader(convertCSV), FormatCSV, to)
+		if assert.NoError(t, err, to) {
+			got, err := ioutil.ReadAll(r)
+			assert.NoError(t, err, to)
+			assert.Equal(t, want, string(got), to)
+			assert.NoError(t, r.Close())
+		}
+	}
+}
+
+func TestConvertResults_MarkdownHeader(t *testing.T) {
+	r, err := ConvertResults(strings.NewReader("\"a|b\",\"two\nlines\"\n1,2\n"), FormatCSV, FormatMarkdown)
+	if assert.NoError(t, err) {
+		got, err := ioutil.ReadAll(r)
+		assert.NoError(t, err)
+		assert.Equal(t, "| a\\|b | two<br>lines |\n| --- | --- |\n| 1 | 2 |\n", string(got))
+		r.Close()
+	}
+}
+
+func TestConvertResults_RoundTrip(t *testing.T) {
+	// TSV and JSON lines convert back into the same CSV.
+	for _, via := range []Format{FormatTSV, FormatNDJSON, FormatJSON} {
+		r, err := ConvertResults(strings.NewReader(convertCSV), FormatCSV, via)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		back, err := ConvertResults(r, via, FormatCSV)
+		if assert.NoError(t, err, via) {
+			got, err := ioutil.ReadAll(back)
+			assert.NoError(t, err, via)
+			assert.Equal(t, convertCSV, string(got), via)
+		}
+	}
+}
+
+func TestConvertResults_Types(t *testing.T) {
+	sparql := `{"head": {"vars": ["n", "ok", "big", "label"]}, "results": {"bindings": [
+		{"n": {"type": "literal", "value": "42", "datatype": "http://www.w3.org/2001/XMLSchema#integer"},
+		 "ok": {"type": "literal", "value": "true", "datatype": "http://www.w3.org/2001/XMLSchema#boolean"},
+		 "big": {"type": "literal", "value": "1.", "datatype": "http://www.w3.org/2001/XMLSchema#decimal"},
+		 "label": {"type": "literal", "value": "x", "xml:lang": "en"}}
+	]}}`
+	r, err := ConvertResults(strings.NewReader(sparql), FormatSPARQLJSON, FormatNDJSON)
+	if assert.NoError(t, err) {
+		got, _ := ioutil.ReadAll(r)
+		assert.Equal(t, `{"n":42,"ok":true,"big":"1.","label":"x"}`+"\n", string(got))
+	}
+}
+
+func TestConvertResults_Errors(t *testing.T) {
+	_, err := ConvertResults(strings.NewReader(convertCSV), "text/plain", FormatCSV)
+	assert.EqualError(t, err, `dwapi: can't decode results of type "text/plain"`)
+	_, err = ConvertResults(strings.NewReader(convertCSV), FormatCSV, "application/pdf")
+	assert.EqualError(t, err, `dwapi: can't convert results to type "application/pdf"`)
+
+	r, err := ConvertResults(strings.NewReader("a,b\n1,2\n\"3"), FormatCSV, FormatJSON)
+	if assert.NoError(t, err) {
+		_, err = ioutil.ReadAll(r)
+		assert.Error(t, err)
+	}
+}
@@ -0,0 +1,379 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package dwapi
+
+import (
+	"encoding"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ResultError describes a value in query results that can't be decoded into a Go value.
+type ResultError struct {
+	// Row is the 1-based number of the row in the results.
+	Row      int
+	Column   string
+	Value    string
+	Datatype string
+	// Field is the struct field, or the argument, that the value was decoded into.
+	Field string
+	Type  reflect.Type
+	Err   error
+}
+
+func (e *ResultError) Error() string {
+	value := strconv.Quote(e.Value)
+	if e.Datatype != "" {
+		value += "^^" + shortDatatype(e.Datatype)
+	}
+	return fmt.Sprintf("dwapi: row %d, column %q: can't decode %s into %s (%s): %s",
+		e.Row, e.Column, value, e.Field, e.Type, e.Err)
+}
+
+var (
+	timeType        = reflect.TypeOf(time.Time{})
+	textUnmarshaler = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// DecodeResults decodes query results of the given content type into dest, which must be a pointer
+// to a slice of structs or of pointers to structs. SPARQL JSON results
+// (application/sparql-results+json), CSV, TSV and JSON results are supported.
+//
+// Columns are decoded into the fields tagged with their name, e.g. `dw:"birth_date"`, or else into
+// the field whose name matches the column's, ignoring case and underscores. Fields tagged `dw:"-"`
+// are ignored. A tagged field without a column is an error, and so is a value that can't be
+// converted to the type of its field.
+//
+// Typed values are converted according to their xsd datatype: integers into int and uint fields,
+// decimals and doubles into float fields, booleans into bool fields, and dates and times into
+// time.Time fields. Unbound values and nulls leave pointer fields nil and other fields zero. Any
+// value can be decoded into a string field, or a field implementing encoding.TextUnmarshaler.
+func DecodeResults(r io.Reader, contentType string, dest interface{}) error {
+	rr, err := newResultReader(r, contentType)
+	if err != nil {
+		return err
+	}
+	return decodeRows(rr, dest)
+}
+
+func decodeRows(rr resultReader, dest interface{}) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("dwapi: results must be decoded into a pointer to a slice, not %T", dest)
+	}
+	slice := v.Elem()
+	elem := slice.Type().Elem()
+	structType := elem
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return fmt.Errorf("dwapi: results must be decoded into a slice of structs, not %T", dest)
+	}
+
+	columns := rr.columns()
+	fields, err := mapColumns(structType, columns)
+	if err != nil {
+		return err
+	}
+
+	slice.SetLen(0)
+	for n := 1; ; n++ {
+		row, err := rr.next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		item := reflect.New(structType).Elem()
+		for i, f := range fields {
+			if f == nil || i >= len(row) {
+				continue
+			}
+			field := item.FieldByIndex(f.index)
+			if err = setTerm(field, row[i]); err != nil {
+				return resultError(n, columns[i], row[i], structType.Name()+"."+f.name, field.Type(), err)
+			}
+		}
+		if elem.Kind() == reflect.Ptr {
+			item = item.Addr()
+		}
+		slice.Set(reflect.Append(slice, item))
+	}
+}
+
+func resultError(row int, column string, t *ResultTerm, field string, typ reflect.Type, err error) error {
+	return &ResultError{
+		Row:      row,
+		Column:   column,
+		Value:    t.Value,
+		Datatype: t.Datatype,
+		Field:    field,
+		Type:     typ,
+		Err:      err,
+	}
+}
+
+type resultField struct {
+	name  string
+	index []int
+	tag   string
+}
+
+// mapColumns finds the field of each column, nil for columns no field is decoded from.
+func mapColumns(t reflect.Type, columns []string) ([]*resultField, error) {
+	fields := make([]*resultField, len(columns))
+	for _, f := range structFields(t, nil) {
+		i := -1
+		if f.tag != "" {
+			for c, column := range columns {
+				if column == f.tag {
+					i = c
+					break
+				}
+			}
+			if i < 0 {
+				return nil, fmt.Errorf("dwapi: column %q of field %s.%s is not in the results %q",
+					f.tag, t.Name(), f.name, columns)
+			}
+		} else {
+			for c, column := range columns {
+				if strings.EqualFold(strings.Replace(column, "_", "", -1), f.name) && fields[c] == nil {
+					i = c
+					break
+				}
+			}
+		}
+		if i >= 0 && (fields[i] == nil || fields[i].tag == "") {
+			f := f
+			fields[i] = &f
+		}
+	}
+	return fields, nil
+}
+
+func structFields(t reflect.Type, index []int) []resultField {
+	var fields []resultField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("dw")
+		if f.PkgPath != "" && !f.Anonymous || tag == "-" {
+			continue
+		}
+		idx := append(append([]int(nil), index...), i)
+		if f.Anonymous && tag == "" && f.Type.Kind() == reflect.Struct && f.Type != timeType {
+			fields = append(fields, structFields(f.Type, idx)...)
+			continue
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		fields = append(fields, resultField{name: f.Name, index: idx, tag: tag})
+	}
+	return fields
+}
+
+// setTerm converts a value of the results into v.
+func setTerm(v reflect.Value, t *ResultTerm) error {
+	if t == nil {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+	if v.Kind() == reflect.Ptr {
+		p := reflect.New(v.Type().Elem())
+		if err := setTerm(p.Elem(), t); err != nil {
+			return err
+		}
+		v.Set(p)
+		return nil
+	}
+
+	if v.Type() == timeType {
+		if err := t.check("time"); err != nil {
+			return err
+		}
+		tm, err := parseTime(t.Value)
+		if err == nil {
+			v.Set(reflect.ValueOf(tm))
+		}
+		return err
+	}
+	if reflect.PtrTo(v.Type()).Implements(textUnmarshaler) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(t.Value))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(t.Value)
+	case reflect.Bool:
+		if err := t.check("bool"); err != nil {
+			return err
+		}
+		b, err := strconv.ParseBool(t.Value)
+		if err != nil {
+			return numError(err)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if err := t.check("int"); err != nil {
+			return err
+		}
+		n, err := strconv.ParseInt(t.Value, 10, v.Type().Bits())
+		if err != nil {
+			return numError(err)
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if err := t.check("int"); err != nil {
+			return err
+		}
+		n, err := strconv.ParseUint(t.Value, 10, v.Type().Bits())
+		if err != nil {
+			return numError(err)
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		if err := t.check("int", "float"); err != nil {
+			return err
+		}
+		f, err := strconv.ParseFloat(t.Value, v.Type().Bits())
+		if err != nil {
+			return numError(err)
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.Uint8 {
+			return fmt.Errorf("unsupported field type")
+		}
+		v.SetBytes([]byte(t.Value))
+	case reflect.Interface:
+		value, err := t.goValue()
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(value))
+	default:
+		return fmt.Errorf("unsupported field type")
+	}
+	return nil
+}
+
+func numError(err error) error {
+	if e, ok := err.(*strconv.NumError); ok {
+		return e.Err
+	}
+	return err
+}
+
+// category groups the xsd datatypes by the Go types they convert to. Values without a datatype, and
+// strings, have no category and are parsed into any type.
+func (t *ResultTerm) category() string {
+	if t.Type == "uri" || t.Type == "bnode" {
+		return "iri"
+	}
+	if !strings.HasPrefix(t.Datatype, xsd) {
+		return ""
+	}
+	switch strings.TrimPrefix(t.Datatype, xsd) {
+	case "integer", "int", "long", "short", "byte", "nonNegativeInteger", "positiveInteger",
+		"nonPositiveInteger", "negativeInteger", "unsignedLong", "unsignedInt", "unsignedShort",
+		"unsignedByte":
+		return "int"
+	case "decimal", "double", "float":
+		return "float"
+	case "boolean":
+		return "bool"
+	case "dateTime", "dateTimeStamp", "date", "time":
+		return "time"
+	}
+	return ""
+}
+
+// check fails if the value's datatype doesn't convert to any of the categories.
+func (t *ResultTerm) check(categories ...string) error {
+	c := t.category()
+	if c == "" {
+		return nil
+	}
+	for _, want := range categories {
+		if c == want {
+			return nil
+		}
+	}
+	if c == "iri" {
+		return fmt.Errorf("column holds %ss, not literals", t.Type)
+	}
+	return fmt.Errorf("column type %s doesn't match", shortDatatype(t.Datatype))
+}
+
+// goValue converts the value into the Go type of its datatype: int64, float64, bool, time.Time or
+// string.
+func (t *ResultTerm) goValue() (interface{}, error) {
+	switch t.category() {
+	case "int":
+		n, err := strconv.ParseInt(t.Value, 10, 64)
+		if numError(err) == strconv.ErrRange {
+			// Integers too large for an int64 are kept as they are.
+			return t.Value, nil
+		}
+		return n, numError(err)
+	case "float":
+		f, err := strconv.ParseFloat(t.Value, 64)
+		return f, numError(err)
+	case "bool":
+		b, err := strconv.ParseBool(t.Value)
+		return b, err
+	case "time":
+		return parseTime(t.Value)
+	}
+	return t.Value, nil
+}
+
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02Z07:00",
+	"2006-01-02",
+	"15:04:05.999999999Z07:00",
+	"15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+}
+
+// parseTime parses the lexical forms of xsd:dateTime, xsd:date and xsd:time. Values without a time
+// zone are in UTC.
+func parseTime(s string) (time.Time, error) {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("not a date or time")
+}
+
+func shortDatatype(datatype string) string {
+	if strings.HasPrefix(datatype, xsd) {
+		return "xsd:" + strings.TrimPrefix(datatype, xsd)
+	}
+	return "<" + datatype + ">"
+}
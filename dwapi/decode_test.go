@@ -0,0 +1,137 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package dwapi
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Audit struct {
+	Updated time.Time
+}
+
+type decodedRow struct {
+	Audit
+	ID       int
+	Name     string `dw:"full_name"`
+	Active   bool
+	Ratio    float32
+	Count    *uint16
+	IP       net.IP
+	Value    interface{}
+	internal string
+	Ignored  string `dw:"-"`
+}
+
+const typedResults = `{
+	"head": {"vars": ["id", "full_name", "active", "ratio", "count", "ip", "value", "updated", "ignored"]},
+	"results": {"bindings": [{
+		"id": {"type": "literal", "value": "1", "datatype": "http://www.w3.org/2001/XMLSchema#int"},
+		"full_name": {"type": "literal", "value": "Ada", "xml:lang": "en"},
+		"active": {"type": "literal", "value": "true", "datatype": "http://www.w3.org/2001/XMLSchema#boolean"},
+		"ratio": {"type": "literal", "value": "0.5", "datatype": "http://www.w3.org/2001/XMLSchema#double"},
+		"count": {"type": "literal", "value": "7", "datatype": "http://www.w3.org/2001/XMLSchema#integer"},
+		"ip": {"type": "literal", "value": "10.0.0.1"},
+		"value": {"type": "literal", "value": "2018-08-03", "datatype": "http://www.w3.org/2001/XMLSchema#date"},
+		"updated": {"type": "literal", "value": "2018-08-03T15:56:41.5", "datatype": "http://www.w3.org/2001/XMLSchema#dateTime"},
+		"ignored": {"type": "literal", "value": "x"}
+	}, {
+		"id": {"type": "literal", "value": "2", "datatype": "http://www.w3.org/2001/XMLSchema#int"},
+		"full_name": {"type": "literal", "value": "Grace"}
+	}]}
+}`
+
+func TestDecodeResults(t *testing.T) {
+	var got []*decodedRow
+	err := DecodeResults(strings.NewReader(typedResults), "application/sparql-results+json", &got)
+	if !assert.NoError(t, err) || !assert.Len(t, got, 2) {
+		return
+	}
+	count := uint16(7)
+	assert.Equal(t, &decodedRow{
+		Audit:  Audit{Updated: time.Date(2018, 8, 3, 15, 56, 41, 5e8, time.UTC)},
+		ID:     1,
+		Name:   "Ada",
+		Active: true,
+		Ratio:  0.5,
+		Count:  &count,
+		IP:     net.ParseIP("10.0.0.1"),
+		Value:  time.Date(2018, 8, 3, 0, 0, 0, 0, time.UTC),
+	}, got[0])
+	assert.Equal(t, &decodedRow{ID: 2, Name: "Grace"}, got[1])
+}
+
+func TestDecodeResults_CSV(t *testing.T) {
+	type row struct {
+		UserID int64
+		Score  *float64
+		Joined time.Time
+	}
+	csv := "user_id,score,joined\n1,2.5,2018-08-03\n2,,2018-08-04T10:00:00Z\n"
+	var got []row
+	err := DecodeResults(strings.NewReader(csv), "text/csv; charset=utf-8", &got)
+	if assert.NoError(t, err) {
+		score := 2.5
+		assert.Equal(t, []row{
+			{UserID: 1, Score: &score, Joined: time.Date(2018, 8, 3, 0, 0, 0, 0, time.UTC)},
+			{UserID: 2, Joined: time.Date(2018, 8, 4, 10, 0, 0, 0, time.UTC)},
+		}, got)
+	}
+}
+
+func TestDecodeResults_Errors(t *testing.T) {
+	type row struct {
+		ID int8 `dw:"id"`
+	}
+	tests := []struct {
+		results string
+		dest    interface{}
+		want    string
+	}{
+		{"id\n1\n", []row{}, "dwapi: results must be decoded into a pointer to a slice, not []dwapi.row"},
+		{"id\n1\n", &[]int{}, "dwapi: results must be decoded into a slice of structs, not *[]int"},
+		{"name\nx\n", &[]row{}, `dwapi: column "id" of field row.ID is not in the results ["name"]`},
+		{"id\n1\n1000\n", &[]row{}, `dwapi: row 2, column "id": can't decode "1000" into row.ID (int8): value out of range`},
+		{"id\nabc\n", &[]row{}, `dwapi: row 1, column "id": can't decode "abc" into row.ID (int8): invalid syntax`},
+	}
+	for _, test := range tests {
+		err := DecodeResults(strings.NewReader(test.results), "text/csv", test.dest)
+		if assert.Error(t, err) {
+			assert.Equal(t, test.want, err.Error())
+		}
+	}
+
+	typed := `{"head": {"vars": ["id"]}, "results": {"bindings": [
+		{"id": {"type": "literal", "value": "2018-08-03", "datatype": "http://www.w3.org/2001/XMLSchema#date"}}
+	]}}`
+	var got []row
+	err := DecodeResults(strings.NewReader(typed), "application/sparql-results+json", &got)
+	if assert.IsType(t, &ResultError{}, err) {
+		assert.Equal(t, 1, err.(*ResultError).Row)
+		assert.Equal(t, `dwapi: row 1, column "id": can't decode "2018-08-03"^^xsd:date into row.ID (int8): `+
+			`column type xsd:date doesn't match`, err.Error())
+	}
+
+	err = DecodeResults(strings.NewReader("<sparql/>"), "application/sparql-results+xml", &got)
+	assert.EqualError(t, err, `dwapi: can't decode results of type "application/sparql-results+xml"`)
+}
@@ -0,0 +1,149 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package dwapi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines around the changes of a hunk.
+const diffContext = 3
+
+type diffLine struct {
+	op   byte // ' ', '-' or '+'
+	text string
+}
+
+// unifiedDiff returns the differences between the lines of a and b in the unified diff format, or an
+// empty string if there are none.
+func unifiedDiff(a, b, fromName, toName string) string {
+	lines := diffLines(splitLines(a), splitLines(b))
+
+	var out strings.Builder
+	for start := 0; start < len(lines); {
+		// A hunk runs from the first change left, until the changes are more than twice the context
+		// apart.
+		first := start
+		for first < len(lines) && lines[first].op == ' ' {
+			first++
+		}
+		if first == len(lines) {
+			break
+		}
+		last, equal := first, 0
+		for i := first + 1; i < len(lines) && equal <= 2*diffContext; i++ {
+			if lines[i].op == ' ' {
+				equal++
+			} else {
+				last, equal = i, 0
+			}
+		}
+		from, to := first-diffContext, last+diffContext+1
+		if from < 0 {
+			from = 0
+		}
+		if to > len(lines) {
+			to = len(lines)
+		}
+
+		if out.Len() == 0 {
+			fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+		}
+		aLine, bLine := lineNumbers(lines[:from])
+		aCount, bCount := lineNumbers(lines[from:to])
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(aLine, aCount), hunkRange(bLine, bCount))
+		for _, l := range lines[from:to] {
+			out.WriteByte(l.op)
+			out.WriteString(l.text)
+			out.WriteByte('\n')
+		}
+		start = to
+	}
+	return out.String()
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// diffLines returns the edits that turn a into b, from their longest common subsequence.
+func diffLines(a, b []string) []diffLine {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var lines []diffLine
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, diffLine{' ', a[i]})
+			i++
+			j++
+		case j == len(b) || i < len(a) && lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, diffLine{'-', a[i]})
+			i++
+		default:
+			lines = append(lines, diffLine{'+', b[j]})
+			j++
+		}
+	}
+	return lines
+}
+
+// lineNumbers counts the lines of a and of b in a run of edits.
+func lineNumbers(lines []diffLine) (a, b int) {
+	for _, l := range lines {
+		if l.op != '+' {
+			a++
+		}
+		if l.op != '-' {
+			b++
+		}
+	}
+	return a, b
+}
+
+// hunkRange formats the range of a hunk that starts after the given number of lines. Empty ranges
+// start at the line before them.
+func hunkRange(before, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", before)
+	}
+	if count == 1 {
+		return fmt.Sprint(before + 1)
+	}
+	return fmt.Sprintf("%d,%d", before+1, count)
+}
@@ -0,0 +1,61 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package dwapi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	var a, b []string
+	for i := 1; i <= 20; i++ {
+		a = append(a, "line "+string(rune('a'+i)))
+	}
+	b = append(b, a...)
+	b[1] = "changed"
+	b = append(b[:15], b[16:]...)
+	b = append(b, "added")
+
+	assert.Equal(t, `--- from
++++ to
+@@ -1,5 +1,5 @@
+ line b
+-line c
++changed
+ line d
+ line e
+ line f
+@@ -13,8 +13,8 @@
+ line n
+ line o
+ line p
+-line q
+ line r
+ line s
+ line t
+ line u
++added
+`, unifiedDiff(strings.Join(a, "\n")+"\n", strings.Join(b, "\n"), "from", "to"))
+
+	assert.Equal(t, "--- from\n+++ to\n@@ -0,0 +1 @@\n+new\n", unifiedDiff("", "new", "from", "to"))
+	assert.Equal(t, "--- from\n+++ to\n@@ -1,2 +0,0 @@\n-x\n-y\n", unifiedDiff("x\ny", "", "from", "to"))
+	assert.Empty(t, unifiedDiff("same\n", "same", "from", "to"))
+}
@@ -0,0 +1,344 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package dwapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Response models keep the JSON fields they don't know about in their Extra map, so that attributes
+// added to the API are preserved, and written back when the model is marshaled, until the models
+// catch up.
+
+var knownFields sync.Map // reflect.Type -> []string
+
+// jsonFields returns the JSON names of the fields of a struct type.
+func jsonFields(t reflect.Type) []string {
+	if names, ok := knownFields.Load(t); ok {
+		return names.([]string)
+	}
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "-" || f.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		names = append(names, name)
+	}
+	knownFields.Store(t, names)
+	return names
+}
+
+// unmarshalWithExtra unmarshals b into v, a pointer to a struct, and sets extra to the fields of b
+// that don't correspond to any field of v.
+func unmarshalWithExtra(b []byte, v interface{}, extra *map[string]json.RawMessage) error {
+	if err := json.Unmarshal(b, v); err != nil {
+		return err
+	}
+	*extra = nil
+	if bytes.Equal(bytes.TrimSpace(b), []byte("null")) {
+		return nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+	known := jsonFields(reflect.TypeOf(v).Elem())
+	for name, value := range fields {
+		if isKnownField(known, name) {
+			continue
+		}
+		if *extra == nil {
+			*extra = make(map[string]json.RawMessage)
+		}
+		(*extra)[name] = value
+	}
+	return nil
+}
+
+// isKnownField matches field names case-insensitively, like encoding/json does.
+func isKnownField(known []string, name string) bool {
+	for _, k := range known {
+		if strings.EqualFold(k, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// marshalWithExtra marshals v, a struct, adding the fields in extra that v doesn't already have.
+func marshalWithExtra(v interface{}, extra map[string]json.RawMessage) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return b, err
+	}
+
+	known := jsonFields(reflect.TypeOf(v))
+	var names []string
+	for name := range extra {
+		if !isKnownField(known, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	buf := bytes.NewBuffer(b[:len(b)-1])
+	for _, name := range names {
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		k, _ := json.Marshal(name)
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(extra[name])
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// unknownFields lists the paths of the Extra fields found in v, e.g. "files[].source.newField".
+func unknownFields(v interface{}) []string {
+	found := make(map[string]bool)
+	collectUnknownFields(reflect.ValueOf(v), "", found)
+
+	fields := make([]string, 0, len(found))
+	for f := range found {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+var extraType = reflect.TypeOf(map[string]json.RawMessage(nil))
+
+func collectUnknownFields(v reflect.Value, path string, found map[string]bool) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			collectUnknownFields(v.Elem(), path, found)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			collectUnknownFields(v.Index(i), path+"[]", found)
+		}
+	case reflect.Map:
+		for _, k := range v.MapKeys() {
+			collectUnknownFields(v.MapIndex(k), path+"[]", found)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" {
+				continue
+			}
+			if f.Name == "Extra" && f.Type == extraType {
+				for name := range v.Field(i).Interface().(map[string]json.RawMessage) {
+					found[joinPath(path, name)] = true
+				}
+				continue
+			}
+			name := strings.Split(f.Tag.Get("json"), ",")[0]
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			collectUnknownFields(v.Field(i), joinPath(path, name), found)
+		}
+	}
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// reportUnknownFields passes the unknown fields of a response to OnUnknownFields when the client is
+// in strict mode.
+func (c *Client) reportUnknownFields(method, endpoint string, response interface{}) {
+	if !c.StrictMode || c.OnUnknownFields == nil {
+		return
+	}
+	if fields := unknownFields(response); len(fields) > 0 {
+		c.OnUnknownFields(method, endpoint, fields)
+	}
+}
+
+func (r *DatasetCreateResponse) UnmarshalJSON(b []byte) error {
+	type model DatasetCreateResponse
+	return unmarshalWithExtra(b, (*model)(r), &r.Extra)
+}
+
+func (r DatasetCreateResponse) MarshalJSON() ([]byte, error) {
+	type model DatasetCreateResponse
+	return marshalWithExtra(model(r), r.Extra)
+}
+
+func (r *DatasetSummaryResponse) UnmarshalJSON(b []byte) error {
+	type model DatasetSummaryResponse
+	return unmarshalWithExtra(b, (*model)(r), &r.Extra)
+}
+
+func (r DatasetSummaryResponse) MarshalJSON() ([]byte, error) {
+	type model DatasetSummaryResponse
+	return marshalWithExtra(model(r), r.Extra)
+}
+
+func (r *DigitalObjectIdentifier) UnmarshalJSON(b []byte) error {
+	type model DigitalObjectIdentifier
+	return unmarshalWithExtra(b, (*model)(r), &r.Extra)
+}
+
+func (r DigitalObjectIdentifier) MarshalJSON() ([]byte, error) {
+	type model DigitalObjectIdentifier
+	return marshalWithExtra(model(r), r.Extra)
+}
+
+func (r *FileSourceResponse) UnmarshalJSON(b []byte) error {
+	type model FileSourceResponse
+	return unmarshalWithExtra(b, (*model)(r), &r.Extra)
+}
+
+func (r FileSourceResponse) MarshalJSON() ([]byte, error) {
+	type model FileSourceResponse
+	return marshalWithExtra(model(r), r.Extra)
+}
+
+func (r *FileSummaryResponse) UnmarshalJSON(b []byte) error {
+	type model FileSummaryResponse
+	return unmarshalWithExtra(b, (*model)(r), &r.Extra)
+}
+
+func (r FileSummaryResponse) MarshalJSON() ([]byte, error) {
+	type model FileSummaryResponse
+	return marshalWithExtra(model(r), r.Extra)
+}
+
+func (r *InsightCreateResponse) UnmarshalJSON(b []byte) error {
+	type model InsightCreateResponse
+	return unmarshalWithExtra(b, (*model)(r), &r.Extra)
+}
+
+func (r InsightCreateResponse) MarshalJSON() ([]byte, error) {
+	type model InsightCreateResponse
+	return marshalWithExtra(model(r), r.Extra)
+}
+
+func (r *InsightSummaryResponse) UnmarshalJSON(b []byte) error {
+	type model InsightSummaryResponse
+	return unmarshalWithExtra(b, (*model)(r), &r.Extra)
+}
+
+func (r InsightSummaryResponse) MarshalJSON() ([]byte, error) {
+	type model InsightSummaryResponse
+	return marshalWithExtra(model(r), r.Extra)
+}
+
+func (r *LinkedDatasetSummaryResponse) UnmarshalJSON(b []byte) error {
+	type model LinkedDatasetSummaryResponse
+	return unmarshalWithExtra(b, (*model)(r), &r.Extra)
+}
+
+func (r LinkedDatasetSummaryResponse) MarshalJSON() ([]byte, error) {
+	type model LinkedDatasetSummaryResponse
+	return marshalWithExtra(model(r), r.Extra)
+}
+
+func (r *ProjectCreateResponse) UnmarshalJSON(b []byte) error {
+	type model ProjectCreateResponse
+	return unmarshalWithExtra(b, (*model)(r), &r.Extra)
+}
+
+func (r ProjectCreateResponse) MarshalJSON() ([]byte, error) {
+	type model ProjectCreateResponse
+	return marshalWithExtra(model(r), r.Extra)
+}
+
+func (r *ProjectSummaryResponse) UnmarshalJSON(b []byte) error {
+	type model ProjectSummaryResponse
+	return unmarshalWithExtra(b, (*model)(r), &r.Extra)
+}
+
+func (r ProjectSummaryResponse) MarshalJSON() ([]byte, error) {
+	type model ProjectSummaryResponse
+	return marshalWithExtra(model(r), r.Extra)
+}
+
+func (r *QuerySummaryResponse) UnmarshalJSON(b []byte) error {
+	type model QuerySummaryResponse
+	return unmarshalWithExtra(b, (*model)(r), &r.Extra)
+}
+
+func (r QuerySummaryResponse) MarshalJSON() ([]byte, error) {
+	type model QuerySummaryResponse
+	return marshalWithExtra(model(r), r.Extra)
+}
+
+func (r *StreamSchema) UnmarshalJSON(b []byte) error {
+	type model StreamSchema
+	return unmarshalWithExtra(b, (*model)(r), &r.Extra)
+}
+
+func (r StreamSchema) MarshalJSON() ([]byte, error) {
+	type model StreamSchema
+	return marshalWithExtra(model(r), r.Extra)
+}
+
+func (r *Subscription) UnmarshalJSON(b []byte) error {
+	type model Subscription
+	return unmarshalWithExtra(b, (*model)(r), &r.Extra)
+}
+
+func (r Subscription) MarshalJSON() ([]byte, error) {
+	type model Subscription
+	return marshalWithExtra(model(r), r.Extra)
+}
+
+func (r *SuccessResponse) UnmarshalJSON(b []byte) error {
+	type model SuccessResponse
+	return unmarshalWithExtra(b, (*model)(r), &r.Extra)
+}
+
+func (r SuccessResponse) MarshalJSON() ([]byte, error) {
+	type model SuccessResponse
+	return marshalWithExtra(model(r), r.Extra)
+}
+
+func (r *UserInfoResponse) UnmarshalJSON(b []byte) error {
+	type model UserInfoResponse
+	return unmarshalWithExtra(b, (*model)(r), &r.Extra)
+}
+
+func (r UserInfoResponse) MarshalJSON() ([]byte, error) {
+	type model UserInfoResponse
+	return marshalWithExtra(model(r), r.Extra)
+}
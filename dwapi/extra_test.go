@@ -0,0 +1,122 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package dwapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const datasetWithExtraFields = `{
+	"owner": "tim-notes",
+	"id": "my-awesome-dataset",
+	"Title": "My Awesome Dataset",
+	"files": [{
+		"name": "data.csv",
+		"source": {"url": "https://example.com/data.csv", "syncSummary": "OK", "syncSchedule": {"every": "DAY"}}
+	}],
+	"hasDiscussion": true
+}`
+
+func TestExtra_RoundTrip(t *testing.T) {
+	var d DatasetSummaryResponse
+	if !assert.NoError(t, json.Unmarshal([]byte(datasetWithExtraFields), &d)) {
+		return
+	}
+	assert.Equal(t, "My Awesome Dataset", d.Title)
+	assert.Equal(t, map[string]json.RawMessage{"hasDiscussion": json.RawMessage("true")}, d.Extra)
+	assert.Equal(t, "OK", d.Files[0].Source.SyncSummaryText)
+	assert.Equal(t, map[string]json.RawMessage{"syncSchedule": json.RawMessage(`{"every": "DAY"}`)},
+		d.Files[0].Source.Extra)
+	assert.Nil(t, d.Files[0].Extra)
+
+	b, err := json.Marshal(d)
+	if !assert.NoError(t, err) {
+		return
+	}
+	var fields map[string]interface{}
+	assert.NoError(t, json.Unmarshal(b, &fields))
+	assert.Equal(t, true, fields["hasDiscussion"])
+	assert.Equal(t, map[string]interface{}{"every": "DAY"},
+		fields["files"].([]interface{})[0].(map[string]interface{})["source"].(map[string]interface{})["syncSchedule"])
+
+	var again DatasetSummaryResponse
+	assert.NoError(t, json.Unmarshal(b, &again))
+	assert.Equal(t, d.Extra, again.Extra)
+}
+
+func TestExtra_Marshal(t *testing.T) {
+	b, err := json.Marshal(SuccessResponse{})
+	assert.NoError(t, err)
+	assert.Equal(t, `{}`, string(b))
+
+	b, err = json.Marshal(SuccessResponse{Extra: map[string]json.RawMessage{
+		"b":       json.RawMessage(`2`),
+		"a":       json.RawMessage(`"1"`),
+		"message": json.RawMessage(`"ignored"`),
+	}})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"a":"1","b":2}`, string(b))
+
+	b, err = json.Marshal(SuccessResponse{Message: "saved", Extra: map[string]json.RawMessage{"a": json.RawMessage(`1`)}})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"message":"saved","a":1}`, string(b))
+}
+
+func TestClient_StrictMode(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/datasets/tim-notes/my-awesome-dataset", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, datasetWithExtraFields)
+	})
+	mux.HandleFunc("/user/datasets/own", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"count": 1, "records": [%s]}`, datasetWithExtraFields)
+	})
+
+	var reports []string
+	dw.OnUnknownFields = func(method, endpoint string, fields []string) {
+		reports = append(reports, fmt.Sprintf("%s %s %v", method, endpoint, fields))
+	}
+
+	_, err := dw.Dataset.Retrieve("tim-notes", "my-awesome-dataset")
+	assert.NoError(t, err)
+	assert.Empty(t, reports)
+
+	// Without OnUnknownFields, the fields are ignored.
+	dw.StrictMode = true
+	dw.OnUnknownFields = nil
+	_, err = dw.Dataset.Retrieve("tim-notes", "my-awesome-dataset")
+	assert.NoError(t, err)
+
+	dw.OnUnknownFields = func(method, endpoint string, fields []string) {
+		reports = append(reports, fmt.Sprintf("%s %s %v", method, endpoint, fields))
+	}
+	_, err = dw.Dataset.Retrieve("tim-notes", "my-awesome-dataset")
+	assert.NoError(t, err)
+	_, err = dw.User.DatasetsOwned()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"GET /datasets/tim-notes/my-awesome-dataset [files[].source.syncSchedule hasDiscussion]",
+		"GET /user/datasets/own [[].files[].source.syncSchedule [].hasDiscussion]",
+	}, reports)
+}
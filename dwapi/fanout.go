@@ -0,0 +1,145 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package dwapi
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// SourceColumn is the first column of the rows of `QueryService.ExecuteSQLAcross`, naming the dataset
+// or project each row comes from as owner/id.
+const SourceColumn = "source_dataset"
+
+// FanOutFailure is a dataset or project that a query run by `QueryService.ExecuteSQLAcross` failed
+// against. Rows read before the failure have been returned already.
+type FanOutFailure struct {
+	Dataset DatasetOrProjectIdentifier
+	Err     error
+}
+
+func (f FanOutFailure) Error() string {
+	return fmt.Sprintf("%s/%s: %s", f.Dataset.Owner, f.Dataset.ID, f.Err)
+}
+
+// FanOutRows are the rows of a query run across datasets or projects, in the order they arrive.
+type FanOutRows struct {
+	*Rows
+	union *unionReader
+}
+
+// Failures returns the datasets or projects that the query failed against. The list is complete once
+// Next has returned false.
+func (r *FanOutRows) Failures() []FanOutFailure {
+	r.union.mu.Lock()
+	defer r.union.mu.Unlock()
+	return append([]FanOutFailure(nil), r.union.failures...)
+}
+
+// Identifiers returns the identifiers of the datasets or projects that keep returns true for, or of
+// all of them if keep is nil, e.g. to run a query across some of `UserService.DatasetsOwned`.
+func Identifiers(datasets []DatasetSummaryResponse, keep func(DatasetSummaryResponse) bool) (
+	response []DatasetOrProjectIdentifier) {
+	for _, d := range datasets {
+		if keep == nil || keep(d) {
+			response = append(response, DatasetOrProjectIdentifier{Owner: d.Owner, ID: d.ID})
+		}
+	}
+	return
+}
+
+// unionReader merges the rows of a query against several datasets, which must have the same columns.
+type unionReader struct {
+	cols  []string
+	rows  chan []*ResultTerm
+	done  chan struct{}
+	close sync.Once
+
+	mu       sync.Mutex
+	ready    chan struct{}
+	failures []FanOutFailure
+}
+
+func (u *unionReader) columns() []string {
+	return u.cols
+}
+
+func (u *unionReader) next() ([]*ResultTerm, error) {
+	row, ok := <-u.rows
+	if !ok {
+		return nil, io.EOF
+	}
+	return row, nil
+}
+
+// Close stops the queries still running.
+func (u *unionReader) Close() error {
+	u.close.Do(func() { close(u.done) })
+	return nil
+}
+
+func (u *unionReader) fail(d DatasetOrProjectIdentifier, err error) {
+	u.mu.Lock()
+	u.failures = append(u.failures, FanOutFailure{Dataset: d, Err: err})
+	u.mu.Unlock()
+}
+
+// open sets the columns of the union from the first dataset to answer, and checks those of the others
+// against them.
+func (u *unionReader) open(columns []string) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.cols == nil {
+		u.cols = append([]string{SourceColumn}, columns...)
+		close(u.ready)
+		return nil
+	}
+	if fmt.Sprint(u.cols[1:]) != fmt.Sprint(columns) {
+		return fmt.Errorf("dwapi: columns %v don't match %v", columns, u.cols[1:])
+	}
+	return nil
+}
+
+// streamRows sends the rows of the query against one dataset to the union.
+func (s *QueryService) streamRows(u *unionReader, d DatasetOrProjectIdentifier, acceptType string,
+	body *SQLQueryRequest) {
+	r, err := s.ExecuteSQLRows(d.Owner, d.ID, acceptType, body)
+	if err != nil {
+		u.fail(d, err)
+		return
+	}
+	defer r.Close()
+	if err := u.open(r.Columns()); err != nil {
+		u.fail(d, err)
+		return
+	}
+	source := &ResultTerm{Type: "literal", Value: d.Owner + "/" + d.ID, Datatype: xsd + "string"}
+	for r.Next() {
+		row := append([]*ResultTerm{source}, r.row...)
+		select {
+		case u.rows <- row:
+		case <-u.done:
+			u.fail(d, ErrRowsClosed)
+			return
+		}
+	}
+	if err := r.Err(); err != nil {
+		u.fail(d, err)
+	}
+}
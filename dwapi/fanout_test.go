@@ -0,0 +1,74 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package dwapi
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdentifiers(t *testing.T) {
+	owned := []DatasetSummaryResponse{
+		{Owner: "acme", ID: "sales-emea"},
+		{Owner: "acme", ID: "crm"},
+		{Owner: "acme", ID: "sales-apac"},
+	}
+	assert.Equal(t, []DatasetOrProjectIdentifier{{Owner: "acme", ID: "sales-emea"}, {Owner: "acme", ID: "sales-apac"}},
+		Identifiers(owned, func(d DatasetSummaryResponse) bool { return strings.HasPrefix(d.ID, "sales-") }))
+	assert.Len(t, Identifiers(owned, nil), 3)
+}
+
+func TestFanOutRows_Failures(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/sql/", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message": "not found"}`, http.StatusNotFound)
+	})
+	rows, err := dw.Query.ExecuteSQLAcross([]DatasetOrProjectIdentifier{{Owner: "acme", ID: "a"}, {Owner: "acme", ID: "b"}},
+		"text/csv", &SQLQueryRequest{Query: "SELECT 1"}, nil)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{SourceColumn}, rows.Columns())
+		assert.False(t, rows.Next())
+		assert.Len(t, rows.Failures(), 2)
+	}
+}
+
+func TestFanOutRows_Close(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/sql/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "n\n1\n2\n3\n")
+	})
+	var datasets []DatasetOrProjectIdentifier
+	for i := 0; i < 10; i++ {
+		datasets = append(datasets, DatasetOrProjectIdentifier{Owner: "acme", ID: fmt.Sprint("d", i)})
+	}
+	rows, err := dw.Query.ExecuteSQLAcross(datasets, "text/csv", &SQLQueryRequest{Query: "SELECT n"},
+		&BatchOptions{Concurrency: 2})
+	if assert.NoError(t, err) {
+		assert.True(t, rows.Next())
+		assert.NoError(t, rows.Close())
+		assert.False(t, rows.Next())
+	}
+}
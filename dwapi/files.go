@@ -67,6 +67,7 @@ func (s *FileService) DownloadAndSave(owner, id, filename, path string) (respons
 	if err != nil {
 		return
 	}
+	defer r.Close()
 
 	if err = s.client.saveToFile(path, r); err != nil {
 		return
@@ -95,6 +96,7 @@ func (s *FileService) DownloadAndSaveDataset(owner, id, path string) (response S
 	if err != nil {
 		return
 	}
+	defer r.Close()
 
 	if err = s.client.saveToFile(path, r); err != nil {
 		return
@@ -158,7 +158,7 @@ func TestFileService_DownloadAndSave(t *testing.T) {
 	filename := "test-file"
 	path := filepath.Join(os.TempDir(), filename)
 	want := SuccessResponse{
-		fmt.Sprintf("File saved to %s", path),
+		Message: fmt.Sprintf("File saved to %s", path),
 	}
 
 	owner := testClientOwner
@@ -212,7 +212,7 @@ func TestFileService_DownloadAndSaveDataset(t *testing.T) {
 	filename := "test-file"
 	path := filepath.Join(os.TempDir(), filename)
 	want := SuccessResponse{
-		fmt.Sprintf("ZIP file saved to %s", path),
+		Message: fmt.Sprintf("ZIP file saved to %s", path),
 	}
 
 	owner := testClientOwner
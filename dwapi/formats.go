@@ -0,0 +1,171 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package dwapi
+
+import (
+	"fmt"
+	"mime"
+	"path/filepath"
+	"strings"
+)
+
+// Format is the content type of query results, passed as the acceptType of the query methods.
+type Format string
+
+// Formats of query results. Which ones a query can be returned in depends on its QueryKind; Markdown
+// and HTML are only written by ConvertResults, which the ToFile methods of QueryService use for them.
+const (
+	FormatCSV        Format = "text/csv"
+	FormatTSV        Format = "text/tab-separated-values"
+	FormatJSON       Format = "application/json"
+	FormatJSONLines  Format = "application/json-l"
+	FormatNDJSON     Format = "application/x-ndjson"
+	FormatSPARQLJSON Format = "application/sparql-results+json"
+	FormatSPARQLXML  Format = "application/sparql-results+xml"
+	FormatTurtle     Format = "text/turtle"
+	FormatNTriples   Format = "application/n-triples"
+	FormatRDFXML     Format = "application/rdf+xml"
+	FormatJSONLD     Format = "application/ld+json"
+	FormatMarkdown   Format = "text/markdown"
+	FormatHTML       Format = "text/html"
+)
+
+// QueryKind is what a query returns: a table for SQL and SPARQL SELECT and ASK queries, or a graph
+// for SPARQL CONSTRUCT and DESCRIBE queries.
+type QueryKind int
+
+const (
+	SQLQuery QueryKind = iota
+	SPARQLSelectQuery
+	SPARQLConstructQuery
+)
+
+var kindFormats = map[QueryKind][]Format{
+	SQLQuery: {FormatCSV, FormatTSV, FormatJSON, FormatJSONLines, FormatNDJSON, FormatSPARQLJSON,
+		FormatSPARQLXML},
+	SPARQLSelectQuery: {FormatSPARQLJSON, FormatSPARQLXML, FormatCSV, FormatTSV, FormatJSON,
+		FormatJSONLines, FormatNDJSON},
+	SPARQLConstructQuery: {FormatTurtle, FormatNTriples, FormatRDFXML, FormatJSONLD},
+}
+
+func (k QueryKind) String() string {
+	switch k {
+	case SQLQuery:
+		return "SQL"
+	case SPARQLSelectQuery:
+		return "SPARQL SELECT"
+	case SPARQLConstructQuery:
+		return "SPARQL CONSTRUCT"
+	}
+	return fmt.Sprintf("QueryKind(%d)", int(k))
+}
+
+// Formats returns the formats that queries of the kind can be returned in, the default first.
+func (k QueryKind) Formats() []Format {
+	return append([]Format(nil), kindFormats[k]...)
+}
+
+// Validate checks that queries of the kind can be returned in a format. Parameters of the format,
+// such as a charset, are ignored.
+func (k QueryKind) Validate(f Format) error {
+	mediaType, _, err := mime.ParseMediaType(string(f))
+	if err != nil {
+		return fmt.Errorf("dwapi: invalid format %q", f)
+	}
+	for _, allowed := range kindFormats[k] {
+		if Format(mediaType) == allowed {
+			return nil
+		}
+	}
+	return fmt.Errorf("dwapi: %s results can't be returned as %s", k, mediaType)
+}
+
+// SPARQLKind returns the kind of a SPARQL query from its form, after any prologue and comments.
+func SPARQLKind(query string) (QueryKind, error) {
+	for i := 0; i < len(query); {
+		switch c := query[i]; {
+		case c == '#':
+			for i < len(query) && query[i] != '\n' {
+				i++
+			}
+		case c == '<':
+			for i < len(query) && query[i] != '>' {
+				i++
+			}
+		case isSPARQLWordByte(c):
+			start := i
+			for i < len(query) && (isSPARQLWordByte(query[i]) || query[i] == ':') {
+				i++
+			}
+			// Prefixed names, such as ex:select, aren't keywords.
+			switch word := query[start:i]; strings.ToUpper(word) {
+			case "SELECT", "ASK":
+				return SPARQLSelectQuery, nil
+			case "CONSTRUCT", "DESCRIBE":
+				return SPARQLConstructQuery, nil
+			}
+			continue
+		}
+		i++
+	}
+	return 0, fmt.Errorf("dwapi: not a SPARQL SELECT, ASK, CONSTRUCT or DESCRIBE query")
+}
+
+func isSPARQLWordByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-' ||
+		c == '.' || c >= 0x80
+}
+
+var extensionFormats = map[string]Format{
+	".csv":    FormatCSV,
+	".tsv":    FormatTSV,
+	".json":   FormatJSON,
+	".jsonl":  FormatJSONLines,
+	".ndjson": FormatNDJSON,
+	".srj":    FormatSPARQLJSON,
+	".srx":    FormatSPARQLXML,
+	".ttl":    FormatTurtle,
+	".nt":     FormatNTriples,
+	".rdf":    FormatRDFXML,
+	".jsonld": FormatJSONLD,
+	".md":     FormatMarkdown,
+	".html":   FormatHTML,
+	".htm":    FormatHTML,
+	".owl":    FormatRDFXML,
+}
+
+// FormatForPath returns the format of a file from its extension, e.g. FormatCSV for results.csv.
+func FormatForPath(path string) (Format, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	if f, ok := extensionFormats[ext]; ok {
+		return f, nil
+	}
+	return "", fmt.Errorf("dwapi: no format for the extension of %q", path)
+}
+
+// formatForPath returns the format of a file for queries of a kind, and the format to request the
+// results in. Markdown and HTML tables are converted from SPARQL JSON results.
+func formatForPath(path string, kind QueryKind) (format, request Format, err error) {
+	if format, err = FormatForPath(path); err != nil {
+		return
+	}
+	if (format == FormatMarkdown || format == FormatHTML) && kind != SPARQLConstructQuery {
+		return format, FormatSPARQLJSON, nil
+	}
+	return format, format, kind.Validate(format)
+}
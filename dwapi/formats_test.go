@@ -0,0 +1,82 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package dwapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryKind_Validate(t *testing.T) {
+	assert.NoError(t, SQLQuery.Validate(FormatCSV))
+	assert.NoError(t, SQLQuery.Validate("application/json; charset=utf-8"))
+	assert.NoError(t, SPARQLSelectQuery.Validate(FormatSPARQLXML))
+	assert.NoError(t, SPARQLConstructQuery.Validate(FormatJSONLD))
+
+	assert.EqualError(t, SQLQuery.Validate(FormatTurtle), "dwapi: SQL results can't be returned as text/turtle")
+	assert.EqualError(t, SPARQLConstructQuery.Validate(FormatCSV),
+		"dwapi: SPARQL CONSTRUCT results can't be returned as text/csv")
+	assert.EqualError(t, SQLQuery.Validate(FormatMarkdown), "dwapi: SQL results can't be returned as text/markdown")
+	assert.EqualError(t, SQLQuery.Validate("text/"), `dwapi: invalid format "text/"`)
+
+	assert.Equal(t, FormatTurtle, SPARQLConstructQuery.Formats()[0])
+	assert.Equal(t, "QueryKind(7)", QueryKind(7).String())
+}
+
+func TestSPARQLKind(t *testing.T) {
+	tests := map[string]QueryKind{
+		"SELECT ?s WHERE { ?s ?p ?o }": SPARQLSelectQuery,
+		"ask { ?s ?p ?o }":             SPARQLSelectQuery,
+		"# CONSTRUCT a table\nPREFIX ex: <http://example.com/#x>\nSELECT * {}":    SPARQLSelectQuery,
+		"PREFIX : <http://example.com/>\nCONSTRUCT { ?s a :T } WHERE { ?s a :T }": SPARQLConstructQuery,
+		"DESCRIBE <http://example.com/a>":                                         SPARQLConstructQuery,
+		"ASK{?s ?p ?o}":                                                           SPARQLSelectQuery,
+		"SELECT*{?s ?p ?o}":                                                       SPARQLSelectQuery,
+		"PREFIX select: <http://example.com/select#ask>\nCONSTRUCT{?s a select:T}WHERE{?s a select:T}": SPARQLConstructQuery,
+		"BASE <http://example.com/>CONSTRUCT WHERE { ?s ?p ?o }":                                       SPARQLConstructQuery,
+	}
+	for query, want := range tests {
+		got, err := SPARQLKind(query)
+		if assert.NoError(t, err, query) {
+			assert.Equal(t, want, got, query)
+		}
+	}
+	_, err := SPARQLKind("INSERT DATA { }")
+	assert.EqualError(t, err, "dwapi: not a SPARQL SELECT, ASK, CONSTRUCT or DESCRIBE query")
+}
+
+func TestFormatForPath(t *testing.T) {
+	tests := map[string]Format{
+		"results.csv":        FormatCSV,
+		"/tmp/Results.TSV":   FormatTSV,
+		"rows.jsonl":         FormatJSONLines,
+		"graph.ttl":          FormatTurtle,
+		"out/results.srj":    FormatSPARQLJSON,
+		"report.md":          FormatMarkdown,
+		"dir.with.dots/x.nt": FormatNTriples,
+	}
+	for path, want := range tests {
+		got, err := FormatForPath(path)
+		if assert.NoError(t, err, path) {
+			assert.Equal(t, want, got, path)
+		}
+	}
+	_, err := FormatForPath("results")
+	assert.EqualError(t, err, `dwapi: no format for the extension of "results"`)
+}
@@ -0,0 +1,196 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package dwapi
+
+import (
+	"io"
+)
+
+// The interfaces below describe the method sets of the services hanging off of `Client`. Code that
+// depends on this package can accept these interfaces instead of the concrete services, and substitute
+// the fakes from the `dwapimock` package in tests.
+//
+//go:generate go run ../dwapimock/internal/gen -in interfaces.go -out ../dwapimock/mocks.go
+
+// DatasetAPI is the method set of `DatasetService`.
+type DatasetAPI interface {
+	AddFilesFromURLs(owner, datasetid string, body *[]FileCreateRequest) (SuccessResponse, error)
+	AssociateDOI(owner, datasetid, doi string) (SuccessResponse, error)
+	AssociateDOIWithVersion(owner, datasetid, versionid, doi string) (SuccessResponse, error)
+	Contributing() ([]DatasetSummaryResponse, error)
+	Create(owner string, body *DatasetCreateRequest) (DatasetCreateResponse, error)
+	CreateOrReplace(owner, id string, body *DatasetReplaceRequest) (SuccessResponse, error)
+	Delete(owner, datasetid string) (SuccessResponse, error)
+	DeleteDOI(owner, datasetid, doi string) (SuccessResponse, error)
+	DeleteDOIAssociatedWithVersion(owner, datasetid, versionid, doi string) (SuccessResponse, error)
+	DownloadFile(owner, datasetid, filename string) (io.Reader, error)
+	DownloadAndSaveFile(owner, datasetid, filename, path string) (SuccessResponse, error)
+	Download(owner, datasetid, filename string) (io.Reader, error)
+	DownloadAndSave(owner, datasetid, path string) (SuccessResponse, error)
+	Liked() ([]DatasetSummaryResponse, error)
+	ListQueries(owner, datasetid string) ([]QuerySummaryResponse, error)
+	Owned() ([]DatasetSummaryResponse, error)
+	Retrieve(owner, datasetid string) (DatasetSummaryResponse, error)
+	RetrieveVersion(owner, datasetid, versionid string) (DatasetSummaryResponse, error)
+	Sync(owner, datasetid string) (SuccessResponse, error)
+	Update(owner, id string, body *DatasetUpdateRequest) (SuccessResponse, error)
+	UploadFile(owner, id, filename, path string, expandArchive bool) (SuccessResponse, error)
+}
+
+// DOIAPI is the method set of `DoiService`.
+type DOIAPI interface {
+	Associate(owner, datasetid, doi string) (SuccessResponse, error)
+	AssociateWithVersion(owner, datasetid, versionid, doi string) (SuccessResponse, error)
+	Delete(owner, datasetid, doi string) (SuccessResponse, error)
+	DeleteAssociatedWithVersion(owner, datasetid, versionid, doi string) (SuccessResponse, error)
+}
+
+// FileAPI is the method set of `FileService`.
+type FileAPI interface {
+	AddFilesFromURLs(owner, id string, body *[]FileCreateRequest) (SuccessResponse, error)
+	Delete(owner, id, filename string) (SuccessResponse, error)
+	Download(owner, id, filename string) (io.ReadCloser, error)
+	DownloadAndSave(owner, id, filename, path string) (SuccessResponse, error)
+	DownloadDataset(owner, id string) (io.ReadCloser, error)
+	DownloadAndSaveDataset(owner, id, path string) (SuccessResponse, error)
+	Sync(owner, id string) (SuccessResponse, error)
+	Upload(owner, id, filename, path string, expandArchive bool) (SuccessResponse, error)
+	UploadStream(owner, id, filename string, body io.Reader, expandArchive bool) (SuccessResponse, error)
+}
+
+// InsightAPI is the method set of `InsightService`.
+type InsightAPI interface {
+	Create(owner, projectid string, body *InsightCreateRequest) (InsightCreateResponse, error)
+	Delete(owner, projectid, insightid string) (SuccessResponse, error)
+	List(owner, projectid string) ([]InsightSummaryResponse, error)
+	Replace(owner, projectid, insightid string, body *InsightReplaceRequest) (SuccessResponse, error)
+	Retrieve(owner, projectid, insightid string) (InsightSummaryResponse, error)
+	RetrieveVersion(owner, projectid, insightid, versionid string) (InsightSummaryResponse, error)
+	Update(owner, projectid, insightid string, body *InsightUpdateRequest) (SuccessResponse, error)
+}
+
+// ProjectAPI is the method set of `ProjectService`.
+type ProjectAPI interface {
+	AddFilesFromURLs(owner, projectid string, body *[]FileCreateRequest) (SuccessResponse, error)
+	Contributing() ([]ProjectSummaryResponse, error)
+	Create(owner string, body *ProjectCreateOrUpdateRequest) (ProjectCreateResponse, error)
+	CreateOrReplace(owner, projectid string, body *ProjectCreateOrUpdateRequest) (SuccessResponse, error)
+	Delete(owner, projectid string) (SuccessResponse, error)
+	DownloadFile(owner, projectid, filename string) (io.Reader, error)
+	DownloadAndSaveFile(owner, projectid, filename, path string) (SuccessResponse, error)
+	Download(owner, projectid, filename string) (io.Reader, error)
+	DownloadAndSave(owner, projectid, path string) (SuccessResponse, error)
+	Liked() ([]ProjectSummaryResponse, error)
+	LinkDataset(owner, projectid, linkedDatasetOwner, linkedDatasetid string) (SuccessResponse, error)
+	ListQueries(owner, projectid string) ([]QuerySummaryResponse, error)
+	Owned() ([]ProjectSummaryResponse, error)
+	Retrieve(owner, projectid string) (ProjectSummaryResponse, error)
+	RetrieveVersion(owner, projectid, versionid string) (ProjectSummaryResponse, error)
+	Sync(owner, projectid string) (SuccessResponse, error)
+	UnlinkDataset(owner, projectid, linkedDatasetOwner, linkedDatasetid string) (SuccessResponse, error)
+	Update(owner, id string, body *ProjectCreateOrUpdateRequest) (SuccessResponse, error)
+	UploadFile(owner, id, filename, path string, expandArchive bool) (SuccessResponse, error)
+}
+
+// QueryAPI is the method set of `QueryService`.
+type QueryAPI interface {
+	CreateSavedQueryInDataset(owner, datasetid string, body *QueryCreateRequest) (QuerySummaryResponse, error)
+	CreateSavedQueryInProject(owner, projectid string, body *QueryCreateRequest) (QuerySummaryResponse, error)
+	DeleteSavedQueryInDataset(owner, datasetid, queryid string) (SuccessResponse, error)
+	DeleteSavedQueryInProject(owner, projectid, queryid string) (SuccessResponse, error)
+	DiffVersions(queryid, fromVersion, toVersion string) (string, error)
+	ExecuteBatch(queries map[string]BatchQuery, options *BatchOptions) map[string]BatchResult
+	ExecuteSavedQuery(queryid, acceptType string, body *SavedQueryExecutionRequest) (io.ReadCloser, error)
+	ExecuteSavedQueryAndSave(queryid, acceptType, path string, body *SavedQueryExecutionRequest) (
+		SuccessResponse, error)
+	ExecuteSavedQueryInto(queryid string, body *SavedQueryExecutionRequest, dest interface{}) error
+	ExecuteSavedQueryRows(queryid, acceptType string, body *SavedQueryExecutionRequest) (*Rows, error)
+	ExecuteSavedQueryToFile(queryid, path string, body *SavedQueryExecutionRequest) (SuccessResponse, error)
+	ExecuteSavedQueryVersion(owner, id, queryid, versionid, acceptType string, body *SavedQueryExecutionRequest) (
+		io.ReadCloser, error)
+	ExecuteSPARQL(owner, id, acceptType string, body *SPARQLQueryRequest) (io.ReadCloser, error)
+	ExecuteSPARQLAndSave(owner, id, acceptType, path string, body *SPARQLQueryRequest) (SuccessResponse, error)
+	ExecuteSPARQLInto(owner, id string, body *SPARQLQueryRequest, dest interface{}) error
+	ExecuteSPARQLRows(owner, id, acceptType string, body *SPARQLQueryRequest) (*Rows, error)
+	ExecuteSPARQLToFile(owner, id, path string, body *SPARQLQueryRequest) (SuccessResponse, error)
+	ExecuteSQL(owner, id, acceptType string, body *SQLQueryRequest) (io.ReadCloser, error)
+	ExecuteSQLAcross(datasets []DatasetOrProjectIdentifier, acceptType string, body *SQLQueryRequest,
+		options *BatchOptions) (*FanOutRows, error)
+	ExecuteSQLAndSave(owner, id, acceptType, path string, body *SQLQueryRequest) (SuccessResponse, error)
+	ExecuteSQLChunked(owner, id string, body *SQLQueryRequest, options *ChunkOptions) (*ChunkedRows, error)
+	ExecuteSQLInto(owner, id string, body *SQLQueryRequest, dest interface{}) error
+	ExecuteSQLRows(owner, id, acceptType string, body *SQLQueryRequest) (*Rows, error)
+	ExecuteSQLToFile(owner, id, path string, body *SQLQueryRequest) (SuccessResponse, error)
+	ListQueriesAssociatedWithDataset(owner, datasetid string) ([]QuerySummaryResponse, error)
+	ListQueriesAssociatedWithProject(owner, projectid string) ([]QuerySummaryResponse, error)
+	ListVersions(queryid string) ([]QuerySummaryResponse, error)
+	Retrieve(queryid string) (QuerySummaryResponse, error)
+	RetrieveVersion(queryid, versionid string) (QuerySummaryResponse, error)
+	RollbackSavedQueryInDataset(owner, datasetid, queryid, versionid string) (QuerySummaryResponse, error)
+	RollbackSavedQueryInProject(owner, projectid, queryid, versionid string) (QuerySummaryResponse, error)
+	UpdateSavedQueryInDataset(owner, datasetid, queryid string, body *QueryUpdateRequest) (
+		QuerySummaryResponse, error)
+	UpdateSavedQueryInProject(owner, projectid, queryid string, body *QueryUpdateRequest) (
+		QuerySummaryResponse, error)
+}
+
+// StreamAPI is the method set of `StreamService`.
+type StreamAPI interface {
+	Append(owner, id, streamid string, body io.Reader) (SuccessResponse, error)
+	Delete(owner, id, streamid string) (SuccessResponse, error)
+	RetrieveSchema(owner, id, streamid string) (StreamSchema, error)
+	SetOrUpdateSchema(owner, id, streamid string, body *StreamSchemaUpdateRequest) (SuccessResponse, error)
+}
+
+// UserAPI is the method set of `UserService`.
+type UserAPI interface {
+	DatasetsContributing() ([]DatasetSummaryResponse, error)
+	DatasetsLiked() ([]DatasetSummaryResponse, error)
+	DatasetsOwned() ([]DatasetSummaryResponse, error)
+	ProjectsContributing() ([]ProjectSummaryResponse, error)
+	ProjectsLiked() ([]ProjectSummaryResponse, error)
+	ProjectsOwned() ([]ProjectSummaryResponse, error)
+	Retrieve(agentid string) (UserInfoResponse, error)
+	Self() (UserInfoResponse, error)
+}
+
+// WebhookAPI is the method set of `WebhookService`.
+type WebhookAPI interface {
+	List() ([]Subscription, error)
+	RetrieveAccountSubscription(user string) (Subscription, error)
+	RetrieveDatasetSubscription(owner, datasetid string) (Subscription, error)
+	RetrieveProjectSubscription(owner, projectid string) (Subscription, error)
+	SubscribeToAccount(user string, body *SubscriptionCreateRequest) (SuccessResponse, error)
+	SubscribeToDataset(owner, datasetid string, body *SubscriptionCreateRequest) (SuccessResponse, error)
+	SubscribeToProject(owner, projectid string, body *SubscriptionCreateRequest) (SuccessResponse, error)
+	UnsubscribeFromAccount(user string) (SuccessResponse, error)
+	UnsubscribeFromDataset(owner, datasetid string) (SuccessResponse, error)
+	UnsubscribeFromProject(owner, projectid string) (SuccessResponse, error)
+}
+
+var (
+	_ DatasetAPI = (*DatasetService)(nil)
+	_ DOIAPI     = (*DoiService)(nil)
+	_ FileAPI    = (*FileService)(nil)
+	_ InsightAPI = (*InsightService)(nil)
+	_ ProjectAPI = (*ProjectService)(nil)
+	_ QueryAPI   = (*QueryService)(nil)
+	_ StreamAPI  = (*StreamService)(nil)
+	_ UserAPI    = (*UserService)(nil)
+	_ WebhookAPI = (*WebhookService)(nil)
+)
@@ -17,6 +17,8 @@
 
 package dwapi
 
+import "encoding/json"
+
 type DatasetCreateRequest struct {
 	Description string              `json:"description,omitempty"`
 	Files       []FileCreateRequest `json:"files,omitempty"`
@@ -30,6 +32,8 @@ type DatasetCreateRequest struct {
 type DatasetCreateResponse struct {
 	Message string `json:"message,omitempty"`
 	URI     string `json:"uri"`
+
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type DatasetOrProjectIdentifier struct {
@@ -65,6 +69,8 @@ type DatasetSummaryResponse struct {
 	Version     string                    `json:"version"`
 	VersionDois []DigitalObjectIdentifier `json:"versionDois,omitempty"`
 	Visibility  string                    `json:"visibility"`
+
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type DatasetUpdateRequest struct {
@@ -80,6 +86,8 @@ type DatasetUpdateRequest struct {
 type DigitalObjectIdentifier struct {
 	Created string `json:"created"`
 	Doi     string `json:"doi"`
+
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type FileCreateRequest struct {
@@ -101,12 +109,15 @@ type FileSourceCreateOrUpdateRequest struct {
 }
 
 type FileSourceResponse struct {
-	Authorization   WebAuthorization    `json:"authorization,omitempty"`
-	Credentials     WebCredentials      `json:"credentials,omitempty"`
-	ExpandArchive   bool                `json:"expandArchive,omitempty"`
-	Method          string              `json:"method,omitempty"`
-	SyncStatus      string              `json:"syncStatus"`
-	SyncSummary     []string            `json:"labels,omitempty"`
+	Authorization WebAuthorization `json:"authorization,omitempty"`
+	Credentials   WebCredentials   `json:"credentials,omitempty"`
+	ExpandArchive bool             `json:"expandArchive,omitempty"`
+	Method        string           `json:"method,omitempty"`
+	SyncStatus    string           `json:"syncStatus"`
+	// Deprecated: SyncSummary was read from a "labels" field by mistake and is never set. Use
+	// SyncSummaryText.
+	SyncSummary     []string            `json:"-"`
+	SyncSummaryText string              `json:"syncSummary,omitempty"`
 	LastSyncFailure string              `json:"lastSyncFailure,omitempty"`
 	LastSyncStart   string              `json:"lastSyncStart,omitempty"`
 	LastSyncSuccess string              `json:"lastSyncSuccess,omitempty"`
@@ -114,6 +125,8 @@ type FileSourceResponse struct {
 	RequestEntity   string              `json:"requestEntity,omitempty"`
 	RequestHeaders  interface{}         `json:"requestHeaders,omitempty"`
 	URL             string              `json:"url,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type FileSummaryResponse struct {
@@ -124,6 +137,8 @@ type FileSummaryResponse struct {
 	SizeInBytes uint               `json:"sizeInBytes,omitempty"`
 	Source      FileSourceResponse `json:"source,omitempty"`
 	Updated     string             `json:"updated"`
+
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type InsightBody struct {
@@ -144,6 +159,8 @@ type InsightCreateRequest struct {
 type InsightCreateResponse struct {
 	Message string `json:"message,omitempty"`
 	URI     string `json:"uri"`
+
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type InsightReplaceRequest struct {
@@ -167,6 +184,8 @@ type InsightSummaryResponse struct {
 	Title           string      `json:"title"`
 	Updated         string      `json:"updated"`
 	Version         string      `json:"version"`
+
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type InsightUpdateRequest struct {
@@ -196,6 +215,8 @@ type LinkedDatasetSummaryResponse struct {
 	Updated     string   `json:"updated"`
 	Version     string   `json:"version"`
 	Visibility  string   `json:"visibility"`
+
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type OauthTokenReference struct {
@@ -218,6 +239,8 @@ type ProjectCreateOrUpdateRequest struct {
 type ProjectCreateResponse struct {
 	Message string `json:"message,omitempty"`
 	URI     string `json:"uri"`
+
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type ProjectSummaryResponse struct {
@@ -236,6 +259,8 @@ type ProjectSummaryResponse struct {
 	Updated        string                         `json:"updated"`
 	Version        string                         `json:"version"`
 	Visibility     string                         `json:"visibility"`
+
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type QueryCreateRequest struct {
@@ -257,9 +282,12 @@ type QuerySummaryResponse struct {
 	Language   string                    `json:"language,omitempty"`
 	Name       string                    `json:"name,omitempty"`
 	Owner      string                    `json:"owner,omitempty"`
+	Published  bool                      `json:"published,omitempty"`
 	Updated    string                    `json:"updated,omitempty"`
 	Version    string                    `json:"version,omitempty"`
 	Parameters map[string]QueryParameter `json:"parameters,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type QueryUpdateRequest struct {
@@ -276,16 +304,25 @@ type SavedQueryExecutionRequest struct {
 
 type SPARQLQueryRequest struct {
 	Query string `json:"query"`
+	// Parameters binds variables of the query, named with a leading $, e.g. $region, to RDF literals.
+	// It's the same parameters object that SavedQueryExecutionRequest sends when executing saved queries.
+	Parameters map[string]string `json:"parameters,omitempty"`
 }
 
 type SQLQueryRequest struct {
-	Query              string `json:"query"`
-	IncludeTableSchema bool   `json:"includeTableSchema,omitempty"`
+	Query string `json:"query"`
+	// Parameters binds the positional ? parameters of the query, named $data_world_param0,
+	// $data_world_param1, ..., to RDF literals. It's the same parameters object that
+	// SavedQueryExecutionRequest sends when executing saved queries.
+	Parameters         map[string]string `json:"parameters,omitempty"`
+	IncludeTableSchema bool              `json:"includeTableSchema,omitempty"`
 }
 
 type StreamSchema struct {
 	PrimaryKeyFields []string `json:"primaryKeyFields,omitempty"`
 	SequenceField    string   `json:"sequenceField,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type StreamSchemaUpdateRequest struct {
@@ -299,6 +336,8 @@ type Subscription struct {
 	Events  []string                   `json:"events"`
 	Project DatasetOrProjectIdentifier `json:"project,omitempty"`
 	User    UserIdentifier             `json:"user,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type SubscriptionCreateRequest struct {
@@ -307,6 +346,8 @@ type SubscriptionCreateRequest struct {
 
 type SuccessResponse struct {
 	Message string `json:"message,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type UserIdentifier struct {
@@ -319,6 +360,8 @@ type UserInfoResponse struct {
 	DisplayName string `json:"displayName,omitempty"`
 	ID          string `json:"id"`
 	Updated     string `json:"updated"`
+
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type WebAuthorization struct {
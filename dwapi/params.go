@@ -0,0 +1,182 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package dwapi
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/datadotworld/dwapi-go/rdf"
+)
+
+// NewSQLQueryRequest returns a request for a SQL query with positional ? parameters. The arguments
+// are sent as parameters of the request, written as RDF literals by FormatParameter, rather than
+// being interpolated into the query:
+//
+//	req, err := dwapi.NewSQLQueryRequest("SELECT * FROM sales WHERE region = ? AND total > ?", region, 1000)
+func NewSQLQueryRequest(query string, args ...interface{}) (*SQLQueryRequest, error) {
+	if n := CountPlaceholders(query); n != len(args) {
+		return nil, fmt.Errorf("dwapi: expected %d arguments, got %d", n, len(args))
+	}
+	req := &SQLQueryRequest{Query: query}
+	for i, a := range args {
+		lit, err := FormatParameter(a)
+		if err != nil {
+			return nil, fmt.Errorf("dwapi: parameter %d: %s", i+1, err)
+		}
+		if req.Parameters == nil {
+			req.Parameters = make(map[string]string, len(args))
+		}
+		// The API names positional parameters $data_world_param0, $data_world_param1, ...
+		req.Parameters["$data_world_param"+strconv.Itoa(i)] = lit
+	}
+	return req, nil
+}
+
+var sparqlVariable = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// NewSPARQLQueryRequest returns a request for a SPARQL query whose variables are bound to the given
+// values, written as RDF literals by FormatParameter. The names of the parameters may start with $ or
+// ?, and each must be a variable of the query:
+//
+//	req, err := dwapi.NewSPARQLQueryRequest("SELECT ?s WHERE { ?s :region $region }",
+//		map[string]interface{}{"region": region})
+func NewSPARQLQueryRequest(query string, params map[string]interface{}) (*SPARQLQueryRequest, error) {
+	req := &SPARQLQueryRequest{Query: query}
+	for name, v := range params {
+		name = strings.TrimLeft(name, "$?")
+		if !sparqlVariable.MatchString(name) {
+			return nil, fmt.Errorf("dwapi: invalid parameter name %q", name)
+		}
+		if !regexp.MustCompile(`[$?]` + name + `\b`).MatchString(query) {
+			return nil, fmt.Errorf("dwapi: parameter $%s is not a variable of the query", name)
+		}
+		lit, err := FormatParameter(v)
+		if err != nil {
+			return nil, fmt.Errorf("dwapi: parameter $%s: %s", name, err)
+		}
+		if req.Parameters == nil {
+			req.Parameters = make(map[string]string, len(params))
+		}
+		req.Parameters["$"+name] = lit
+	}
+	return req, nil
+}
+
+// FormatParameter writes a Go value as the RDF literal that the API expects for query parameters, e.g.
+// "42"^^<http://www.w3.org/2001/XMLSchema#integer>. Strings and byte slices are plain literals,
+// integers are xsd:integer, floats xsd:decimal (or xsd:double if they aren't finite), booleans
+// xsd:boolean, and times xsd:dateTime. Nil isn't a valid parameter.
+func FormatParameter(v interface{}) (string, error) {
+	switch v := v.(type) {
+	case string:
+		return rdf.Quote(v), nil
+	case []byte:
+		return rdf.Quote(string(v)), nil
+	case int:
+		return typed(strconv.FormatInt(int64(v), 10), "integer"), nil
+	case int8:
+		return typed(strconv.FormatInt(int64(v), 10), "integer"), nil
+	case int16:
+		return typed(strconv.FormatInt(int64(v), 10), "integer"), nil
+	case int32:
+		return typed(strconv.FormatInt(int64(v), 10), "integer"), nil
+	case int64:
+		return typed(strconv.FormatInt(v, 10), "integer"), nil
+	case uint:
+		return typed(strconv.FormatUint(uint64(v), 10), "integer"), nil
+	case uint8:
+		return typed(strconv.FormatUint(uint64(v), 10), "integer"), nil
+	case uint16:
+		return typed(strconv.FormatUint(uint64(v), 10), "integer"), nil
+	case uint32:
+		return typed(strconv.FormatUint(uint64(v), 10), "integer"), nil
+	case uint64:
+		return typed(strconv.FormatUint(v, 10), "integer"), nil
+	case float32:
+		return formatFloat(float64(v), 32), nil
+	case float64:
+		return formatFloat(v, 64), nil
+	case bool:
+		return typed(strconv.FormatBool(v), "boolean"), nil
+	case time.Time:
+		return typed(v.Format(time.RFC3339Nano), "dateTime"), nil
+	case nil:
+		return "", fmt.Errorf("NULL can't be a parameter")
+	}
+	return "", fmt.Errorf("unsupported type %T", v)
+}
+
+func formatFloat(f float64, bits int) string {
+	switch {
+	case math.IsNaN(f):
+		return typed("NaN", "double")
+	case math.IsInf(f, 1):
+		return typed("INF", "double")
+	case math.IsInf(f, -1):
+		return typed("-INF", "double")
+	}
+	return typed(strconv.FormatFloat(f, 'f', -1, bits), "decimal")
+}
+
+func typed(lexical, datatype string) string {
+	return rdf.Quote(lexical) + "^^<" + xsd + datatype + ">"
+}
+
+// SQLIdent quotes a table or column name as a data.world SQL identifier, with backticks, doubling
+// any backtick in the name.
+func SQLIdent(name string) string {
+	return "`" + strings.Replace(name, "`", "``", -1) + "`"
+}
+
+// CountPlaceholders counts the ? placeholders of a SQL query, outside of quoted strings, quoted
+// identifiers and comments.
+func CountPlaceholders(query string) int {
+	n := 0
+	for i := 0; i < len(query); i++ {
+		switch c := query[i]; {
+		case c == '?':
+			n++
+		case c == '\'' || c == '"' || c == '`':
+			// A doubled quote is an escaped quote, and is skipped as an empty string followed by
+			// another quoted string.
+			end := strings.IndexByte(query[i+1:], c)
+			if end < 0 {
+				return n
+			}
+			i += end + 1
+		case c == '-' && strings.HasPrefix(query[i:], "--"):
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				return n
+			}
+			i += end
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				return n
+			}
+			i += end + 3
+		}
+	}
+	return n
+}
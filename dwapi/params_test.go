@@ -0,0 +1,133 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package dwapi
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatParameter(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  string
+	}{
+		{"it's \"quoted\"\n", `"it's \"quoted\"\n"`},
+		{[]byte(`C:\`), `"C:\\"`},
+		{-3, `"-3"^^<http://www.w3.org/2001/XMLSchema#integer>`},
+		{int64(-3), `"-3"^^<http://www.w3.org/2001/XMLSchema#integer>`},
+		{uint8(7), `"7"^^<http://www.w3.org/2001/XMLSchema#integer>`},
+		{uint64(math.MaxUint64), `"18446744073709551615"^^<http://www.w3.org/2001/XMLSchema#integer>`},
+		{0.1, `"0.1"^^<http://www.w3.org/2001/XMLSchema#decimal>`},
+		{float32(0.1), `"0.1"^^<http://www.w3.org/2001/XMLSchema#decimal>`},
+		{1e21, `"1000000000000000000000"^^<http://www.w3.org/2001/XMLSchema#decimal>`},
+		{math.Inf(-1), `"-INF"^^<http://www.w3.org/2001/XMLSchema#double>`},
+		{math.NaN(), `"NaN"^^<http://www.w3.org/2001/XMLSchema#double>`},
+		{true, `"true"^^<http://www.w3.org/2001/XMLSchema#boolean>`},
+		{time.Date(2018, 8, 3, 15, 56, 41, 0, time.UTC), `"2018-08-03T15:56:41Z"^^<http://www.w3.org/2001/XMLSchema#dateTime>`},
+	}
+	for _, test := range tests {
+		got, err := FormatParameter(test.value)
+		if assert.NoError(t, err) {
+			assert.Equal(t, test.want, got)
+		}
+	}
+	_, err := FormatParameter(struct{}{})
+	assert.EqualError(t, err, "unsupported type struct {}")
+	_, err = FormatParameter(nil)
+	assert.EqualError(t, err, "NULL can't be a parameter")
+}
+
+func TestCountPlaceholders(t *testing.T) {
+	tests := map[string]int{
+		"SELECT * FROM t": 0,
+		"SELECT * FROM t WHERE a = ? AND b IN (?, ?)":         3,
+		"SELECT '?', \"a?\", `b?` FROM t WHERE c = ?":         1,
+		"SELECT 'it''s?' FROM t WHERE c = ?":                  1,
+		"SELECT a -- why?\nFROM t WHERE b = ? /* and ? */":    1,
+		"SELECT a FROM t WHERE b = ? AND c = 'unterminated ?": 1,
+	}
+	for query, want := range tests {
+		assert.Equal(t, want, CountPlaceholders(query), query)
+	}
+}
+
+func TestNewSQLQueryRequest(t *testing.T) {
+	req, err := NewSQLQueryRequest("SELECT * FROM sales WHERE region = ? AND total > ?", "EMEA'; DROP TABLE sales --", 1000)
+	assert.NoError(t, err)
+	assert.Equal(t, &SQLQueryRequest{
+		Query: "SELECT * FROM sales WHERE region = ? AND total > ?",
+		Parameters: map[string]string{
+			"$data_world_param0": `"EMEA'; DROP TABLE sales --"`,
+			"$data_world_param1": `"1000"^^<http://www.w3.org/2001/XMLSchema#integer>`,
+		},
+	}, req)
+
+	req, err = NewSQLQueryRequest("SELECT 1")
+	assert.NoError(t, err)
+	assert.Nil(t, req.Parameters)
+
+	_, err = NewSQLQueryRequest("SELECT * FROM sales WHERE region = ?")
+	assert.EqualError(t, err, "dwapi: expected 1 arguments, got 0")
+	_, err = NewSQLQueryRequest("SELECT * FROM sales WHERE region = ?", nil)
+	assert.EqualError(t, err, "dwapi: parameter 1: NULL can't be a parameter")
+}
+
+func TestNewSPARQLQueryRequest(t *testing.T) {
+	query := "SELECT ?s WHERE { ?s :region $region ; :total ?total FILTER(?total > $min) }"
+	req, err := NewSPARQLQueryRequest(query, map[string]interface{}{"region": "EMEA", "$min": 1.5})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"$region": `"EMEA"`,
+		"$min":    `"1.5"^^<http://www.w3.org/2001/XMLSchema#decimal>`,
+	}, req.Parameters)
+
+	_, err = NewSPARQLQueryRequest(query, map[string]interface{}{"reg": "EMEA"})
+	assert.EqualError(t, err, "dwapi: parameter $reg is not a variable of the query")
+	_, err = NewSPARQLQueryRequest(query, map[string]interface{}{"a b": 1})
+	assert.EqualError(t, err, `dwapi: invalid parameter name "a b"`)
+	_, err = NewSPARQLQueryRequest(query, map[string]interface{}{"min": []int{1}})
+	assert.EqualError(t, err, "dwapi: parameter $min: unsupported type []int")
+}
+
+func TestQueryService_ExecuteSPARQLWithParameters(t *testing.T) {
+	setup()
+	defer teardown()
+
+	req, err := NewSPARQLQueryRequest("SELECT ?s WHERE { ?s :region $region }", map[string]interface{}{"region": "EMEA"})
+	if !assert.NoError(t, err) {
+		return
+	}
+	endpoint := "/sparql/" + testClientOwner + "/dataset"
+	mux.HandleFunc(endpoint, func(w http.ResponseWriter, r *http.Request) {
+		var body SPARQLQueryRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, map[string]string{"$region": `"EMEA"`}, body.Parameters)
+		w.Header().Set("Content-Type", "application/sparql-results+json")
+		_, _ = w.Write([]byte(`{"head": {"vars": []}, "results": {"bindings": []}}`))
+	})
+	r, err := dw.Query.ExecuteSPARQL(testClientOwner, "dataset", "application/sparql-results+json", req)
+	if assert.NoError(t, err) {
+		r.Close()
+	}
+}
@@ -18,12 +18,20 @@
 package dwapi
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"strings"
+	"sync"
+	"time"
 )
 
 type QueryService struct {
 	client *Client
+
+	// Cache, if set, keeps the results of ExecuteSQL and ExecuteSPARQL, and of the methods built on
+	// them, so that queries against unchanged datasets aren't run again.
+	Cache *ResultCache
 }
 
 // CreateSavedQueryInDataset creates a saved query in the specified dataset.
@@ -62,11 +70,80 @@ func (s *QueryService) DeleteSavedQueryInProject(owner, projectid, queryid strin
 	return
 }
 
-// ExecuteSavedQuery runs a saved query against a dataset or data project.
+// DiffVersions compares the bodies of two versions of a saved query, and returns their differences as a
+// unified diff, or an empty string if the bodies are the same.
+func (s *QueryService) DiffVersions(queryid, fromVersion, toVersion string) (response string, err error) {
+	from, err := s.RetrieveVersion(queryid, fromVersion)
+	if err != nil {
+		return
+	}
+	to, err := s.RetrieveVersion(queryid, toVersion)
+	if err != nil {
+		return
+	}
+	return unifiedDiff(from.Body, to.Body, queryid+"@"+fromVersion, queryid+"@"+toVersion), nil
+}
+
+// ExecuteBatch runs the named queries concurrently, reading their whole responses, and returns the
+// result of each by name. A query that fails doesn't stop the others.
+func (s *QueryService) ExecuteBatch(queries map[string]BatchQuery, options *BatchOptions) (
+	response map[string]BatchResult) {
+	concurrency := defaultConcurrency
+	var limiter *RateLimiter
+	if options != nil {
+		if options.Concurrency > 0 {
+			concurrency = options.Concurrency
+		}
+		limiter = options.RateLimiter
+	}
+
+	response = make(map[string]BatchResult, len(queries))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for name, q := range queries {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(name string, q BatchQuery) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if limiter != nil {
+				limiter.Wait()
+			}
+			start := time.Now()
+			body, err := s.executeBatchQuery(q)
+			result := BatchResult{Body: body, Err: err, Duration: time.Since(start)}
+			mu.Lock()
+			response[name] = result
+			mu.Unlock()
+		}(name, q)
+	}
+	wg.Wait()
+	return
+}
+
+// ExecuteSavedQuery runs a saved query against a dataset or data project. If an acceptType is given,
+// the saved query is retrieved first to check that its results can be returned in that type.
 //
 // SPARQL results are available in a variety of formats. See https://apidocs.data.world/api/queries/executequery
 // for the full list of return types.
 func (s *QueryService) ExecuteSavedQuery(queryid, acceptType string, body *SavedQueryExecutionRequest) (
+	response io.ReadCloser, err error) {
+	if acceptType != "" {
+		kind, err := s.savedQueryKind(queryid)
+		if err != nil {
+			return nil, err
+		}
+		if err = kind.Validate(Format(acceptType)); err != nil {
+			return nil, err
+		}
+	}
+	return s.executeSavedQuery(queryid, acceptType, body)
+}
+
+func (s *QueryService) executeSavedQuery(queryid, acceptType string, body *SavedQueryExecutionRequest) (
 	response io.ReadCloser, err error) {
 	endpoint := fmt.Sprintf("/queries/%s/results", queryid)
 	headers := s.client.buildHeaders(POST, endpoint)
@@ -90,22 +167,100 @@ func (s *QueryService) ExecuteSavedQueryAndSave(queryid, acceptType, path string
 	if err != nil {
 		return
 	}
+	return s.saveResults(r, path, "", "")
+}
 
-	if err = s.client.saveToFile(path, r); err != nil {
+// ExecuteSavedQueryInto runs a saved query against a dataset or data project and decodes the results
+// into dest, a pointer to a slice of structs. See `DecodeResults` for how columns map to fields.
+func (s *QueryService) ExecuteSavedQueryInto(queryid string, body *SavedQueryExecutionRequest,
+	dest interface{}) (err error) {
+	r, err := s.ExecuteSavedQuery(queryid, string(FormatSPARQLJSON), body)
+	if err != nil {
 		return
 	}
+	defer r.Close()
+	return DecodeResults(r, string(FormatSPARQLJSON), dest)
+}
 
-	return SuccessResponse{
-		Message: fmt.Sprintf("Results saved to %s", path),
-	}, nil
+// ExecuteSavedQueryRows runs a saved query against a dataset or data project and returns the results,
+// to be read a row at a time. The acceptType is one of the types supported by `DecodeResults`, or
+// SPARQL JSON results if empty.
+func (s *QueryService) ExecuteSavedQueryRows(queryid, acceptType string, body *SavedQueryExecutionRequest) (
+	response *Rows, err error) {
+	if acceptType == "" {
+		acceptType = string(FormatSPARQLJSON)
+	}
+	r, err := s.ExecuteSavedQuery(queryid, acceptType, body)
+	if err != nil {
+		return
+	}
+	return NewRows(r, acceptType)
 }
 
-// ExecuteSPARQL runs a SPARQL query against a dataset or data project.
+// ExecuteSavedQueryToFile runs a saved query and saves the results to a file, in the format of its
+// extension, e.g. CSV for results.csv, or a Markdown or HTML table for results.md or results.html.
+// The saved query is retrieved first to check that its results can be returned in that format.
+func (s *QueryService) ExecuteSavedQueryToFile(queryid, path string, body *SavedQueryExecutionRequest) (
+	response SuccessResponse, err error) {
+	kind, err := s.savedQueryKind(queryid)
+	if err != nil {
+		return
+	}
+	format, request, err := formatForPath(path, kind)
+	if err != nil {
+		return
+	}
+	r, err := s.executeSavedQuery(queryid, string(request), body)
+	if err != nil {
+		return
+	}
+	return s.saveResults(r, path, request, format)
+}
+
+// ExecuteSavedQueryVersion runs a version of a saved query, rather than its current version, against
+// the dataset or data project that the query is saved in. The API has no endpoint executing a
+// version, so the version is retrieved and its body run with `Query.ExecuteSQL` or
+// `Query.ExecuteSPARQL`. MaxRows can't be applied to those, and is an error.
+func (s *QueryService) ExecuteSavedQueryVersion(owner, id, queryid, versionid, acceptType string,
+	body *SavedQueryExecutionRequest) (response io.ReadCloser, err error) {
+	if body == nil {
+		body = &SavedQueryExecutionRequest{}
+	}
+	if body.MaxRows != 0 {
+		return nil, errors.New("dwapi: MaxRows can't be applied to a version of a saved query")
+	}
+	v, err := s.RetrieveVersion(queryid, versionid)
+	if err != nil {
+		return
+	}
+	switch strings.ToUpper(v.Language) {
+	case "SQL":
+		return s.ExecuteSQL(owner, id, acceptType, &SQLQueryRequest{
+			Query:              v.Body,
+			Parameters:         body.Parameters,
+			IncludeTableSchema: body.IncludeTableSchema,
+		})
+	case "SPARQL":
+		return s.ExecuteSPARQL(owner, id, acceptType, &SPARQLQueryRequest{Query: v.Body, Parameters: body.Parameters})
+	}
+	return nil, fmt.Errorf("dwapi: saved query %s has an unknown language %q", queryid, v.Language)
+}
+
+// ExecuteSPARQL runs a SPARQL query against a dataset or data project. An acceptType is checked
+// against the form of the query, e.g. Turtle is rejected for a SELECT query.
 //
 // SPARQL results are available in a variety of formats. See https://apidocs.data.world/api/queries/sparqlpost
 // for the full list of return types.
 func (s *QueryService) ExecuteSPARQL(owner, id, acceptType string, body *SPARQLQueryRequest) (
 	response io.ReadCloser, err error) {
+	if acceptType != "" && body != nil {
+		// A query whose form isn't recognized is left for the API to report on.
+		if kind, err := SPARQLKind(body.Query); err == nil {
+			if err = kind.Validate(Format(acceptType)); err != nil {
+				return nil, err
+			}
+		}
+	}
 	endpoint := fmt.Sprintf("/sparql/%s/%s", owner, id)
 	headers := s.client.buildHeaders(POST, endpoint)
 	headers.AcceptType = acceptType
@@ -114,6 +269,9 @@ func (s *QueryService) ExecuteSPARQL(owner, id, acceptType string, body *SPARQLQ
 	if err != nil {
 		return
 	}
+	if s.Cache != nil {
+		return s.cachedRequest(owner, id, headers, b)
+	}
 	return s.client.rawRequest(headers, b)
 }
 
@@ -128,22 +286,69 @@ func (s *QueryService) ExecuteSPARQLAndSave(owner, id, acceptType, path string,
 	if err != nil {
 		return
 	}
+	return s.saveResults(r, path, "", "")
+}
 
-	if err = s.client.saveToFile(path, r); err != nil {
+// ExecuteSPARQLInto runs a SPARQL SELECT or ASK query against a dataset or data project and decodes
+// the results into dest, a pointer to a slice of structs. See `DecodeResults` for how variables map
+// to fields.
+func (s *QueryService) ExecuteSPARQLInto(owner, id string, body *SPARQLQueryRequest, dest interface{}) (
+	err error) {
+	r, err := s.ExecuteSPARQL(owner, id, string(FormatSPARQLJSON), body)
+	if err != nil {
 		return
 	}
+	defer r.Close()
+	return DecodeResults(r, string(FormatSPARQLJSON), dest)
+}
 
-	return SuccessResponse{
-		Message: fmt.Sprintf("Results saved to %s", path),
-	}, nil
+// ExecuteSPARQLRows runs a SPARQL SELECT or ASK query against a dataset or data project and returns
+// the results, to be read a row at a time. The acceptType is one of the types supported by
+// `DecodeResults`, or SPARQL JSON results if empty.
+func (s *QueryService) ExecuteSPARQLRows(owner, id, acceptType string, body *SPARQLQueryRequest) (
+	response *Rows, err error) {
+	if acceptType == "" {
+		acceptType = string(FormatSPARQLJSON)
+	}
+	r, err := s.ExecuteSPARQL(owner, id, acceptType, body)
+	if err != nil {
+		return
+	}
+	return NewRows(r, acceptType)
 }
 
-// ExecuteSQL runs a SQL query against a dataset or data project.
+// ExecuteSPARQLToFile runs a SPARQL query against a dataset or data project and saves the results to
+// a file, in the format of its extension, e.g. Turtle for graph.ttl, or a Markdown or HTML table for
+// results.md or results.html. The format must suit the form of the query.
+func (s *QueryService) ExecuteSPARQLToFile(owner, id, path string, body *SPARQLQueryRequest) (
+	response SuccessResponse, err error) {
+	kind, err := SPARQLKind(body.Query)
+	if err != nil {
+		return
+	}
+	format, request, err := formatForPath(path, kind)
+	if err != nil {
+		return
+	}
+	r, err := s.ExecuteSPARQL(owner, id, string(request), body)
+	if err != nil {
+		return
+	}
+	return s.saveResults(r, path, request, format)
+}
+
+// ExecuteSQL runs a SQL query against a dataset or data project. An acceptType is checked against the
+// formats of SQL results, e.g. Turtle is rejected.
 //
 // SQL results are available in a variety of formats. See https://apidocs.data.world/api/queries/sqlpost
 // for the full list of return types.
 func (s *QueryService) ExecuteSQL(owner, id, acceptType string, body *SQLQueryRequest) (
 	response io.ReadCloser, err error) {
+	if acceptType != "" {
+		if err = SQLQuery.Validate(Format(acceptType)); err != nil {
+			return
+		}
+	}
 	endpoint := fmt.Sprintf("/sql/%s/%s", owner, id)
 	headers := s.client.buildHeaders(POST, endpoint)
 	headers.AcceptType = acceptType
@@ -152,9 +357,81 @@ func (s *QueryService) ExecuteSQL(owner, id, acceptType string, body *SQLQueryRe
 	if err != nil {
 		return
 	}
+	if s.Cache != nil {
+		return s.cachedRequest(owner, id, headers, b)
+	}
 	return s.client.rawRequest(headers, b)
 }
 
+// ExecuteSQLAcross runs a SQL query against each of the datasets or projects, which must have the same
+// columns, and streams the union of their results, with the dataset or project of each row in its
+// first column, SourceColumn. The queries run concurrently as set by the options. Those that fail are
+// reported by Failures rather than ending the rows; an error is returned only if no dataset was given.
+func (s *QueryService) ExecuteSQLAcross(datasets []DatasetOrProjectIdentifier, acceptType string,
+	body *SQLQueryRequest, options *BatchOptions) (response *FanOutRows, err error) {
+	if len(datasets) == 0 {
+		return nil, errors.New("dwapi: no datasets to run the query against")
+	}
+	concurrency := defaultConcurrency
+	var limiter *RateLimiter
+	if options != nil {
+		if options.Concurrency > 0 {
+			concurrency = options.Concurrency
+		}
+		limiter = options.RateLimiter
+	}
+
+	u := &unionReader{
+		rows:  make(chan []*ResultTerm),
+		done:  make(chan struct{}),
+		ready: make(chan struct{}),
+	}
+	var wg sync.WaitGroup
+	finished := make(chan struct{})
+	go func() {
+		sem := make(chan struct{}, concurrency)
+		for _, d := range datasets {
+			select {
+			case sem <- struct{}{}:
+			case <-u.done:
+				u.fail(d, ErrRowsClosed)
+				continue
+			}
+			wg.Add(1)
+			go func(d DatasetOrProjectIdentifier) {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+				if limiter != nil {
+					limiter.Wait()
+				}
+				s.streamRows(u, d, acceptType, body)
+			}(d)
+		}
+		wg.Wait()
+		close(u.rows)
+		close(finished)
+	}()
+
+	select {
+	case <-u.ready:
+	case <-finished:
+		u.mu.Lock()
+		if u.cols == nil {
+			// Every query failed, so there are only the failures to report.
+			u.cols = []string{SourceColumn}
+		}
+		u.mu.Unlock()
+	}
+	rows, err := newRows(u, u)
+	if err != nil {
+		u.Close()
+		return nil, err
+	}
+	return &FanOutRows{Rows: rows, union: u}, nil
+}
+
 // ExecuteSQLAndSave runs a SQL query against a dataset or data project and saves the results to a file.
 //
 // SQL results are available in a variety of formats. See https://apidocs.data.world/api/queries/sqlpost
@@ -165,14 +442,66 @@ func (s *QueryService) ExecuteSQLAndSave(owner, id, acceptType, path string, bod
 	if err != nil {
 		return
 	}
+	return s.saveResults(r, path, "", "")
+}
 
-	if err = s.client.saveToFile(path, r); err != nil {
+// ExecuteSQLChunked runs a SQL query against a dataset or data project in chunks of rows, for results
+// too large for one request, and returns them as one result. The query is wrapped to order and page
+// its rows as the options say; see `ChunkOptions`. `RowsReader` writes the rows out as one file.
+func (s *QueryService) ExecuteSQLChunked(owner, id string, body *SQLQueryRequest, options *ChunkOptions) (
+	response *ChunkedRows, err error) {
+	c, err := s.newChunkReader(owner, id, body, options)
+	if err != nil {
+		return
+	}
+	rows, err := newRows(c, c)
+	if err != nil {
 		return
 	}
+	return &ChunkedRows{Rows: rows, chunks: c}, nil
+}
 
-	return SuccessResponse{
-		Message: fmt.Sprintf("Results saved to %s", path),
-	}, nil
+// ExecuteSQLInto runs a SQL query against a dataset or data project and decodes the results into
+// dest, a pointer to a slice of structs. See `DecodeResults` for how columns map to fields.
+func (s *QueryService) ExecuteSQLInto(owner, id string, body *SQLQueryRequest, dest interface{}) (
+	err error) {
+	r, err := s.ExecuteSQL(owner, id, string(FormatSPARQLJSON), body)
+	if err != nil {
+		return
+	}
+	defer r.Close()
+	return DecodeResults(r, string(FormatSPARQLJSON), dest)
+}
+
+// ExecuteSQLRows runs a SQL query against a dataset or data project and returns the results, to be
+// read a row at a time. The acceptType is one of the types supported by `DecodeResults`, or SPARQL
+// JSON results, which carry the datatypes of the columns, if empty.
+func (s *QueryService) ExecuteSQLRows(owner, id, acceptType string, body *SQLQueryRequest) (
+	response *Rows, err error) {
+	if acceptType == "" {
+		acceptType = string(FormatSPARQLJSON)
+	}
+	r, err := s.ExecuteSQL(owner, id, acceptType, body)
+	if err != nil {
+		return
+	}
+	return NewRows(r, acceptType)
+}
+
+// ExecuteSQLToFile runs a SQL query against a dataset or data project and saves the results to a
+// file, in the format of its extension, e.g. CSV for results.csv, or a Markdown or HTML table for
+// results.md or results.html.
+func (s *QueryService) ExecuteSQLToFile(owner, id, path string, body *SQLQueryRequest) (
+	response SuccessResponse, err error) {
+	format, request, err := formatForPath(path, SQLQuery)
+	if err != nil {
+		return
+	}
+	r, err := s.ExecuteSQL(owner, id, string(request), body)
+	if err != nil {
+		return
+	}
+	return s.saveResults(r, path, request, format)
 }
 
 // ListQueries lists the saved queries associated with a dataset.
@@ -201,6 +530,16 @@ func (s *QueryService) ListQueriesAssociatedWithProject(owner, projectid string)
 	return
 }
 
+// ErrVersionsNotListed is returned by `Query.ListVersions`.
+var ErrVersionsNotListed = errors.New("dwapi: the API doesn't list the versions of saved queries")
+
+// ListVersions would list the versions of a saved query, but the API has no endpoint for it, and it
+// always fails with ErrVersionsNotListed. The version of a query is in the QuerySummaryResponse that
+// `Query.Retrieve` and the updates of the query return, and `Query.RetrieveVersion` fetches a version.
+func (s *QueryService) ListVersions(queryid string) (response []QuerySummaryResponse, err error) {
+	return nil, ErrVersionsNotListed
+}
+
 // Retrieve fetches a saved query.
 //
 // Query definitions will be returned, not the query results. To retrieve the query results,
@@ -223,6 +562,73 @@ func (s *QueryService) RetrieveVersion(queryid, versionid string) (response Quer
 	return
 }
 
+// RollbackSavedQueryInDataset updates a saved query in the specified dataset to the name and content
+// of one of its versions, which creates a new version. The query stays published if it is.
+func (s *QueryService) RollbackSavedQueryInDataset(owner, datasetid, queryid, versionid string) (
+	response QuerySummaryResponse, err error) {
+	body, err := s.rollback(queryid, versionid)
+	if err != nil {
+		return
+	}
+	return s.UpdateSavedQueryInDataset(owner, datasetid, queryid, body)
+}
+
+// RollbackSavedQueryInProject updates a saved query in the specified project to the name and content
+// of one of its versions, which creates a new version. The query stays published if it is.
+func (s *QueryService) RollbackSavedQueryInProject(owner, projectid, queryid, versionid string) (
+	response QuerySummaryResponse, err error) {
+	body, err := s.rollback(queryid, versionid)
+	if err != nil {
+		return
+	}
+	return s.UpdateSavedQueryInProject(owner, projectid, queryid, body)
+}
+
+// saveResults saves query results to a file, converting them from one format to another if the two
+// differ, and closes them.
+func (s *QueryService) saveResults(r io.ReadCloser, path string, from, to Format) (
+	response SuccessResponse, err error) {
+	defer r.Close()
+	if from != to {
+		if r, err = ConvertResults(r, from, to); err != nil {
+			return
+		}
+		defer r.Close()
+	}
+	if err = s.client.saveToFile(path, r); err != nil {
+		return
+	}
+	return SuccessResponse{
+		Message: fmt.Sprintf("Results saved to %s", path),
+	}, nil
+}
+
+// savedQueryKind retrieves a saved query to learn its kind.
+func (s *QueryService) savedQueryKind(queryid string) (QueryKind, error) {
+	q, err := s.Retrieve(queryid)
+	if err != nil {
+		return 0, err
+	}
+	if strings.EqualFold(q.Language, "SPARQL") {
+		return SPARQLKind(q.Body)
+	}
+	return SQLQuery, nil
+}
+
+// rollback returns the update restoring a version of a saved query, with the query's current
+// published state, which an update without it would clear.
+func (s *QueryService) rollback(queryid, versionid string) (*QueryUpdateRequest, error) {
+	current, err := s.Retrieve(queryid)
+	if err != nil {
+		return nil, err
+	}
+	v, err := s.RetrieveVersion(queryid, versionid)
+	if err != nil {
+		return nil, err
+	}
+	return &QueryUpdateRequest{Name: v.Name, Content: v.Body, Published: current.Published}, nil
+}
+
 // UpdateSavedQueryInDataset updates a saved query in the specified dataset.
 func (s *QueryService) UpdateSavedQueryInDataset(owner, datasetid, queryid string, body *QueryUpdateRequest) (
 	response QuerySummaryResponse, err error) {
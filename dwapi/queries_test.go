@@ -18,13 +18,18 @@
 package dwapi
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -145,6 +150,51 @@ func TestQueryService_DeleteSavedQueryInProject(t *testing.T) {
 	}
 }
 
+// handleQueryVersions serves two versions of a saved query, whose current version is published.
+func handleQueryVersions(queryid string) {
+	mux.HandleFunc("/queries/"+queryid, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"body": "SELECT name, age\nFROM people\nWHERE age > 21", "id": %q, "language": "SQL",
+			"name": "People", "published": true, "version": "v2"}`, queryid)
+	})
+	bodies := map[string]string{
+		"v1": "SELECT name\nFROM people\nWHERE age > 21",
+		"v2": "SELECT name, age\nFROM people\nWHERE age > 21",
+	}
+	for version, body := range bodies {
+		version, body := version, body
+		mux.HandleFunc(fmt.Sprintf("/queries/%s/v/%s", queryid, version), func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `{"body": %q, "id": %q, "language": "SQL", "name": "People", "version": %q}`,
+				body, queryid, version)
+		})
+	}
+}
+
+func TestQueryService_DiffVersions(t *testing.T) {
+	setup()
+	defer teardown()
+
+	queryid := "query.id"
+	handleQueryVersions(queryid)
+	got, err := dw.Query.DiffVersions(queryid, "v1", "v2")
+	if assert.NoError(t, err) {
+		assert.Equal(t, `--- query.id@v1
++++ query.id@v2
+@@ -1,3 +1,3 @@
+-SELECT name
++SELECT name, age
+ FROM people
+ WHERE age > 21
+`, got)
+	}
+
+	got, err = dw.Query.DiffVersions(queryid, "v1", "v1")
+	assert.NoError(t, err)
+	assert.Empty(t, got)
+
+	_, err = dw.Query.DiffVersions(queryid, "v1", "v3")
+	assert.Error(t, err)
+}
+
 func ExampleQueryService_ExecuteSQLAndSave() {
 	owner := "dataset-owner"
 	id := "my-awesome-dataset"
@@ -161,6 +211,69 @@ func ExampleQueryService_ExecuteSQLAndSave() {
 	}
 }
 
+func TestQueryService_ExecuteBatch(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var mu sync.Mutex
+	inFlight, most := 0, 0
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		if inFlight > most {
+			most = inFlight
+		}
+		mu.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+
+		assert.Equal(t, "text/csv", r.Header.Get("Accept"))
+		if strings.Contains(r.URL.Path, "missing") {
+			http.Error(w, `{"message": "not found"}`, http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, r.URL.Path)
+	}
+	mux.HandleFunc("/sql/", handler)
+	mux.HandleFunc("/sparql/", handler)
+
+	queries := map[string]BatchQuery{
+		"broken":  {Owner: testClientOwner, ID: "missing", AcceptType: "text/csv", SQL: &SQLQueryRequest{Query: "SELECT 1"}},
+		"invalid": {Owner: testClientOwner, ID: "sales"},
+	}
+	for _, id := range []string{"sales", "visits", "people"} {
+		queries["sql-"+id] = BatchQuery{Owner: testClientOwner, ID: id, AcceptType: "text/csv",
+			SQL: &SQLQueryRequest{Query: "SELECT * FROM t"}}
+		queries["sparql-"+id] = BatchQuery{Owner: testClientOwner, ID: id, AcceptType: "text/csv",
+			SPARQL: &SPARQLQueryRequest{Query: "SELECT * WHERE { ?s ?p ?o }"}}
+	}
+	got := dw.Query.ExecuteBatch(queries, &BatchOptions{Concurrency: 2})
+
+	assert.Len(t, got, len(queries))
+	assert.Equal(t, 2, most)
+	for _, id := range []string{"sales", "visits", "people"} {
+		if assert.NoError(t, got["sql-"+id].Err) {
+			assert.Equal(t, "/sql/"+testClientOwner+"/"+id, string(got["sql-"+id].Body))
+			assert.True(t, got["sql-"+id].Duration >= 20*time.Millisecond)
+		}
+		if assert.NoError(t, got["sparql-"+id].Err) {
+			assert.Equal(t, "/sparql/"+testClientOwner+"/"+id, string(got["sparql-"+id].Body))
+		}
+	}
+	assert.EqualError(t, got["broken"].Err, "404 Not Found")
+	assert.EqualError(t, got["invalid"].Err, "dwapi: a batch query needs exactly one of a SQL and a SPARQL request")
+}
+
+// handleSavedQuery serves a saved SQL query, which the methods executing saved queries retrieve to
+// check the accept type.
+func handleSavedQuery(queryid string) {
+	mux.HandleFunc("/queries/"+queryid, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"id": %q, "language": "SQL", "body": "SELECT * FROM Tables"}`, queryid)
+	})
+}
+
 func TestQueryService_ExecuteSavedQuery(t *testing.T) {
 	setup()
 	defer teardown()
@@ -178,12 +291,16 @@ func TestQueryService_ExecuteSavedQuery(t *testing.T) {
 	}
 	endpoint := fmt.Sprintf("/queries/%s/results", queryid)
 	mux.HandleFunc(endpoint, handler)
+	handleSavedQuery(queryid)
 	r, err := dw.Query.ExecuteSavedQuery(queryid, acceptType, &body)
 	if assert.NoError(t, err) {
 		got, _ := ioutil.ReadAll(r)
 		assert.Equal(t, want, string(got))
+		r.Close()
 	}
-	r.Close()
+
+	_, err = dw.Query.ExecuteSavedQuery(queryid, string(FormatTurtle), &body)
+	assert.EqualError(t, err, "dwapi: SQL results can't be returned as text/turtle")
 }
 
 func TestQueryService_ExecuteSavedQueryAndSave(t *testing.T) {
@@ -193,7 +310,7 @@ func TestQueryService_ExecuteSavedQueryAndSave(t *testing.T) {
 	filename := "test-file"
 	path := filepath.Join(os.TempDir(), filename)
 	want := SuccessResponse{
-		fmt.Sprintf("Results saved to %s", path),
+		Message: fmt.Sprintf("Results saved to %s", path),
 	}
 
 	queryid := "my-saved-query"
@@ -207,6 +324,7 @@ func TestQueryService_ExecuteSavedQueryAndSave(t *testing.T) {
 	}
 	endpoint := fmt.Sprintf("/queries/%s/results", queryid)
 	mux.HandleFunc(endpoint, handler)
+	handleSavedQuery(queryid)
 	got, err := dw.Query.ExecuteSavedQueryAndSave(queryid, acceptType, path, &body)
 	if assert.NoError(t, err) {
 		assert.Equal(t, want, got)
@@ -221,6 +339,133 @@ func TestQueryService_ExecuteSavedQueryAndSave(t *testing.T) {
 	_ = os.Remove(path)
 }
 
+func TestQueryService_ExecuteSavedQueryInto(t *testing.T) {
+	setup()
+	defer teardown()
+
+	type row struct {
+		Name string
+	}
+	want := []row{{Name: "Tables"}}
+
+	queryid := "my-saved-query"
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Method, POST, "Expected method 'POST', got %s", r.Method)
+		assert.Equal(t, "application/sparql-results+json", r.Header.Get("Accept"))
+		fmt.Fprintf(w, `{
+			"head": {"vars": ["name"]},
+			"results": {"bindings": [{"name": {"type": "literal", "value": "Tables"}}]}
+		}`)
+	}
+	endpoint := fmt.Sprintf("/queries/%s/results", queryid)
+	mux.HandleFunc(endpoint, handler)
+	handleSavedQuery(queryid)
+	var got []row
+	err := dw.Query.ExecuteSavedQueryInto(queryid, nil, &got)
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, got)
+	}
+}
+
+func TestQueryService_ExecuteSavedQueryRows(t *testing.T) {
+	setup()
+	defer teardown()
+
+	queryid := "my-saved-query"
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Method, POST, "Expected method 'POST', got %s", r.Method)
+		assert.Equal(t, "text/csv", r.Header.Get("Accept"))
+		fmt.Fprintf(w, "name\nTables\n")
+	}
+	endpoint := fmt.Sprintf("/queries/%s/results", queryid)
+	mux.HandleFunc(endpoint, handler)
+	handleSavedQuery(queryid)
+	rows, err := dw.Query.ExecuteSavedQueryRows(queryid, "text/csv", nil)
+	if assert.NoError(t, err) {
+		defer rows.Close()
+		var name string
+		assert.True(t, rows.Next())
+		assert.NoError(t, rows.Scan(&name))
+		assert.Equal(t, "Tables", name)
+		assert.False(t, rows.Next())
+	}
+}
+
+func TestQueryService_ExecuteSavedQueryToFile(t *testing.T) {
+	setup()
+	defer teardown()
+
+	queries := map[string]string{
+		"sql-query":       `{"id": "sql-query", "language": "SQL", "body": "SELECT * FROM Tables"}`,
+		"construct-query": `{"id": "construct-query", "language": "SPARQL", "body": "CONSTRUCT { ?s ?p ?o } WHERE { ?s ?p ?o }"}`,
+	}
+	for id, query := range queries {
+		query := query
+		mux.HandleFunc("/queries/"+id, func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, query)
+		})
+		mux.HandleFunc("/queries/"+id+"/results", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, r.Header.Get("Accept"))
+		})
+	}
+
+	dir, err := ioutil.TempDir("", "dwapi")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "tables.tsv")
+	_, err = dw.Query.ExecuteSavedQueryToFile("sql-query", path, &SavedQueryExecutionRequest{})
+	if assert.NoError(t, err) {
+		c, _ := ioutil.ReadFile(path)
+		assert.Equal(t, "text/tab-separated-values", string(c))
+	}
+	path = filepath.Join(dir, "graph.ttl")
+	_, err = dw.Query.ExecuteSavedQueryToFile("construct-query", path, &SavedQueryExecutionRequest{})
+	if assert.NoError(t, err) {
+		c, _ := ioutil.ReadFile(path)
+		assert.Equal(t, "text/turtle", string(c))
+	}
+
+	_, err = dw.Query.ExecuteSavedQueryToFile("sql-query", path, &SavedQueryExecutionRequest{})
+	assert.EqualError(t, err, "dwapi: SQL results can't be returned as text/turtle")
+	_, err = dw.Query.ExecuteSavedQueryToFile("construct-query", filepath.Join(dir, "graph.md"), nil)
+	assert.EqualError(t, err, "dwapi: SPARQL CONSTRUCT results can't be returned as text/markdown")
+}
+
+func TestQueryService_ExecuteSavedQueryVersion(t *testing.T) {
+	setup()
+	defer teardown()
+
+	owner := testClientOwner
+	id := "my-awesome-dataset"
+	queryid := "query.id"
+	handleQueryVersions(queryid)
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Method, POST, "Expected method 'POST', got %s", r.Method)
+		assert.Equal(t, "text/csv", r.Header.Get("Accept"))
+		var body SQLQueryRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, SQLQueryRequest{Query: "SELECT name\nFROM people\nWHERE age > 21",
+			Parameters: map[string]string{"$data_world_param0": `"21"`}}, body)
+		fmt.Fprintf(w, "name\nAbe\n")
+	}
+	mux.HandleFunc(fmt.Sprintf("/sql/%s/%s", owner, id), handler)
+	r, err := dw.Query.ExecuteSavedQueryVersion(owner, id, queryid, "v1", "text/csv",
+		&SavedQueryExecutionRequest{Parameters: map[string]string{"$data_world_param0": `"21"`}})
+	if assert.NoError(t, err) {
+		got, _ := ioutil.ReadAll(r)
+		assert.Equal(t, "name\nAbe\n", string(got))
+		r.Close()
+	}
+
+	_, err = dw.Query.ExecuteSavedQueryVersion(owner, id, queryid, "v1", "text/csv", &SavedQueryExecutionRequest{MaxRows: 10})
+	assert.EqualError(t, err, "dwapi: MaxRows can't be applied to a version of a saved query")
+	_, err = dw.Query.ExecuteSavedQueryVersion(owner, id, queryid, "v3", "text/csv", nil)
+	assert.Error(t, err)
+}
+
 func TestQueryService_ExecuteSPARQL(t *testing.T) {
 	setup()
 	defer teardown()
@@ -243,8 +488,14 @@ func TestQueryService_ExecuteSPARQL(t *testing.T) {
 	if assert.NoError(t, err) {
 		got, _ := ioutil.ReadAll(r)
 		assert.Equal(t, want, string(got))
+		r.Close()
 	}
-	r.Close()
+
+	_, err = dw.Query.ExecuteSPARQL(owner, id, acceptType, &SPARQLQueryRequest{Query: "SELECT*{?s ?p ?o}"})
+	assert.EqualError(t, err, "dwapi: SPARQL SELECT results can't be returned as text/turtle")
+	_, err = dw.Query.ExecuteSPARQLAndSave(owner, id, "text/csv", filepath.Join(os.TempDir(), "graph.csv"),
+		&SPARQLQueryRequest{Query: "CONSTRUCT WHERE { ?s ?p ?o }"})
+	assert.EqualError(t, err, "dwapi: SPARQL CONSTRUCT results can't be returned as text/csv")
 }
 
 func TestQueryService_ExecuteSPARQLAndSaveService(t *testing.T) {
@@ -254,7 +505,7 @@ func TestQueryService_ExecuteSPARQLAndSaveService(t *testing.T) {
 	filename := "test-file"
 	path := filepath.Join(os.TempDir(), filename)
 	want := SuccessResponse{
-		fmt.Sprintf("Results saved to %s", path),
+		Message: fmt.Sprintf("Results saved to %s", path),
 	}
 
 	owner := testClientOwner
@@ -283,6 +534,94 @@ func TestQueryService_ExecuteSPARQLAndSaveService(t *testing.T) {
 	_ = os.Remove(path)
 }
 
+func TestQueryService_ExecuteSPARQLInto(t *testing.T) {
+	setup()
+	defer teardown()
+
+	type row struct {
+		Subject string `dw:"s"`
+		Count   int64  `dw:"n"`
+	}
+	want := []row{{Subject: "http://example.com/a", Count: 3}}
+
+	owner := testClientOwner
+	id := "my-awesome-dataset"
+	body := SPARQLQueryRequest{
+		Query: "SELECT ?s (COUNT(*) AS ?n) WHERE { ?s ?p ?o } GROUP BY ?s",
+	}
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Method, POST, "Expected method 'POST', got %s", r.Method)
+		assert.Equal(t, "application/sparql-results+json", r.Header.Get("Accept"))
+		fmt.Fprintf(w, `{
+			"head": {"vars": ["s", "n"]},
+			"results": {"bindings": [{
+				"s": {"type": "uri", "value": "http://example.com/a"},
+				"n": {"type": "literal", "value": "3", "datatype": "http://www.w3.org/2001/XMLSchema#integer"}
+			}]}
+		}`)
+	}
+	endpoint := fmt.Sprintf("/sparql/%s/%s", owner, id)
+	mux.HandleFunc(endpoint, handler)
+	var got []row
+	err := dw.Query.ExecuteSPARQLInto(owner, id, &body, &got)
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, got)
+	}
+}
+
+func TestQueryService_ExecuteSPARQLRows(t *testing.T) {
+	setup()
+	defer teardown()
+
+	owner := testClientOwner
+	id := "my-awesome-dataset"
+	body := SPARQLQueryRequest{
+		Query: "ASK { ?s ?p ?o }",
+	}
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Method, POST, "Expected method 'POST', got %s", r.Method)
+		assert.Equal(t, "application/sparql-results+json", r.Header.Get("Accept"))
+		fmt.Fprintf(w, `{"head": {}, "boolean": true}`)
+	}
+	endpoint := fmt.Sprintf("/sparql/%s/%s", owner, id)
+	mux.HandleFunc(endpoint, handler)
+	rows, err := dw.Query.ExecuteSPARQLRows(owner, id, "", &body)
+	if assert.NoError(t, err) {
+		defer rows.Close()
+		var got bool
+		assert.True(t, rows.Next())
+		assert.NoError(t, rows.Scan(&got))
+		assert.True(t, got)
+	}
+}
+
+func TestQueryService_ExecuteSPARQLToFile(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/sparql/"+testClientOwner+"/my-awesome-dataset", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, r.Header.Get("Accept"))
+	})
+	dir, err := ioutil.TempDir("", "dwapi")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	selectQuery := &SPARQLQueryRequest{Query: "PREFIX ex: <http://example.com/#construct>\nSELECT * WHERE { ?s ?p ?o }"}
+	path := filepath.Join(dir, "results.srx")
+	_, err = dw.Query.ExecuteSPARQLToFile(testClientOwner, "my-awesome-dataset", path, selectQuery)
+	if assert.NoError(t, err) {
+		c, _ := ioutil.ReadFile(path)
+		assert.Equal(t, "application/sparql-results+xml", string(c))
+	}
+
+	_, err = dw.Query.ExecuteSPARQLToFile(testClientOwner, "my-awesome-dataset", filepath.Join(dir, "graph.nt"), selectQuery)
+	assert.EqualError(t, err, "dwapi: SPARQL SELECT results can't be returned as application/n-triples")
+	_, err = dw.Query.ExecuteSPARQLToFile(testClientOwner, "my-awesome-dataset", filepath.Join(dir, "results.pdf"), selectQuery)
+	assert.EqualError(t, err, `dwapi: no format for the extension of "`+filepath.Join(dir, "results.pdf")+`"`)
+}
+
 func TestQueryService_ExecuteSQL(t *testing.T) {
 	setup()
 	defer teardown()
@@ -306,8 +645,63 @@ func TestQueryService_ExecuteSQL(t *testing.T) {
 	if assert.NoError(t, err) {
 		got, _ := ioutil.ReadAll(r)
 		assert.Equal(t, want, string(got))
+		r.Close()
 	}
-	r.Close()
+
+	_, err = dw.Query.ExecuteSQL(owner, id, string(FormatJSONLD), &body)
+	assert.EqualError(t, err, "dwapi: SQL results can't be returned as application/ld+json")
+}
+
+func TestQueryService_ExecuteSQLAcross(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/sql/", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/sql/acme/sales-emea":
+			fmt.Fprint(w, "region,total\nEMEA,10\nEMEA,20\n")
+		case "/sql/acme/sales-apac":
+			fmt.Fprint(w, "region,total\nAPAC,5\n")
+		case "/sql/acme/sales-old":
+			fmt.Fprint(w, "region,amount\nAMER,1\n")
+		default:
+			http.Error(w, `{"message": "not found"}`, http.StatusNotFound)
+		}
+	})
+
+	datasets := []DatasetOrProjectIdentifier{
+		{Owner: "acme", ID: "sales-emea"},
+		{Owner: "acme", ID: "sales-apac"},
+		{Owner: "acme", ID: "sales-old"},
+		{Owner: "acme", ID: "sales-missing"},
+	}
+	rows, err := dw.Query.ExecuteSQLAcross(datasets, "text/csv", &SQLQueryRequest{Query: "SELECT * FROM sales"},
+		&BatchOptions{Concurrency: 1})
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer rows.Close()
+	assert.Equal(t, []string{SourceColumn, "region", "total"}, rows.Columns())
+
+	got := map[string]int{}
+	for rows.Next() {
+		var source, region string
+		var total int
+		if assert.NoError(t, rows.Scan(&source, &region, &total)) {
+			got[source+" "+region] += total
+		}
+	}
+	assert.NoError(t, rows.Err())
+	assert.Equal(t, map[string]int{"acme/sales-emea EMEA": 30, "acme/sales-apac APAC": 5}, got)
+
+	failures := rows.Failures()
+	if assert.Len(t, failures, 2) {
+		assert.EqualError(t, failures[0], "acme/sales-old: dwapi: columns [region amount] don't match [region total]")
+		assert.EqualError(t, failures[1], "acme/sales-missing: 404 Not Found")
+	}
+
+	_, err = dw.Query.ExecuteSQLAcross(nil, "text/csv", &SQLQueryRequest{Query: "SELECT 1"}, nil)
+	assert.EqualError(t, err, "dwapi: no datasets to run the query against")
 }
 
 func TestQueryService_ExecuteSQLAndSave(t *testing.T) {
@@ -317,7 +711,7 @@ func TestQueryService_ExecuteSQLAndSave(t *testing.T) {
 	filename := "test-file"
 	path := filepath.Join(os.TempDir(), filename)
 	want := SuccessResponse{
-		fmt.Sprintf("Results saved to %s", path),
+		Message: fmt.Sprintf("Results saved to %s", path),
 	}
 
 	owner := testClientOwner
@@ -347,6 +741,138 @@ func TestQueryService_ExecuteSQLAndSave(t *testing.T) {
 	_ = os.Remove(path)
 }
 
+func TestQueryService_ExecuteSQLChunked(t *testing.T) {
+	setup()
+	defer teardown()
+	var requests int32
+	handleChunks(t, 25, &requests)
+
+	rows, err := dw.Query.ExecuteSQLChunked("acme", "big", &SQLQueryRequest{Query: "SELECT id, name FROM big"},
+		&ChunkOptions{ChunkSize: 10, OrderBy: []string{"id"}})
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"id", "name"}, rows.Columns())
+		assert.Equal(t, span(1, 25), readIDs(t, rows, 100))
+		assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+	}
+
+	_, err = dw.Query.ExecuteSQLChunked("acme", "big", &SQLQueryRequest{Query: "SELECT id, name FROM big"}, nil)
+	assert.EqualError(t, err, "dwapi: chunked queries need OrderBy columns or a KeyColumn for a stable order")
+	_, err = dw.Query.ExecuteSQLChunked("acme", "big", &SQLQueryRequest{Query: "SELECT id, name FROM big"},
+		&ChunkOptions{KeyColumn: "key"})
+	assert.EqualError(t, err, `dwapi: key column "key" is not a column of the results`)
+}
+
+func TestQueryService_ExecuteSQLInto(t *testing.T) {
+	setup()
+	defer teardown()
+
+	type row struct {
+		ID      int64
+		Score   *float64
+		Created time.Time `dw:"created_at"`
+	}
+	score := 1.5
+	want := []row{
+		{ID: 1, Score: &score, Created: time.Date(2018, 8, 3, 15, 56, 41, 0, time.UTC)},
+		{ID: 2},
+	}
+
+	owner := testClientOwner
+	id := "my-awesome-dataset"
+	body := SQLQueryRequest{
+		Query: "SELECT id, score, created_at FROM Tables",
+	}
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Method, POST, "Expected method 'POST', got %s", r.Method)
+		assert.Equal(t, "application/sparql-results+json", r.Header.Get("Accept"))
+		fmt.Fprintf(w, `{
+			"head": {"vars": ["id", "score", "created_at"]},
+			"results": {"bindings": [{
+				"id": {"type": "literal", "value": "1", "datatype": "http://www.w3.org/2001/XMLSchema#integer"},
+				"score": {"type": "literal", "value": "1.5", "datatype": "http://www.w3.org/2001/XMLSchema#decimal"},
+				"created_at": {"type": "literal", "value": "2018-08-03T15:56:41Z",
+					"datatype": "http://www.w3.org/2001/XMLSchema#dateTime"}
+			}, {
+				"id": {"type": "literal", "value": "2", "datatype": "http://www.w3.org/2001/XMLSchema#integer"}
+			}]}
+		}`)
+	}
+	endpoint := fmt.Sprintf("/sql/%s/%s", owner, id)
+	mux.HandleFunc(endpoint, handler)
+	var got []row
+	err := dw.Query.ExecuteSQLInto(owner, id, &body, &got)
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, got)
+	}
+}
+
+func TestQueryService_ExecuteSQLRows(t *testing.T) {
+	setup()
+	defer teardown()
+
+	owner := testClientOwner
+	id := "my-awesome-dataset"
+	body := SQLQueryRequest{
+		Query: "SELECT id FROM Tables",
+	}
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Method, POST, "Expected method 'POST', got %s", r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Accept"))
+		fmt.Fprintf(w, `[{"id": 1}, {"id": 2}]`)
+	}
+	endpoint := fmt.Sprintf("/sql/%s/%s", owner, id)
+	mux.HandleFunc(endpoint, handler)
+	rows, err := dw.Query.ExecuteSQLRows(owner, id, "application/json", &body)
+	if assert.NoError(t, err) {
+		defer rows.Close()
+		var got []int64
+		for rows.Next() {
+			var n int64
+			assert.NoError(t, rows.Scan(&n))
+			got = append(got, n)
+		}
+		assert.NoError(t, rows.Err())
+		assert.Equal(t, []int64{1, 2}, got)
+	}
+}
+
+func TestQueryService_ExecuteSQLToFile(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/sql/"+testClientOwner+"/my-awesome-dataset", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") == string(FormatSPARQLJSON) {
+			fmt.Fprint(w, `{"head": {"vars": ["name"]}, "results": {"bindings": [{"name": {"type": "literal", "value": "Tables"}}]}}`)
+			return
+		}
+		fmt.Fprint(w, r.Header.Get("Accept"))
+	})
+	dir, err := ioutil.TempDir("", "dwapi")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	body := &SQLQueryRequest{Query: "SELECT * FROM Tables"}
+	path := filepath.Join(dir, "Tables.CSV")
+	got, err := dw.Query.ExecuteSQLToFile(testClientOwner, "my-awesome-dataset", path, body)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "Results saved to "+path, got.Message)
+		c, _ := ioutil.ReadFile(path)
+		assert.Equal(t, "text/csv", string(c))
+	}
+
+	// Markdown and HTML tables are converted from SPARQL JSON results.
+	path = filepath.Join(dir, "tables.md")
+	if _, err = dw.Query.ExecuteSQLToFile(testClientOwner, "my-awesome-dataset", path, body); assert.NoError(t, err) {
+		c, _ := ioutil.ReadFile(path)
+		assert.Equal(t, "| name |\n| --- |\n| Tables |\n", string(c))
+	}
+
+	_, err = dw.Query.ExecuteSQLToFile(testClientOwner, "my-awesome-dataset", filepath.Join(dir, "graph.ttl"), body)
+	assert.EqualError(t, err, "dwapi: SQL results can't be returned as text/turtle")
+}
+
 func TestQueryService_ListQueriesAssociatedWithDataset(t *testing.T) {
 	setup()
 	defer teardown()
@@ -411,6 +937,14 @@ func TestQueryService_ListQueriesAssociatedWithProject(t *testing.T) {
 	}
 }
 
+func TestQueryService_ListVersions(t *testing.T) {
+	setup()
+	defer teardown()
+
+	_, err := dw.Query.ListVersions("query.id")
+	assert.Equal(t, ErrVersionsNotListed, err)
+}
+
 func TestQueryService_Retrieve(t *testing.T) {
 	setup()
 	defer teardown()
@@ -470,6 +1004,56 @@ func TestQueryService_RetrieveVersion(t *testing.T) {
 	}
 }
 
+func TestQueryService_RollbackSavedQueryInDataset(t *testing.T) {
+	setup()
+	defer teardown()
+
+	owner := testClientOwner
+	id := "my-awesome-dataset"
+	queryid := "query.id"
+	handleQueryVersions(queryid)
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Method, PUT, "Expected method 'PUT', got %s", r.Method)
+		var body QueryUpdateRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, QueryUpdateRequest{Name: "People", Content: "SELECT name\nFROM people\nWHERE age > 21",
+			Published: true}, body)
+		fmt.Fprintf(w, `{"body": %q, "id": %q, "name": %q, "version": "v3"}`, body.Content, queryid, body.Name)
+	}
+	endpoint := fmt.Sprintf("/datasets/%s/%s/queries/%s", owner, id, queryid)
+	mux.HandleFunc(endpoint, handler)
+	got, err := dw.Query.RollbackSavedQueryInDataset(owner, id, queryid, "v1")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "v3", got.Version)
+		assert.Equal(t, "SELECT name\nFROM people\nWHERE age > 21", got.Body)
+	}
+}
+
+func TestQueryService_RollbackSavedQueryInProject(t *testing.T) {
+	setup()
+	defer teardown()
+
+	owner := testClientOwner
+	id := "my-awesome-project"
+	queryid := "query.id"
+	handleQueryVersions(queryid)
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Method, PUT, "Expected method 'PUT', got %s", r.Method)
+		var body QueryUpdateRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, QueryUpdateRequest{Name: "People", Content: "SELECT name\nFROM people\nWHERE age > 21",
+			Published: true}, body)
+		fmt.Fprintf(w, `{"body": %q, "id": %q, "name": %q, "version": "v3"}`, body.Content, queryid, body.Name)
+	}
+	endpoint := fmt.Sprintf("/projects/%s/%s/queries/%s", owner, id, queryid)
+	mux.HandleFunc(endpoint, handler)
+	got, err := dw.Query.RollbackSavedQueryInProject(owner, id, queryid, "v1")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "v3", got.Version)
+		assert.Equal(t, "SELECT name\nFROM people\nWHERE age > 21", got.Body)
+	}
+}
+
 func TestQueryService_UpdateSavedQueryInDataset(t *testing.T) {
 	setup()
 	defer teardown()
@@ -0,0 +1,462 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package dwapi
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"strconv"
+	"strings"
+)
+
+const xsd = "http://www.w3.org/2001/XMLSchema#"
+
+// ResultTerm is a value in query results: an RDF term in SPARQL JSON results, with the type "uri",
+// "bnode" or "literal", or a value of a CSV or JSON result, which has a datatype only if JSON gives it
+// one.
+type ResultTerm struct {
+	Type     string `json:"type"`
+	Value    string `json:"value"`
+	Datatype string `json:"datatype,omitempty"`
+	Lang     string `json:"xml:lang,omitempty"`
+}
+
+// resultReader reads query results a row at a time. Unbound values are nil.
+type resultReader interface {
+	columns() []string
+	next() ([]*ResultTerm, error)
+}
+
+// datatyped is implemented by result readers that know the datatypes of the columns before reading
+// the rows.
+type datatyped interface {
+	columnDatatypes() []string
+}
+
+// newResultReader returns a reader for results of the given content type: SPARQL JSON results, CSV,
+// a JSON array of objects or JSON lines.
+func newResultReader(r io.Reader, contentType string) (resultReader, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = contentType
+	}
+	switch Format(mediaType) {
+	case FormatSPARQLJSON:
+		return NewSPARQLJSONReader(r)
+	case FormatCSV:
+		return newCSVReader(r)
+	case FormatTSV:
+		return newTSVReader(r)
+	case FormatJSON, FormatJSONLines, FormatNDJSON:
+		return newJSONReader(r)
+	}
+	return nil, fmt.Errorf("dwapi: can't decode results of type %q", contentType)
+}
+
+// SPARQLJSONReader reads results in the SPARQL 1.1 Query Results JSON format a solution at a time.
+type SPARQLJSONReader struct {
+	dec      *json.Decoder
+	vars     []string
+	links    []string
+	boolean  *bool
+	bindings bool
+	head     bool
+	// pending is set while the boolean of an ASK query hasn't been read as a row.
+	pending bool
+	done    bool
+}
+
+// NewSPARQLJSONReader reads the head of the results, and the boolean of the results of an ASK query.
+func NewSPARQLJSONReader(r io.Reader) (*SPARQLJSONReader, error) {
+	s := &SPARQLJSONReader{dec: json.NewDecoder(r)}
+	err := s.readHead()
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *SPARQLJSONReader) readHead() error {
+	if err := expectDelim(s.dec, '{'); err != nil {
+		return err
+	}
+	for s.dec.More() {
+		key, err := s.dec.Token()
+		if err != nil {
+			return err
+		}
+		switch key {
+		case "head":
+			var head struct {
+				Vars []string `json:"vars"`
+				Link []string `json:"link"`
+			}
+			if err = s.dec.Decode(&head); err != nil {
+				return err
+			}
+			s.vars, s.links, s.head = head.Vars, head.Link, true
+		case "boolean":
+			s.boolean = new(bool)
+			if err = s.dec.Decode(s.boolean); err != nil {
+				return err
+			}
+			s.pending = true
+		case "results":
+			if err = expectDelim(s.dec, '{'); err != nil {
+				return err
+			}
+			for s.dec.More() {
+				if key, err = s.dec.Token(); err != nil {
+					return err
+				}
+				if key != "bindings" {
+					if err = skipValue(s.dec); err != nil {
+						return err
+					}
+					continue
+				}
+				if !s.head {
+					return fmt.Errorf("dwapi: SPARQL results have bindings before the head")
+				}
+				// The bindings are read a solution at a time by Next.
+				s.bindings = true
+				return expectDelim(s.dec, '[')
+			}
+			if err = expectDelim(s.dec, '}'); err != nil {
+				return err
+			}
+		default:
+			if err = skipValue(s.dec); err != nil {
+				return err
+			}
+		}
+	}
+	s.done = true
+	return expectDelim(s.dec, '}')
+}
+
+// Vars returns the variables of the results of a SELECT query.
+func (s *SPARQLJSONReader) Vars() []string {
+	return s.vars
+}
+
+// Links returns the links of the head of the results.
+func (s *SPARQLJSONReader) Links() []string {
+	return s.links
+}
+
+// Boolean returns the result of an ASK query, or nil for other results.
+func (s *SPARQLJSONReader) Boolean() *bool {
+	return s.boolean
+}
+
+// HasBindings reports whether the results have bindings, as those of a SELECT query do.
+func (s *SPARQLJSONReader) HasBindings() bool {
+	return s.bindings
+}
+
+// Next returns the values of the variables in the next solution, in the order of Vars, with nil for
+// unbound variables, and an error for a binding of a variable that isn't in the head. It returns io.EOF
+// after the last solution.
+func (s *SPARQLJSONReader) Next() ([]*ResultTerm, error) {
+	if s.done || !s.bindings || !s.dec.More() {
+		s.done = true
+		return nil, io.EOF
+	}
+	var binding map[string]*ResultTerm
+	if err := s.dec.Decode(&binding); err != nil {
+		return nil, err
+	}
+	row := make([]*ResultTerm, len(s.vars))
+	for i, v := range s.vars {
+		row[i] = binding[v]
+		delete(binding, v)
+	}
+	for v := range binding {
+		return nil, fmt.Errorf("dwapi: SPARQL results bind %q, which isn't one of the variables %q", v, s.vars)
+	}
+	return row, nil
+}
+
+// columns are the variables, or a single boolean column for the results of an ASK query.
+func (s *SPARQLJSONReader) columns() []string {
+	if s.boolean != nil {
+		return []string{"boolean"}
+	}
+	return s.vars
+}
+
+func (s *SPARQLJSONReader) next() ([]*ResultTerm, error) {
+	if s.pending {
+		s.pending = false
+		return []*ResultTerm{{Type: "literal", Value: strconv.FormatBool(*s.boolean), Datatype: xsd + "boolean"}}, nil
+	}
+	if s.boolean != nil {
+		return nil, io.EOF
+	}
+	return s.Next()
+}
+
+type csvReader struct {
+	r       *csv.Reader
+	headers []string
+}
+
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	c := &csvReader{r: csv.NewReader(r)}
+	c.r.FieldsPerRecord = -1
+	headers, err := c.r.Read()
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	c.headers = headers
+	return c, nil
+}
+
+func (c *csvReader) columns() []string {
+	return c.headers
+}
+
+func (c *csvReader) next() ([]*ResultTerm, error) {
+	record, err := c.r.Read()
+	if err != nil {
+		return nil, err
+	}
+	row := make([]*ResultTerm, len(c.headers))
+	for i := range row {
+		// Empty cells are nulls; CSV can't tell them apart from empty strings.
+		if i < len(record) && record[i] != "" {
+			row[i] = &ResultTerm{Type: "literal", Value: record[i]}
+		}
+	}
+	return row, nil
+}
+
+// tsvReader reads tab-separated values, with tabs, line breaks and backslashes in values escaped as
+// \t, \n, \r and \\.
+type tsvReader struct {
+	r       *bufio.Reader
+	headers []string
+}
+
+func newTSVReader(r io.Reader) (*tsvReader, error) {
+	t := &tsvReader{r: bufio.NewReader(r)}
+	headers, err := t.line()
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	t.headers = headers
+	return t, nil
+}
+
+func (t *tsvReader) columns() []string {
+	return t.headers
+}
+
+func (t *tsvReader) next() ([]*ResultTerm, error) {
+	fields, err := t.line()
+	if err != nil {
+		return nil, err
+	}
+	row := make([]*ResultTerm, len(t.headers))
+	for i := range row {
+		if i < len(fields) && fields[i] != "" {
+			row[i] = &ResultTerm{Type: "literal", Value: fields[i]}
+		}
+	}
+	return row, nil
+}
+
+func (t *tsvReader) line() ([]string, error) {
+	line, err := t.r.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return nil, err
+	}
+	line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+	fields := strings.Split(line, "\t")
+	for i, f := range fields {
+		fields[i] = tsvUnescaper.Replace(f)
+	}
+	return fields, nil
+}
+
+var tsvUnescaper = strings.NewReplacer(`\\`, `\`, `\t`, "\t", `\n`, "\n", `\r`, "\r")
+
+// jsonReader reads a JSON array of objects, or a stream of objects such as JSON lines. The columns are
+// the keys of the first object, unless the results start with a table schema. As the columns are
+// known before the rows are read, a later object with a key that isn't a column is an error rather
+// than being dropped.
+type jsonReader struct {
+	dec       *json.Decoder
+	keys      []string
+	datatypes []string
+	schema    *TableSchema
+	first     []*ResultTerm
+	// pending is set while the first row, read to learn the columns, hasn't been returned.
+	pending bool
+}
+
+func newJSONReader(r io.Reader) (*jsonReader, error) {
+	j := &jsonReader{dec: json.NewDecoder(r)}
+	first, err := j.dec.Token()
+	switch {
+	case err == io.EOF:
+		return j, nil
+	case err != nil:
+		return nil, err
+	case first == json.Delim('['):
+		if !j.dec.More() {
+			return j, nil
+		}
+		if err = expectDelim(j.dec, '{'); err != nil {
+			return nil, err
+		}
+	case first != json.Delim('{'):
+		return nil, fmt.Errorf("dwapi: JSON results must be objects, got %v", first)
+	}
+	keys, values, err := readObject(j.dec)
+	if err != nil {
+		return nil, err
+	}
+
+	// Results requested with includeTableSchema start with a {"fields": [...]} object, which names
+	// and types the columns.
+	if len(keys) == 1 && keys[0] == "fields" && values[0] != nil {
+		var schema TableSchema
+		if json.Unmarshal([]byte(values[0].Value), &schema.Fields) == nil {
+			j.schema = &schema
+			j.keys = schema.Names()
+			for i := range schema.Fields {
+				j.datatypes = append(j.datatypes, schema.Fields[i].Datatype())
+			}
+			return j, nil
+		}
+	}
+
+	j.keys, j.datatypes = keys, make([]string, len(keys))
+	j.first, _ = j.row(keys, values)
+	j.pending = true
+	return j, nil
+}
+
+func (j *jsonReader) columns() []string {
+	return j.keys
+}
+
+func (j *jsonReader) columnDatatypes() []string {
+	return j.datatypes
+}
+
+func (j *jsonReader) next() ([]*ResultTerm, error) {
+	if j.pending {
+		j.pending = false
+		return j.first, nil
+	}
+	if !j.dec.More() {
+		return nil, io.EOF
+	}
+	if err := expectDelim(j.dec, '{'); err != nil {
+		return nil, err
+	}
+	keys, values, err := readObject(j.dec)
+	if err != nil {
+		return nil, err
+	}
+	return j.row(keys, values)
+}
+
+// row puts the values of an object in the order of the columns, typing them as the schema says.
+func (j *jsonReader) row(keys []string, values []*ResultTerm) ([]*ResultTerm, error) {
+	row := make([]*ResultTerm, len(j.keys))
+next:
+	for i, k := range keys {
+		for c, column := range j.keys {
+			if column == k {
+				row[c] = values[i]
+				if values[i] != nil && j.datatypes[c] != "" {
+					values[i].Datatype = j.datatypes[c]
+				}
+				continue next
+			}
+		}
+		return nil, fmt.Errorf("dwapi: JSON results have a %q key that isn't one of the columns %q", k, j.keys)
+	}
+	return row, nil
+}
+
+// readObject reads the members of an object whose opening brace has been read, keeping their order.
+func readObject(dec *json.Decoder) (keys []string, values []*ResultTerm, err error) {
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		var raw json.RawMessage
+		if err = dec.Decode(&raw); err != nil {
+			return nil, nil, err
+		}
+		keys = append(keys, key.(string))
+		values = append(values, jsonTerm(raw))
+	}
+	return keys, values, expectDelim(dec, '}')
+}
+
+// jsonTerm gives JSON numbers and booleans their xsd datatype. Nested values are kept as JSON.
+func jsonTerm(raw json.RawMessage) *ResultTerm {
+	raw = bytes.TrimSpace(raw)
+	switch {
+	case len(raw) == 0 || string(raw) == "null":
+		return nil
+	case raw[0] == '"':
+		var s string
+		_ = json.Unmarshal(raw, &s)
+		return &ResultTerm{Type: "literal", Value: s}
+	case string(raw) == "true" || string(raw) == "false":
+		return &ResultTerm{Type: "literal", Value: string(raw), Datatype: xsd + "boolean"}
+	case raw[0] == '-' || raw[0] >= '0' && raw[0] <= '9':
+		if bytes.ContainsAny(raw, ".eE") {
+			return &ResultTerm{Type: "literal", Value: string(raw), Datatype: xsd + "double"}
+		}
+		return &ResultTerm{Type: "literal", Value: string(raw), Datatype: xsd + "integer"}
+	}
+	return &ResultTerm{Type: "literal", Value: string(raw)}
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	t, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if t != delim {
+		return fmt.Errorf("dwapi: expected %v in JSON results, got %v", delim, t)
+	}
+	return nil
+}
+
+func skipValue(dec *json.Decoder) error {
+	var raw json.RawMessage
+	return dec.Decode(&raw)
+}
@@ -0,0 +1,144 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package dwapi
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func readAll(t *testing.T, body, contentType string) ([]string, [][]*ResultTerm) {
+	rr, err := newResultReader(strings.NewReader(body), contentType)
+	if !assert.NoError(t, err) {
+		return nil, nil
+	}
+	var rows [][]*ResultTerm
+	for {
+		row, err := rr.next()
+		if err == io.EOF {
+			return rr.columns(), rows
+		}
+		if !assert.NoError(t, err) {
+			return nil, nil
+		}
+		rows = append(rows, row)
+	}
+}
+
+func literal(value, datatype string) *ResultTerm {
+	if datatype != "" {
+		datatype = xsd + datatype
+	}
+	return &ResultTerm{Type: "literal", Value: value, Datatype: datatype}
+}
+
+func TestResultReader_SPARQLJSON(t *testing.T) {
+	columns, rows := readAll(t, `{
+		"head": {"vars": ["s", "label"], "link": []},
+		"results": {"distinct": false, "bindings": [
+			{"s": {"type": "uri", "value": "http://example.com/a"}, "label": {"type": "literal", "value": "A", "xml:lang": "en"}},
+			{"s": {"type": "bnode", "value": "b0"}}
+		]}
+	}`, string(FormatSPARQLJSON))
+	assert.Equal(t, []string{"s", "label"}, columns)
+	assert.Equal(t, [][]*ResultTerm{
+		{{Type: "uri", Value: "http://example.com/a"}, {Type: "literal", Value: "A", Lang: "en"}},
+		{{Type: "bnode", Value: "b0"}, nil},
+	}, rows)
+
+	columns, rows = readAll(t, `{"head": {}, "boolean": true}`, string(FormatSPARQLJSON))
+	assert.Equal(t, []string{"boolean"}, columns)
+	assert.Equal(t, [][]*ResultTerm{{literal("true", "boolean")}}, rows)
+
+	columns, rows = readAll(t, `{"head": {"vars": ["s"]}, "results": {"bindings": []}}`, string(FormatSPARQLJSON))
+	assert.Equal(t, []string{"s"}, columns)
+	assert.Empty(t, rows)
+
+	_, err := newResultReader(strings.NewReader(`{"results": {"bindings": []}}`), string(FormatSPARQLJSON))
+	assert.EqualError(t, err, "dwapi: SPARQL results have bindings before the head")
+
+	rr, err := newResultReader(strings.NewReader(`{"head": {"vars": ["s"]}, "results": {"bindings": [{"o": {"type": "bnode", "value": "b0"}}]}}`), string(FormatSPARQLJSON))
+	if assert.NoError(t, err) {
+		_, err = rr.next()
+		assert.EqualError(t, err, `dwapi: SPARQL results bind "o", which isn't one of the variables ["s"]`)
+	}
+}
+
+func TestResultReader_JSON(t *testing.T) {
+	want := [][]*ResultTerm{
+		{literal("1", "integer"), literal("a", ""), literal("true", "boolean"), literal(`{"x":1}`, "")},
+		{literal("2.5", "double"), nil, nil, nil},
+	}
+	for _, body := range []string{
+		`[{"n": 1, "s": "a", "b": true, "o": {"x":1}}, {"b": null, "n": 2.5}]`,
+		"{\"n\": 1, \"s\": \"a\", \"b\": true, \"o\": {\"x\":1}}\n{\"n\": 2.5}\n",
+	} {
+		columns, rows := readAll(t, body, "application/json")
+		assert.Equal(t, []string{"n", "s", "b", "o"}, columns)
+		assert.Equal(t, want, rows)
+	}
+
+	columns, rows := readAll(t, `[]`, "application/json")
+	assert.Empty(t, columns)
+	assert.Empty(t, rows)
+
+	// A key missing from the first object isn't dropped from later ones.
+	rr, err := newResultReader(strings.NewReader(`[{"n": 1}, {"n": 2, "note": "x"}]`), "application/json")
+	if assert.NoError(t, err) {
+		_, err = rr.next()
+		assert.NoError(t, err)
+		_, err = rr.next()
+		assert.EqualError(t, err, `dwapi: JSON results have a "note" key that isn't one of the columns ["n"]`)
+	}
+}
+
+func TestResultReader_JSONWithSchema(t *testing.T) {
+	body := `[
+		{"fields": [{"name": "day", "type": "date"}, {"name": "n", "type": "number", "rdfType": "http://www.w3.org/2001/XMLSchema#double"}]},
+		{"day": "2018-08-03", "n": 1},
+		{"n": null}
+	]`
+	rr, err := newResultReader(strings.NewReader(body), "application/json")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []string{xsd + "date", xsd + "double"}, rr.(datatyped).columnDatatypes())
+
+	columns, rows := readAll(t, body, "application/json")
+	assert.Equal(t, []string{"day", "n"}, columns)
+	assert.Equal(t, [][]*ResultTerm{
+		{literal("2018-08-03", "date"), literal("1", "double")},
+		{nil, nil},
+	}, rows)
+}
+
+func TestResultReader_CSV(t *testing.T) {
+	columns, rows := readAll(t, "a,b\n1,\"x, y\"\n2\n", "text/csv")
+	assert.Equal(t, []string{"a", "b"}, columns)
+	assert.Equal(t, [][]*ResultTerm{
+		{literal("1", ""), literal("x, y", "")},
+		{literal("2", ""), nil},
+	}, rows)
+
+	columns, rows = readAll(t, "", "text/csv")
+	assert.Empty(t, columns)
+	assert.Empty(t, rows)
+}
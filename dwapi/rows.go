@@ -0,0 +1,234 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package dwapi
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// Rows is the result of a query, read from the response a row at a time so that results of any size
+// can be processed in constant memory. Its methods follow those of `database/sql.Rows`:
+//
+//	rows, err := dw.Query.ExecuteSQLRows(owner, id, "text/csv", &dwapi.SQLQueryRequest{Query: query})
+//	if err != nil {
+//		return err
+//	}
+//	defer rows.Close()
+//	for rows.Next() {
+//		var name string
+//		var total float64
+//		if err := rows.Scan(&name, &total); err != nil {
+//			return err
+//		}
+//	}
+//	return rows.Err()
+type Rows struct {
+	body   io.Closer
+	reader resultReader
+	types  []*ColumnType
+
+	row     []*ResultTerm
+	n       int
+	pending []*ResultTerm
+	err     error
+	closed  bool
+}
+
+// ColumnType describes a column of Rows.
+type ColumnType struct {
+	name     string
+	datatype string
+}
+
+// Name returns the name of the column.
+func (c *ColumnType) Name() string {
+	return c.name
+}
+
+// Datatype returns the IRI of the xsd datatype of the column's values, or an empty string if the
+// results don't say, e.g. for CSV results.
+func (c *ColumnType) Datatype() string {
+	return c.datatype
+}
+
+// DatabaseTypeName returns the datatype of the column in short form, e.g. "xsd:integer".
+func (c *ColumnType) DatabaseTypeName() string {
+	if c.datatype == "" {
+		return ""
+	}
+	return shortDatatype(c.datatype)
+}
+
+// ScanType returns the Go type that the column's values are scanned into by an interface{}
+// destination.
+func (c *ColumnType) ScanType() reflect.Type {
+	t := &ResultTerm{Type: "literal", Datatype: c.datatype}
+	switch t.category() {
+	case "int":
+		return reflect.TypeOf(int64(0))
+	case "float":
+		return reflect.TypeOf(float64(0))
+	case "bool":
+		return reflect.TypeOf(false)
+	case "time":
+		return timeType
+	}
+	return reflect.TypeOf("")
+}
+
+// ErrRowsClosed is returned by Scan after the rows are closed.
+var ErrRowsClosed = errors.New("dwapi: rows are closed")
+
+// NewRows reads the results of a query of the given content type from body, which it closes when
+// the rows are closed. See `DecodeResults` for the supported content types.
+func NewRows(body io.ReadCloser, contentType string) (*Rows, error) {
+	reader, err := newResultReader(body, contentType)
+	if err != nil {
+		body.Close()
+		return nil, err
+	}
+	return newRows(body, reader)
+}
+
+// newRows returns rows read from reader, closing body when they're closed.
+func newRows(body io.Closer, reader resultReader) (*Rows, error) {
+	r := &Rows{body: body, reader: reader}
+	for _, c := range reader.columns() {
+		r.types = append(r.types, &ColumnType{name: c})
+	}
+	if d, ok := reader.(datatyped); ok {
+		for i, datatype := range d.columnDatatypes() {
+			r.types[i].datatype = datatype
+		}
+	}
+
+	// The first row is read ahead to type the columns before Next is called.
+	row, err := reader.next()
+	switch {
+	case err == io.EOF:
+	case err != nil:
+		body.Close()
+		return nil, err
+	default:
+		r.pending = row
+		r.learnTypes(row)
+	}
+	return r, nil
+}
+
+func (r *Rows) learnTypes(row []*ResultTerm) {
+	for i, t := range row {
+		if t != nil && i < len(r.types) && r.types[i].datatype == "" {
+			r.types[i].datatype = t.Datatype
+		}
+	}
+}
+
+// Columns returns the names of the columns.
+func (r *Rows) Columns() []string {
+	return r.reader.columns()
+}
+
+// ColumnTypes returns the types of the columns. They come from the table schema of JSON results
+// requested with IncludeTableSchema, or else are learned from the values read so far, so a column
+// that has only been null has no datatype yet.
+func (r *Rows) ColumnTypes() []*ColumnType {
+	return r.types
+}
+
+// TableSchema returns the table schema of JSON results requested with IncludeTableSchema, or nil for
+// results without one.
+func (r *Rows) TableSchema() *TableSchema {
+	if j, ok := r.reader.(*jsonReader); ok {
+		return j.schema
+	}
+	return nil
+}
+
+// Next prepares the next row for Scan. It returns false at the end of the results or on an error,
+// which Err then returns, and closes the rows.
+func (r *Rows) Next() bool {
+	if r.closed {
+		return false
+	}
+	if r.pending != nil {
+		r.row, r.pending = r.pending, nil
+		r.n++
+		return true
+	}
+	row, err := r.reader.next()
+	if err != nil {
+		if err != io.EOF {
+			r.err = err
+		}
+		r.Close()
+		return false
+	}
+	r.row = row
+	r.n++
+	r.learnTypes(row)
+	return true
+}
+
+// Scan copies the values of the current row into dest, which holds a pointer per column. See
+// `DecodeResults` for the conversions; an *interface{} receives the Go value of the column's
+// datatype.
+func (r *Rows) Scan(dest ...interface{}) error {
+	if r.closed {
+		return ErrRowsClosed
+	}
+	if r.row == nil {
+		return errors.New("dwapi: Scan called without calling Next")
+	}
+	columns := r.Columns()
+	if len(dest) != len(columns) {
+		return fmt.Errorf("dwapi: expected %d destination arguments in Scan, not %d", len(columns), len(dest))
+	}
+	for i, d := range dest {
+		v := reflect.ValueOf(d)
+		if v.Kind() != reflect.Ptr || v.IsNil() {
+			return fmt.Errorf("dwapi: destination argument %d of Scan is not a pointer", i+1)
+		}
+		var t *ResultTerm
+		if i < len(r.row) {
+			t = r.row[i]
+		}
+		if err := setTerm(v.Elem(), t); err != nil {
+			return resultError(r.n, columns[i], t, fmt.Sprintf("argument %d", i+1), v.Elem().Type(), err)
+		}
+	}
+	return nil
+}
+
+// Err returns the error that ended the iteration, if any.
+func (r *Rows) Err() error {
+	return r.err
+}
+
+// Close closes the response body. It is safe to call more than once.
+func (r *Rows) Close() error {
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+	r.row, r.pending = nil, nil
+	return r.body.Close()
+}
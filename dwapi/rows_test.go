@@ -0,0 +1,160 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package dwapi
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestRows(t *testing.T) {
+	body := &closeRecorder{Reader: strings.NewReader(typedResults)}
+	rows, err := NewRows(body, string(FormatSPARQLJSON))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []string{"id", "full_name", "active", "ratio", "count", "ip", "value", "updated", "ignored"},
+		rows.Columns())
+
+	// The types of the columns are known before the first call to Next.
+	types := rows.ColumnTypes()
+	assert.Equal(t, "id", types[0].Name())
+	assert.Equal(t, "xsd:int", types[0].DatabaseTypeName())
+	assert.Equal(t, reflect.TypeOf(int64(0)), types[0].ScanType())
+	assert.Equal(t, "", types[1].DatabaseTypeName())
+	assert.Equal(t, reflect.TypeOf(""), types[1].ScanType())
+	assert.Equal(t, xsd+"dateTime", types[7].Datatype())
+	assert.Equal(t, reflect.TypeOf(time.Time{}), types[7].ScanType())
+
+	var (
+		id      int
+		name    string
+		active  *bool
+		ratio   float64
+		count   interface{}
+		ip      []byte
+		value   interface{}
+		updated time.Time
+		ignored string
+	)
+	dest := []interface{}{&id, &name, &active, &ratio, &count, &ip, &value, &updated, &ignored}
+	assert.EqualError(t, rows.Scan(dest...), "dwapi: Scan called without calling Next")
+
+	assert.True(t, rows.Next())
+	if assert.NoError(t, rows.Scan(dest...)) {
+		assert.Equal(t, 1, id)
+		assert.Equal(t, true, *active)
+		assert.Equal(t, 0.5, ratio)
+		assert.Equal(t, int64(7), count)
+		assert.Equal(t, "10.0.0.1", string(ip))
+		assert.Equal(t, time.Date(2018, 8, 3, 0, 0, 0, 0, time.UTC), value)
+	}
+
+	assert.True(t, rows.Next())
+	if assert.NoError(t, rows.Scan(dest...)) {
+		assert.Equal(t, 2, id)
+		assert.Equal(t, "Grace", name)
+		assert.Nil(t, active)
+		assert.Nil(t, count)
+		assert.True(t, updated.IsZero())
+	}
+	assert.EqualError(t, rows.Scan(&id), "dwapi: expected 9 destination arguments in Scan, not 1")
+	dest[0] = id
+	assert.EqualError(t, rows.Scan(dest...), "dwapi: destination argument 1 of Scan is not a pointer")
+
+	assert.False(t, rows.Next())
+	assert.NoError(t, rows.Err())
+	assert.True(t, body.closed)
+	assert.Equal(t, ErrRowsClosed, rows.Scan(&id))
+	assert.NoError(t, rows.Close())
+}
+
+func TestRows_ScanError(t *testing.T) {
+	rows, err := NewRows(ioutil.NopCloser(strings.NewReader("a\n1\nx\n")), "text/csv")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer rows.Close()
+
+	var n int
+	assert.True(t, rows.Next())
+	assert.NoError(t, rows.Scan(&n))
+	assert.True(t, rows.Next())
+	assert.EqualError(t, rows.Scan(&n), `dwapi: row 2, column "a": can't decode "x" into argument 1 (int): invalid syntax`)
+}
+
+func TestRows_Streaming(t *testing.T) {
+	// Rows are read as they arrive, without waiting for the rest of the body.
+	pr, pw := io.Pipe()
+	more := make(chan bool)
+	go func() {
+		fmt.Fprint(pw, "n\n0\n")
+		for i := 1; <-more; i++ {
+			fmt.Fprintf(pw, "%d\n", i)
+		}
+		pw.CloseWithError(fmt.Errorf("connection reset"))
+	}()
+
+	rows, err := NewRows(pr, "text/csv")
+	if !assert.NoError(t, err) {
+		return
+	}
+	var n int
+	for i := 0; i < 1000; i++ {
+		if i > 0 {
+			more <- true
+		}
+		if !assert.True(t, rows.Next()) {
+			return
+		}
+		assert.NoError(t, rows.Scan(&n))
+		assert.Equal(t, i, n)
+	}
+	close(more)
+	assert.False(t, rows.Next())
+	assert.EqualError(t, rows.Err(), "connection reset")
+}
+
+func TestNewRows_Errors(t *testing.T) {
+	body := &closeRecorder{Reader: strings.NewReader("<sparql/>")}
+	_, err := NewRows(body, "application/sparql-results+xml")
+	assert.Error(t, err)
+	assert.True(t, body.closed)
+
+	body = &closeRecorder{Reader: strings.NewReader(`{"head": {"vars": ["a"]}, "results": {"bindings": [1]}}`)}
+	_, err = NewRows(body, string(FormatSPARQLJSON))
+	assert.Error(t, err)
+	assert.True(t, body.closed)
+}
@@ -0,0 +1,278 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package dwapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// TableSchema describes the columns of JSON query results requested with IncludeTableSchema. It is a
+// Frictionless Data table schema whose fields also have the IRI of their xsd datatype.
+type TableSchema struct {
+	Fields []TableSchemaField `json:"fields"`
+}
+
+// TableSchemaField describes a column.
+type TableSchemaField struct {
+	Name        string `json:"name"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Type is the Frictionless type of the column, e.g. "integer", "number" or "datetime".
+	Type   string `json:"type,omitempty"`
+	Format string `json:"format,omitempty"`
+	// RDFType is the IRI of the xsd datatype of the column, e.g.
+	// "http://www.w3.org/2001/XMLSchema#integer".
+	RDFType     string                  `json:"rdfType,omitempty"`
+	Constraints *TableSchemaConstraints `json:"constraints,omitempty"`
+}
+
+// TableSchemaConstraints restrict the values of a column. Minimum, Maximum and the values of Enum
+// are in the lexical form of the column's type, e.g. "2018-08-03" for a date.
+type TableSchemaConstraints struct {
+	Required  bool     `json:"required,omitempty"`
+	Unique    bool     `json:"unique,omitempty"`
+	MinLength int      `json:"minLength,omitempty"`
+	MaxLength int      `json:"maxLength,omitempty"`
+	Minimum   string   `json:"minimum,omitempty"`
+	Maximum   string   `json:"maximum,omitempty"`
+	Pattern   string   `json:"pattern,omitempty"`
+	Enum      []string `json:"enum,omitempty"`
+}
+
+// UnmarshalJSON accepts the minimum, maximum and enum values as JSON numbers or booleans as well as
+// strings.
+func (c *TableSchemaConstraints) UnmarshalJSON(b []byte) error {
+	type model TableSchemaConstraints
+	var raw struct {
+		*model
+		Minimum json.RawMessage   `json:"minimum"`
+		Maximum json.RawMessage   `json:"maximum"`
+		Enum    []json.RawMessage `json:"enum"`
+	}
+	raw.model = (*model)(c)
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	c.Minimum, c.Maximum, c.Enum = lexical(raw.Minimum), lexical(raw.Maximum), nil
+	for _, e := range raw.Enum {
+		c.Enum = append(c.Enum, lexical(e))
+	}
+	return nil
+}
+
+// lexical returns a JSON string's value, or the JSON of any other value.
+func lexical(raw json.RawMessage) string {
+	if t := jsonTerm(raw); t != nil {
+		return t.Value
+	}
+	return ""
+}
+
+// schemaDatatypes maps the types of Frictionless table schemas to xsd datatypes.
+var schemaDatatypes = map[string]string{
+	"string":    xsd + "string",
+	"integer":   xsd + "integer",
+	"number":    xsd + "decimal",
+	"boolean":   xsd + "boolean",
+	"date":      xsd + "date",
+	"time":      xsd + "time",
+	"datetime":  xsd + "dateTime",
+	"year":      xsd + "gYear",
+	"yearmonth": xsd + "gYearMonth",
+	"duration":  xsd + "duration",
+}
+
+// Field returns the field of a column.
+func (s *TableSchema) Field(name string) (*TableSchemaField, bool) {
+	for i := range s.Fields {
+		if s.Fields[i].Name == name {
+			return &s.Fields[i], true
+		}
+	}
+	return nil, false
+}
+
+// Names returns the names of the columns.
+func (s *TableSchema) Names() []string {
+	names := make([]string, len(s.Fields))
+	for i, f := range s.Fields {
+		names[i] = f.Name
+	}
+	return names
+}
+
+// Datatype returns the IRI of the xsd datatype of the column: its RDFType, or else the datatype of
+// its Frictionless type. It is empty for types without one, such as "object" and "any".
+func (f *TableSchemaField) Datatype() string {
+	if f.RDFType != "" {
+		return f.RDFType
+	}
+	return schemaDatatypes[f.Type]
+}
+
+// Convert converts a value of the column, in its lexical form, into the Go type of its datatype:
+// int64, float64, bool, time.Time, or else string. Integers too large for an int64 stay strings.
+func (f *TableSchemaField) Convert(value string) (interface{}, error) {
+	t := &ResultTerm{Type: "literal", Value: value, Datatype: f.Datatype()}
+	v, err := t.goValue()
+	if err != nil {
+		return nil, fmt.Errorf("dwapi: column %q: can't convert %q to %s: %s", f.Name, value,
+			shortDatatype(t.Datatype), err)
+	}
+	return v, nil
+}
+
+// Validate checks a value of the column against the constraints of the field, other than
+// uniqueness. A nil value is a null.
+func (f *TableSchemaField) Validate(value *string) error {
+	c := f.Constraints
+	if c == nil {
+		return nil
+	}
+	if value == nil {
+		if c.Required {
+			return f.constraintError("is required")
+		}
+		return nil
+	}
+	s := *value
+
+	if n := utf8.RuneCountInString(s); c.MinLength > 0 && n < c.MinLength || c.MaxLength > 0 && n > c.MaxLength {
+		return f.constraintError("length of %q is out of range", s)
+	}
+	if c.Pattern != "" {
+		re, err := regexp.Compile("^(?:" + c.Pattern + ")$")
+		if err != nil {
+			return f.constraintError("has invalid pattern %q", c.Pattern)
+		}
+		if !re.MatchString(s) {
+			return f.constraintError("%q doesn't match %q", s, c.Pattern)
+		}
+	}
+	if len(c.Enum) > 0 {
+		found := false
+		for _, e := range c.Enum {
+			if f.compare(s, e) == 0 {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return f.constraintError("%q is not one of %q", s, c.Enum)
+		}
+	}
+	if c.Minimum != "" && f.compare(s, c.Minimum) < 0 {
+		return f.constraintError("%q is less than %s", s, c.Minimum)
+	}
+	if c.Maximum != "" && f.compare(s, c.Maximum) > 0 {
+		return f.constraintError("%q is greater than %s", s, c.Maximum)
+	}
+	return nil
+}
+
+func (f *TableSchemaField) constraintError(format string, args ...interface{}) error {
+	return fmt.Errorf("dwapi: column %q: value %s", f.Name, fmt.Sprintf(format, args...))
+}
+
+// compare compares two values of the column as values of its datatype, or as strings if either
+// isn't a valid one. Numbers are compared exactly by their lexical forms, as neither int64 nor
+// float64 holds every integer or decimal.
+func (f *TableSchemaField) compare(a, b string) int {
+	t := &ResultTerm{Type: "literal", Datatype: f.Datatype()}
+	if c := t.category(); c == "int" || c == "float" {
+		if ra, ok := exactNumber(a); ok {
+			if rb, ok := exactNumber(b); ok {
+				return ra.Cmp(rb)
+			}
+		}
+	}
+	va, errA := f.Convert(a)
+	vb, errB := f.Convert(b)
+	if errA == nil && errB == nil {
+		switch va := va.(type) {
+		case float64:
+			return compareFloats(va, vb.(float64))
+		case time.Time:
+			return compareFloats(float64(va.Sub(vb.(time.Time))), 0)
+		}
+	}
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// exactNumber parses the lexical form of an integer, a decimal or a double other than INF and NaN.
+func exactNumber(s string) (*big.Rat, bool) {
+	if strings.ContainsAny(s, "/ ") {
+		return nil, false
+	}
+	return new(big.Rat).SetString(s)
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// DecodeSchemaResults reads JSON results requested with IncludeTableSchema, separating the schema
+// from the rows. The values of the rows are converted according to the schema as by
+// `TableSchemaField.Convert`, and nulls are nil. The schema is nil for results without one, whose
+// values keep their JSON types.
+func DecodeSchemaResults(r io.Reader) (schema *TableSchema, rows []map[string]interface{}, err error) {
+	j, err := newJSONReader(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	for {
+		row, err := j.next()
+		if err == io.EOF {
+			return j.schema, rows, nil
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		values := make(map[string]interface{}, len(row))
+		for i, t := range row {
+			if t == nil {
+				values[j.keys[i]] = nil
+				continue
+			}
+			if values[j.keys[i]], err = t.goValue(); err != nil {
+				return nil, nil, fmt.Errorf("dwapi: row %d, column %q: can't convert %q to %s: %s",
+					len(rows)+1, j.keys[i], t.Value, shortDatatype(t.Datatype), err)
+			}
+		}
+		rows = append(rows, values)
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package dwapi
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const schemaResults = `[
+	{"fields": [
+		{"name": "region", "type": "string", "description": "Sales region",
+			"constraints": {"required": true, "enum": ["EMEA", "APAC"]}},
+		{"name": "total", "type": "number", "rdfType": "http://www.w3.org/2001/XMLSchema#double",
+			"constraints": {"minimum": 0, "maximum": 1e6}},
+		{"name": "closed", "type": "date", "constraints": {"minimum": "2018-01-01"}},
+		{"name": "tags", "type": "array"}
+	]},
+	{"region": "EMEA", "total": 12.5, "closed": "2018-08-03", "tags": "[\"a\"]"},
+	{"region": "APAC", "total": null}
+]`
+
+func TestTableSchema_Unmarshal(t *testing.T) {
+	var schema TableSchema
+	err := json.Unmarshal([]byte(`{"fields": [{"name": "n", "type": "integer", "title": "N",
+		"constraints": {"minimum": 1, "maxLength": 3, "pattern": "[0-9]+", "enum": [1, "2", true], "unique": true}}]}`),
+		&schema)
+	assert.NoError(t, err)
+	assert.Equal(t, TableSchema{Fields: []TableSchemaField{{
+		Name:  "n",
+		Title: "N",
+		Type:  "integer",
+		Constraints: &TableSchemaConstraints{
+			Unique:    true,
+			MaxLength: 3,
+			Minimum:   "1",
+			Pattern:   "[0-9]+",
+			Enum:      []string{"1", "2", "true"},
+		},
+	}}}, schema)
+
+	field, ok := schema.Field("n")
+	assert.True(t, ok)
+	assert.Equal(t, xsd+"integer", field.Datatype())
+	_, ok = schema.Field("m")
+	assert.False(t, ok)
+}
+
+func TestTableSchemaField_Convert(t *testing.T) {
+	tests := []struct {
+		field TableSchemaField
+		value string
+		want  interface{}
+	}{
+		{TableSchemaField{Type: "integer"}, "42", int64(42)},
+		{TableSchemaField{Type: "integer"}, "123456789012345678901234567890", "123456789012345678901234567890"},
+		{TableSchemaField{Type: "number"}, "1.5", 1.5},
+		{TableSchemaField{Type: "boolean"}, "true", true},
+		{TableSchemaField{Type: "datetime"}, "2018-08-03T15:56:41Z", time.Date(2018, 8, 3, 15, 56, 41, 0, time.UTC)},
+		{TableSchemaField{Type: "string", RDFType: xsd + "date"}, "2018-08-03", time.Date(2018, 8, 3, 0, 0, 0, 0, time.UTC)},
+		{TableSchemaField{Type: "object"}, `{"a": 1}`, `{"a": 1}`},
+		{TableSchemaField{}, "x", "x"},
+	}
+	for _, tt := range tests {
+		got, err := tt.field.Convert(tt.value)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, got)
+	}
+
+	f := TableSchemaField{Name: "n", Type: "number"}
+	_, err := f.Convert("many")
+	assert.EqualError(t, err, `dwapi: column "n": can't convert "many" to xsd:decimal: invalid syntax`)
+}
+
+func TestTableSchemaField_Validate(t *testing.T) {
+	str := func(s string) *string { return &s }
+	f := TableSchemaField{Name: "n", Type: "integer", Constraints: &TableSchemaConstraints{
+		Required: true, Minimum: "2", Maximum: "10", Enum: []string{"2", "5", "10"},
+	}}
+	assert.NoError(t, f.Validate(str("5")))
+	assert.NoError(t, f.Validate(str("+05")))
+	assert.EqualError(t, f.Validate(nil), `dwapi: column "n": value is required`)
+	assert.EqualError(t, f.Validate(str("7")), `dwapi: column "n": value "7" is not one of ["2" "5" "10"]`)
+
+	f.Constraints.Enum = nil
+	assert.EqualError(t, f.Validate(str("1")), `dwapi: column "n": value "1" is less than 2`)
+	assert.EqualError(t, f.Validate(str("11")), `dwapi: column "n": value "11" is greater than 10`)
+
+	s := TableSchemaField{Name: "code", Constraints: &TableSchemaConstraints{MinLength: 2, MaxLength: 3, Pattern: "[A-Z]+"}}
+	assert.NoError(t, s.Validate(nil))
+	assert.NoError(t, s.Validate(str("ABC")))
+	assert.EqualError(t, s.Validate(str("A")), `dwapi: column "code": value length of "A" is out of range`)
+	assert.EqualError(t, s.Validate(str("ab")), `dwapi: column "code": value "ab" doesn't match "[A-Z]+"`)
+
+	// Integers above 2^53 are compared exactly.
+	big := TableSchemaField{Name: "id", Type: "integer", Constraints: &TableSchemaConstraints{Maximum: "9007199254740992"}}
+	assert.NoError(t, big.Validate(str("9007199254740992")))
+	assert.EqualError(t, big.Validate(str("9007199254740993")),
+		`dwapi: column "id": value "9007199254740993" is greater than 9007199254740992`)
+	big.Type = "number"
+	assert.Error(t, big.Validate(str("9007199254740993")))
+	assert.NoError(t, big.Validate(str("9007199254740991.5")))
+	assert.Error(t, big.Validate(str("9007199254740992.5")))
+
+	// So are bounds beyond int64.
+	huge := TableSchemaField{Name: "id", Type: "integer", Constraints: &TableSchemaConstraints{
+		Minimum: "-100000000000000000000", Maximum: "100000000000000000000"}}
+	assert.NoError(t, huge.Validate(str("50")))
+	assert.NoError(t, huge.Validate(str("-99999999999999999999")))
+	assert.EqualError(t, huge.Validate(str("100000000000000000001")),
+		`dwapi: column "id": value "100000000000000000001" is greater than 100000000000000000000`)
+	huge.Type = "number"
+	assert.NoError(t, huge.Validate(str("99999999999999999999.9")))
+	assert.NoError(t, huge.Validate(str("1e20")))
+	assert.Error(t, huge.Validate(str("1.00000000000000000001e20")))
+
+	d := TableSchemaField{Name: "day", Type: "date", Constraints: &TableSchemaConstraints{Minimum: "2018-01-01"}}
+	assert.NoError(t, d.Validate(str("2018-08-03")))
+	assert.Error(t, d.Validate(str("2017-12-31")))
+	assert.NoError(t, (&TableSchemaField{}).Validate(nil))
+}
+
+func TestDecodeSchemaResults(t *testing.T) {
+	schema, rows, err := DecodeSchemaResults(strings.NewReader(schemaResults))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []string{"region", "total", "closed", "tags"}, schema.Names())
+	assert.Equal(t, "Sales region", schema.Fields[0].Description)
+	assert.Equal(t, "1e6", schema.Fields[1].Constraints.Maximum)
+	assert.Equal(t, []map[string]interface{}{
+		{"region": "EMEA", "total": 12.5, "closed": time.Date(2018, 8, 3, 0, 0, 0, 0, time.UTC), "tags": `["a"]`},
+		{"region": "APAC", "total": nil, "closed": nil, "tags": nil},
+	}, rows)
+
+	schema, rows, err = DecodeSchemaResults(strings.NewReader(`[{"n": 1, "ok": true}]`))
+	assert.NoError(t, err)
+	assert.Nil(t, schema)
+	assert.Equal(t, []map[string]interface{}{{"n": int64(1), "ok": true}}, rows)
+
+	_, _, err = DecodeSchemaResults(strings.NewReader(`[{"fields": [{"name": "n", "type": "integer"}]}, {"n": 1.5}]`))
+	assert.EqualError(t, err, `dwapi: row 1, column "n": can't convert "1.5" to xsd:integer: invalid syntax`)
+}
+
+func TestRows_TableSchema(t *testing.T) {
+	rows, err := NewRows(ioutil.NopCloser(strings.NewReader(schemaResults)), "application/json")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer rows.Close()
+	assert.Equal(t, "Sales region", rows.TableSchema().Fields[0].Description)
+
+	rows, err = NewRows(ioutil.NopCloser(strings.NewReader("a\n1\n")), "text/csv")
+	if assert.NoError(t, err) {
+		assert.Nil(t, rows.TableSchema())
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+/*
+Package dwapimock provides mock implementations of the service interfaces in the dwapi package
+(`dwapi.DatasetAPI`, `dwapi.QueryAPI`, ...), so code that depends on them can be tested without an
+HTTP server.
+
+Each mock has one Func field per method. Calls are recorded whether or not the matching Func is set:
+
+	q := &dwapimock.QueryAPI{}
+	q.RetrieveFunc = func(queryid string) (dwapi.QuerySummaryResponse, error) {
+		return dwapi.QuerySummaryResponse{ID: queryid}, nil
+	}
+	runReport(q)
+	calls := q.CallsTo("Retrieve")
+
+The mocks in mocks.go are generated from dwapi/interfaces.go; run `go generate ./dwapi` after
+changing the interfaces.
+*/
+package dwapimock
+
+import (
+	"sync"
+)
+
+// Call is a single recorded invocation of a mocked method.
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+// Client bundles one mock per service, mirroring the fields of `dwapi.Client`.
+type Client struct {
+	Dataset *DatasetAPI
+	DOI     *DOIAPI
+	File    *FileAPI
+	Insight *InsightAPI
+	Project *ProjectAPI
+	Query   *QueryAPI
+	Stream  *StreamAPI
+	User    *UserAPI
+	Webhook *WebhookAPI
+}
+
+// NewClient returns a Client with all of its mocks initialized.
+func NewClient() *Client {
+	return &Client{
+		Dataset: &DatasetAPI{},
+		DOI:     &DOIAPI{},
+		File:    &FileAPI{},
+		Insight: &InsightAPI{},
+		Project: &ProjectAPI{},
+		Query:   &QueryAPI{},
+		Stream:  &StreamAPI{},
+		User:    &UserAPI{},
+		Webhook: &WebhookAPI{},
+	}
+}
+
+type recorder struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+func (r *recorder) record(method string, args []interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, Call{Method: method, Args: args})
+}
+
+// Calls returns every call made to the mock, in order.
+func (r *recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Call(nil), r.calls...)
+}
+
+// CallsTo returns the calls made to the named method, in order.
+func (r *recorder) CallsTo(method string) []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var calls []Call
+	for _, c := range r.calls {
+		if c.Method == method {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// Reset forgets all recorded calls.
+func (r *recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = nil
+}
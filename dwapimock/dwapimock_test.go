@@ -0,0 +1,73 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package dwapimock
+
+import (
+	"testing"
+
+	"github.com/datadotworld/dwapi-go/dwapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func listOwnedTitles(d dwapi.DatasetAPI) ([]string, error) {
+	datasets, err := d.Owned()
+	if err != nil {
+		return nil, err
+	}
+	var titles []string
+	for _, ds := range datasets {
+		titles = append(titles, ds.Title)
+	}
+	return titles, nil
+}
+
+func TestDatasetAPI_Func(t *testing.T) {
+	m := &DatasetAPI{}
+	m.OwnedFunc = func() ([]dwapi.DatasetSummaryResponse, error) {
+		return []dwapi.DatasetSummaryResponse{{Title: "My Awesome Dataset"}}, nil
+	}
+
+	got, err := listOwnedTitles(m)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"My Awesome Dataset"}, got)
+	}
+	assert.Len(t, m.CallsTo("Owned"), 1)
+}
+
+func TestQueryAPI_Unset(t *testing.T) {
+	m := &QueryAPI{}
+	_, err := m.Retrieve("unique.id")
+	assert.EqualError(t, err, "dwapimock: QueryAPI.Retrieve called without RetrieveFunc set")
+}
+
+func TestRecorder(t *testing.T) {
+	c := NewClient()
+	_, _ = c.Query.RetrieveVersion("unique.id", "some.version")
+	_, _ = c.Query.Retrieve("unique.id")
+	_, _ = c.Query.RetrieveVersion("unique.id", "another.version")
+
+	want := []Call{
+		{Method: "RetrieveVersion", Args: []interface{}{"unique.id", "some.version"}},
+		{Method: "RetrieveVersion", Args: []interface{}{"unique.id", "another.version"}},
+	}
+	assert.Equal(t, want, c.Query.CallsTo("RetrieveVersion"))
+	assert.Len(t, c.Query.Calls(), 3)
+
+	c.Query.Reset()
+	assert.Empty(t, c.Query.Calls())
+}
@@ -0,0 +1,246 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+// Command gen writes the mock implementations in the dwapimock package from the service interfaces
+// declared in the dwapi package. It is run through `go generate` in the dwapi directory.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const header = `// Code generated by dwapimock/internal/gen. DO NOT EDIT.
+
+package dwapimock
+
+import (
+	"fmt"
+%s
+	"github.com/datadotworld/dwapi-go/dwapi"
+)
+`
+
+func main() {
+	in := flag.String("in", "interfaces.go", "file declaring the dwapi service interfaces")
+	out := flag.String("out", "mocks.go", "file to write the mocks to")
+	flag.Parse()
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, *in, nil, 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var interfaces []*ast.TypeSpec
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			ts := spec.(*ast.TypeSpec)
+			if _, ok := ts.Type.(*ast.InterfaceType); ok && ast.IsExported(ts.Name.Name) {
+				interfaces = append(interfaces, ts)
+			}
+		}
+	}
+	sort.Slice(interfaces, func(i, j int) bool { return interfaces[i].Name.Name < interfaces[j].Name.Name })
+
+	body := new(bytes.Buffer)
+	for _, ts := range interfaces {
+		writeMock(body, ts.Name.Name, ts.Type.(*ast.InterfaceType))
+	}
+
+	// The only standard library package referenced by the service signatures is io.
+	stdlib := ""
+	if regexp.MustCompile(`[^\w.]io\.`).Match(body.Bytes()) {
+		stdlib = "\t\"io\"\n"
+	}
+	b := new(bytes.Buffer)
+	fmt.Fprintf(b, header, stdlib)
+	b.Write(body.Bytes())
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		log.Fatalf("formatting generated code: %v\n%s", err, b.String())
+	}
+	if err = ioutil.WriteFile(*out, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+type method struct {
+	name    string
+	params  []param
+	results []string
+}
+
+type param struct {
+	name     string
+	typ      string
+	variadic bool
+}
+
+func writeMock(b *bytes.Buffer, name string, it *ast.InterfaceType) {
+	var methods []method
+	for _, field := range it.Methods.List {
+		ft, ok := field.Type.(*ast.FuncType)
+		if !ok {
+			log.Fatalf("%s: embedded interfaces are not supported", name)
+		}
+		m := method{name: field.Names[0].Name}
+		for i, p := range fieldList(ft.Params) {
+			t := p.Type
+			_, variadic := t.(*ast.Ellipsis)
+			if variadic {
+				t = t.(*ast.Ellipsis).Elt
+			}
+			typ := typeString(t)
+			if len(p.Names) == 0 {
+				m.params = append(m.params, param{fmt.Sprintf("p%d", i), typ, variadic})
+			}
+			for _, n := range p.Names {
+				m.params = append(m.params, param{n.Name, typ, variadic})
+			}
+		}
+		for _, r := range fieldList(ft.Results) {
+			n := len(r.Names)
+			if n == 0 {
+				n = 1
+			}
+			for i := 0; i < n; i++ {
+				m.results = append(m.results, typeString(r.Type))
+			}
+		}
+		methods = append(methods, m)
+	}
+
+	fmt.Fprintf(b, "\n// %s is a mock implementation of dwapi.%s. Set the Func field matching a method to control\n", name, name)
+	fmt.Fprintf(b, "// its behavior; methods whose Func is unset return zero values and an error.\n")
+	fmt.Fprintf(b, "type %s struct {\n\trecorder\n\n", name)
+	for _, m := range methods {
+		fmt.Fprintf(b, "\t%sFunc func(%s) %s\n", m.name, m.signature(), m.resultList())
+	}
+	fmt.Fprintf(b, "}\n\nvar _ dwapi.%s = (*%s)(nil)\n", name, name)
+
+	for _, m := range methods {
+		var args []string
+		call := make([]string, len(m.params))
+		for i, p := range m.params {
+			args = append(args, p.name)
+			call[i] = p.name
+			if p.variadic {
+				call[i] += "..."
+			}
+		}
+		fmt.Fprintf(b, "\n// %s records the call and invokes %sFunc.\n", m.name, m.name)
+		fmt.Fprintf(b, "func (m *%s) %s(%s) %s {\n", name, m.name, m.signature(), m.resultList())
+		fmt.Fprintf(b, "\tm.record(%q, []interface{}{%s})\n", m.name, strings.Join(args, ", "))
+		fmt.Fprintf(b, "\tif m.%sFunc == nil {\n", m.name)
+		var zeros []string
+		for i, r := range m.results {
+			if r == "error" && i == len(m.results)-1 {
+				zeros = append(zeros, fmt.Sprintf("fmt.Errorf(\"dwapimock: %s.%s called without %sFunc set\")", name, m.name, m.name))
+				continue
+			}
+			fmt.Fprintf(b, "\t\tvar r%d %s\n", i, r)
+			zeros = append(zeros, fmt.Sprintf("r%d", i))
+		}
+		fmt.Fprintf(b, "\t\treturn %s\n\t}\n", strings.Join(zeros, ", "))
+		fmt.Fprintf(b, "\treturn m.%sFunc(%s)\n}\n", m.name, strings.Join(call, ", "))
+	}
+}
+
+func (m method) signature() string {
+	var parts []string
+	for _, p := range m.params {
+		t := p.typ
+		if p.variadic {
+			t = "..." + t
+		}
+		parts = append(parts, p.name+" "+t)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (m method) resultList() string {
+	switch len(m.results) {
+	case 0:
+		return ""
+	case 1:
+		return m.results[0]
+	}
+	return "(" + strings.Join(m.results, ", ") + ")"
+}
+
+func fieldList(fl *ast.FieldList) []*ast.Field {
+	if fl == nil {
+		return nil
+	}
+	return fl.List
+}
+
+// typeString prints a type expression from the dwapi package so that it can be used from dwapimock,
+// qualifying the exported identifiers declared in dwapi.
+func typeString(expr ast.Expr) string {
+	return types.ExprString(qualify(expr))
+}
+
+func qualify(expr ast.Expr) ast.Expr {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if ast.IsExported(t.Name) {
+			return &ast.SelectorExpr{X: ast.NewIdent("dwapi"), Sel: t}
+		}
+	case *ast.StarExpr:
+		return &ast.StarExpr{X: qualify(t.X)}
+	case *ast.ArrayType:
+		return &ast.ArrayType{Len: t.Len, Elt: qualify(t.Elt)}
+	case *ast.MapType:
+		return &ast.MapType{Key: qualify(t.Key), Value: qualify(t.Value)}
+	case *ast.ChanType:
+		return &ast.ChanType{Dir: t.Dir, Value: qualify(t.Value)}
+	case *ast.FuncType:
+		return &ast.FuncType{Params: qualifyFields(t.Params), Results: qualifyFields(t.Results)}
+	case *ast.Ellipsis:
+		return &ast.Ellipsis{Elt: qualify(t.Elt)}
+	}
+	return expr
+}
+
+func qualifyFields(fl *ast.FieldList) *ast.FieldList {
+	if fl == nil {
+		return nil
+	}
+	out := &ast.FieldList{}
+	for _, f := range fl.List {
+		out.List = append(out.List, &ast.Field{Names: f.Names, Type: qualify(f.Type)})
+	}
+	return out
+}
@@ -0,0 +1,1353 @@
+// Code generated by dwapimock/internal/gen. DO NOT EDIT.
+
+package dwapimock
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/datadotworld/dwapi-go/dwapi"
+)
+
+// DOIAPI is a mock implementation of dwapi.DOIAPI. Set the Func field matching a method to control
+// its behavior; methods whose Func is unset return zero values and an error.
+type DOIAPI struct {
+	recorder
+
+	AssociateFunc                   func(owner string, datasetid string, doi string) (dwapi.SuccessResponse, error)
+	AssociateWithVersionFunc        func(owner string, datasetid string, versionid string, doi string) (dwapi.SuccessResponse, error)
+	DeleteFunc                      func(owner string, datasetid string, doi string) (dwapi.SuccessResponse, error)
+	DeleteAssociatedWithVersionFunc func(owner string, datasetid string, versionid string, doi string) (dwapi.SuccessResponse, error)
+}
+
+var _ dwapi.DOIAPI = (*DOIAPI)(nil)
+
+// Associate records the call and invokes AssociateFunc.
+func (m *DOIAPI) Associate(owner string, datasetid string, doi string) (dwapi.SuccessResponse, error) {
+	m.record("Associate", []interface{}{owner, datasetid, doi})
+	if m.AssociateFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: DOIAPI.Associate called without AssociateFunc set")
+	}
+	return m.AssociateFunc(owner, datasetid, doi)
+}
+
+// AssociateWithVersion records the call and invokes AssociateWithVersionFunc.
+func (m *DOIAPI) AssociateWithVersion(owner string, datasetid string, versionid string, doi string) (dwapi.SuccessResponse, error) {
+	m.record("AssociateWithVersion", []interface{}{owner, datasetid, versionid, doi})
+	if m.AssociateWithVersionFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: DOIAPI.AssociateWithVersion called without AssociateWithVersionFunc set")
+	}
+	return m.AssociateWithVersionFunc(owner, datasetid, versionid, doi)
+}
+
+// Delete records the call and invokes DeleteFunc.
+func (m *DOIAPI) Delete(owner string, datasetid string, doi string) (dwapi.SuccessResponse, error) {
+	m.record("Delete", []interface{}{owner, datasetid, doi})
+	if m.DeleteFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: DOIAPI.Delete called without DeleteFunc set")
+	}
+	return m.DeleteFunc(owner, datasetid, doi)
+}
+
+// DeleteAssociatedWithVersion records the call and invokes DeleteAssociatedWithVersionFunc.
+func (m *DOIAPI) DeleteAssociatedWithVersion(owner string, datasetid string, versionid string, doi string) (dwapi.SuccessResponse, error) {
+	m.record("DeleteAssociatedWithVersion", []interface{}{owner, datasetid, versionid, doi})
+	if m.DeleteAssociatedWithVersionFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: DOIAPI.DeleteAssociatedWithVersion called without DeleteAssociatedWithVersionFunc set")
+	}
+	return m.DeleteAssociatedWithVersionFunc(owner, datasetid, versionid, doi)
+}
+
+// DatasetAPI is a mock implementation of dwapi.DatasetAPI. Set the Func field matching a method to control
+// its behavior; methods whose Func is unset return zero values and an error.
+type DatasetAPI struct {
+	recorder
+
+	AddFilesFromURLsFunc               func(owner string, datasetid string, body *[]dwapi.FileCreateRequest) (dwapi.SuccessResponse, error)
+	AssociateDOIFunc                   func(owner string, datasetid string, doi string) (dwapi.SuccessResponse, error)
+	AssociateDOIWithVersionFunc        func(owner string, datasetid string, versionid string, doi string) (dwapi.SuccessResponse, error)
+	ContributingFunc                   func() ([]dwapi.DatasetSummaryResponse, error)
+	CreateFunc                         func(owner string, body *dwapi.DatasetCreateRequest) (dwapi.DatasetCreateResponse, error)
+	CreateOrReplaceFunc                func(owner string, id string, body *dwapi.DatasetReplaceRequest) (dwapi.SuccessResponse, error)
+	DeleteFunc                         func(owner string, datasetid string) (dwapi.SuccessResponse, error)
+	DeleteDOIFunc                      func(owner string, datasetid string, doi string) (dwapi.SuccessResponse, error)
+	DeleteDOIAssociatedWithVersionFunc func(owner string, datasetid string, versionid string, doi string) (dwapi.SuccessResponse, error)
+	DownloadFileFunc                   func(owner string, datasetid string, filename string) (io.Reader, error)
+	DownloadAndSaveFileFunc            func(owner string, datasetid string, filename string, path string) (dwapi.SuccessResponse, error)
+	DownloadFunc                       func(owner string, datasetid string, filename string) (io.Reader, error)
+	DownloadAndSaveFunc                func(owner string, datasetid string, path string) (dwapi.SuccessResponse, error)
+	LikedFunc                          func() ([]dwapi.DatasetSummaryResponse, error)
+	ListQueriesFunc                    func(owner string, datasetid string) ([]dwapi.QuerySummaryResponse, error)
+	OwnedFunc                          func() ([]dwapi.DatasetSummaryResponse, error)
+	RetrieveFunc                       func(owner string, datasetid string) (dwapi.DatasetSummaryResponse, error)
+	RetrieveVersionFunc                func(owner string, datasetid string, versionid string) (dwapi.DatasetSummaryResponse, error)
+	SyncFunc                           func(owner string, datasetid string) (dwapi.SuccessResponse, error)
+	UpdateFunc                         func(owner string, id string, body *dwapi.DatasetUpdateRequest) (dwapi.SuccessResponse, error)
+	UploadFileFunc                     func(owner string, id string, filename string, path string, expandArchive bool) (dwapi.SuccessResponse, error)
+}
+
+var _ dwapi.DatasetAPI = (*DatasetAPI)(nil)
+
+// AddFilesFromURLs records the call and invokes AddFilesFromURLsFunc.
+func (m *DatasetAPI) AddFilesFromURLs(owner string, datasetid string, body *[]dwapi.FileCreateRequest) (dwapi.SuccessResponse, error) {
+	m.record("AddFilesFromURLs", []interface{}{owner, datasetid, body})
+	if m.AddFilesFromURLsFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: DatasetAPI.AddFilesFromURLs called without AddFilesFromURLsFunc set")
+	}
+	return m.AddFilesFromURLsFunc(owner, datasetid, body)
+}
+
+// AssociateDOI records the call and invokes AssociateDOIFunc.
+func (m *DatasetAPI) AssociateDOI(owner string, datasetid string, doi string) (dwapi.SuccessResponse, error) {
+	m.record("AssociateDOI", []interface{}{owner, datasetid, doi})
+	if m.AssociateDOIFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: DatasetAPI.AssociateDOI called without AssociateDOIFunc set")
+	}
+	return m.AssociateDOIFunc(owner, datasetid, doi)
+}
+
+// AssociateDOIWithVersion records the call and invokes AssociateDOIWithVersionFunc.
+func (m *DatasetAPI) AssociateDOIWithVersion(owner string, datasetid string, versionid string, doi string) (dwapi.SuccessResponse, error) {
+	m.record("AssociateDOIWithVersion", []interface{}{owner, datasetid, versionid, doi})
+	if m.AssociateDOIWithVersionFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: DatasetAPI.AssociateDOIWithVersion called without AssociateDOIWithVersionFunc set")
+	}
+	return m.AssociateDOIWithVersionFunc(owner, datasetid, versionid, doi)
+}
+
+// Contributing records the call and invokes ContributingFunc.
+func (m *DatasetAPI) Contributing() ([]dwapi.DatasetSummaryResponse, error) {
+	m.record("Contributing", []interface{}{})
+	if m.ContributingFunc == nil {
+		var r0 []dwapi.DatasetSummaryResponse
+		return r0, fmt.Errorf("dwapimock: DatasetAPI.Contributing called without ContributingFunc set")
+	}
+	return m.ContributingFunc()
+}
+
+// Create records the call and invokes CreateFunc.
+func (m *DatasetAPI) Create(owner string, body *dwapi.DatasetCreateRequest) (dwapi.DatasetCreateResponse, error) {
+	m.record("Create", []interface{}{owner, body})
+	if m.CreateFunc == nil {
+		var r0 dwapi.DatasetCreateResponse
+		return r0, fmt.Errorf("dwapimock: DatasetAPI.Create called without CreateFunc set")
+	}
+	return m.CreateFunc(owner, body)
+}
+
+// CreateOrReplace records the call and invokes CreateOrReplaceFunc.
+func (m *DatasetAPI) CreateOrReplace(owner string, id string, body *dwapi.DatasetReplaceRequest) (dwapi.SuccessResponse, error) {
+	m.record("CreateOrReplace", []interface{}{owner, id, body})
+	if m.CreateOrReplaceFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: DatasetAPI.CreateOrReplace called without CreateOrReplaceFunc set")
+	}
+	return m.CreateOrReplaceFunc(owner, id, body)
+}
+
+// Delete records the call and invokes DeleteFunc.
+func (m *DatasetAPI) Delete(owner string, datasetid string) (dwapi.SuccessResponse, error) {
+	m.record("Delete", []interface{}{owner, datasetid})
+	if m.DeleteFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: DatasetAPI.Delete called without DeleteFunc set")
+	}
+	return m.DeleteFunc(owner, datasetid)
+}
+
+// DeleteDOI records the call and invokes DeleteDOIFunc.
+func (m *DatasetAPI) DeleteDOI(owner string, datasetid string, doi string) (dwapi.SuccessResponse, error) {
+	m.record("DeleteDOI", []interface{}{owner, datasetid, doi})
+	if m.DeleteDOIFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: DatasetAPI.DeleteDOI called without DeleteDOIFunc set")
+	}
+	return m.DeleteDOIFunc(owner, datasetid, doi)
+}
+
+// DeleteDOIAssociatedWithVersion records the call and invokes DeleteDOIAssociatedWithVersionFunc.
+func (m *DatasetAPI) DeleteDOIAssociatedWithVersion(owner string, datasetid string, versionid string, doi string) (dwapi.SuccessResponse, error) {
+	m.record("DeleteDOIAssociatedWithVersion", []interface{}{owner, datasetid, versionid, doi})
+	if m.DeleteDOIAssociatedWithVersionFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: DatasetAPI.DeleteDOIAssociatedWithVersion called without DeleteDOIAssociatedWithVersionFunc set")
+	}
+	return m.DeleteDOIAssociatedWithVersionFunc(owner, datasetid, versionid, doi)
+}
+
+// DownloadFile records the call and invokes DownloadFileFunc.
+func (m *DatasetAPI) DownloadFile(owner string, datasetid string, filename string) (io.Reader, error) {
+	m.record("DownloadFile", []interface{}{owner, datasetid, filename})
+	if m.DownloadFileFunc == nil {
+		var r0 io.Reader
+		return r0, fmt.Errorf("dwapimock: DatasetAPI.DownloadFile called without DownloadFileFunc set")
+	}
+	return m.DownloadFileFunc(owner, datasetid, filename)
+}
+
+// DownloadAndSaveFile records the call and invokes DownloadAndSaveFileFunc.
+func (m *DatasetAPI) DownloadAndSaveFile(owner string, datasetid string, filename string, path string) (dwapi.SuccessResponse, error) {
+	m.record("DownloadAndSaveFile", []interface{}{owner, datasetid, filename, path})
+	if m.DownloadAndSaveFileFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: DatasetAPI.DownloadAndSaveFile called without DownloadAndSaveFileFunc set")
+	}
+	return m.DownloadAndSaveFileFunc(owner, datasetid, filename, path)
+}
+
+// Download records the call and invokes DownloadFunc.
+func (m *DatasetAPI) Download(owner string, datasetid string, filename string) (io.Reader, error) {
+	m.record("Download", []interface{}{owner, datasetid, filename})
+	if m.DownloadFunc == nil {
+		var r0 io.Reader
+		return r0, fmt.Errorf("dwapimock: DatasetAPI.Download called without DownloadFunc set")
+	}
+	return m.DownloadFunc(owner, datasetid, filename)
+}
+
+// DownloadAndSave records the call and invokes DownloadAndSaveFunc.
+func (m *DatasetAPI) DownloadAndSave(owner string, datasetid string, path string) (dwapi.SuccessResponse, error) {
+	m.record("DownloadAndSave", []interface{}{owner, datasetid, path})
+	if m.DownloadAndSaveFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: DatasetAPI.DownloadAndSave called without DownloadAndSaveFunc set")
+	}
+	return m.DownloadAndSaveFunc(owner, datasetid, path)
+}
+
+// Liked records the call and invokes LikedFunc.
+func (m *DatasetAPI) Liked() ([]dwapi.DatasetSummaryResponse, error) {
+	m.record("Liked", []interface{}{})
+	if m.LikedFunc == nil {
+		var r0 []dwapi.DatasetSummaryResponse
+		return r0, fmt.Errorf("dwapimock: DatasetAPI.Liked called without LikedFunc set")
+	}
+	return m.LikedFunc()
+}
+
+// ListQueries records the call and invokes ListQueriesFunc.
+func (m *DatasetAPI) ListQueries(owner string, datasetid string) ([]dwapi.QuerySummaryResponse, error) {
+	m.record("ListQueries", []interface{}{owner, datasetid})
+	if m.ListQueriesFunc == nil {
+		var r0 []dwapi.QuerySummaryResponse
+		return r0, fmt.Errorf("dwapimock: DatasetAPI.ListQueries called without ListQueriesFunc set")
+	}
+	return m.ListQueriesFunc(owner, datasetid)
+}
+
+// Owned records the call and invokes OwnedFunc.
+func (m *DatasetAPI) Owned() ([]dwapi.DatasetSummaryResponse, error) {
+	m.record("Owned", []interface{}{})
+	if m.OwnedFunc == nil {
+		var r0 []dwapi.DatasetSummaryResponse
+		return r0, fmt.Errorf("dwapimock: DatasetAPI.Owned called without OwnedFunc set")
+	}
+	return m.OwnedFunc()
+}
+
+// Retrieve records the call and invokes RetrieveFunc.
+func (m *DatasetAPI) Retrieve(owner string, datasetid string) (dwapi.DatasetSummaryResponse, error) {
+	m.record("Retrieve", []interface{}{owner, datasetid})
+	if m.RetrieveFunc == nil {
+		var r0 dwapi.DatasetSummaryResponse
+		return r0, fmt.Errorf("dwapimock: DatasetAPI.Retrieve called without RetrieveFunc set")
+	}
+	return m.RetrieveFunc(owner, datasetid)
+}
+
+// RetrieveVersion records the call and invokes RetrieveVersionFunc.
+func (m *DatasetAPI) RetrieveVersion(owner string, datasetid string, versionid string) (dwapi.DatasetSummaryResponse, error) {
+	m.record("RetrieveVersion", []interface{}{owner, datasetid, versionid})
+	if m.RetrieveVersionFunc == nil {
+		var r0 dwapi.DatasetSummaryResponse
+		return r0, fmt.Errorf("dwapimock: DatasetAPI.RetrieveVersion called without RetrieveVersionFunc set")
+	}
+	return m.RetrieveVersionFunc(owner, datasetid, versionid)
+}
+
+// Sync records the call and invokes SyncFunc.
+func (m *DatasetAPI) Sync(owner string, datasetid string) (dwapi.SuccessResponse, error) {
+	m.record("Sync", []interface{}{owner, datasetid})
+	if m.SyncFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: DatasetAPI.Sync called without SyncFunc set")
+	}
+	return m.SyncFunc(owner, datasetid)
+}
+
+// Update records the call and invokes UpdateFunc.
+func (m *DatasetAPI) Update(owner string, id string, body *dwapi.DatasetUpdateRequest) (dwapi.SuccessResponse, error) {
+	m.record("Update", []interface{}{owner, id, body})
+	if m.UpdateFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: DatasetAPI.Update called without UpdateFunc set")
+	}
+	return m.UpdateFunc(owner, id, body)
+}
+
+// UploadFile records the call and invokes UploadFileFunc.
+func (m *DatasetAPI) UploadFile(owner string, id string, filename string, path string, expandArchive bool) (dwapi.SuccessResponse, error) {
+	m.record("UploadFile", []interface{}{owner, id, filename, path, expandArchive})
+	if m.UploadFileFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: DatasetAPI.UploadFile called without UploadFileFunc set")
+	}
+	return m.UploadFileFunc(owner, id, filename, path, expandArchive)
+}
+
+// FileAPI is a mock implementation of dwapi.FileAPI. Set the Func field matching a method to control
+// its behavior; methods whose Func is unset return zero values and an error.
+type FileAPI struct {
+	recorder
+
+	AddFilesFromURLsFunc       func(owner string, id string, body *[]dwapi.FileCreateRequest) (dwapi.SuccessResponse, error)
+	DeleteFunc                 func(owner string, id string, filename string) (dwapi.SuccessResponse, error)
+	DownloadFunc               func(owner string, id string, filename string) (io.ReadCloser, error)
+	DownloadAndSaveFunc        func(owner string, id string, filename string, path string) (dwapi.SuccessResponse, error)
+	DownloadDatasetFunc        func(owner string, id string) (io.ReadCloser, error)
+	DownloadAndSaveDatasetFunc func(owner string, id string, path string) (dwapi.SuccessResponse, error)
+	SyncFunc                   func(owner string, id string) (dwapi.SuccessResponse, error)
+	UploadFunc                 func(owner string, id string, filename string, path string, expandArchive bool) (dwapi.SuccessResponse, error)
+	UploadStreamFunc           func(owner string, id string, filename string, body io.Reader, expandArchive bool) (dwapi.SuccessResponse, error)
+}
+
+var _ dwapi.FileAPI = (*FileAPI)(nil)
+
+// AddFilesFromURLs records the call and invokes AddFilesFromURLsFunc.
+func (m *FileAPI) AddFilesFromURLs(owner string, id string, body *[]dwapi.FileCreateRequest) (dwapi.SuccessResponse, error) {
+	m.record("AddFilesFromURLs", []interface{}{owner, id, body})
+	if m.AddFilesFromURLsFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: FileAPI.AddFilesFromURLs called without AddFilesFromURLsFunc set")
+	}
+	return m.AddFilesFromURLsFunc(owner, id, body)
+}
+
+// Delete records the call and invokes DeleteFunc.
+func (m *FileAPI) Delete(owner string, id string, filename string) (dwapi.SuccessResponse, error) {
+	m.record("Delete", []interface{}{owner, id, filename})
+	if m.DeleteFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: FileAPI.Delete called without DeleteFunc set")
+	}
+	return m.DeleteFunc(owner, id, filename)
+}
+
+// Download records the call and invokes DownloadFunc.
+func (m *FileAPI) Download(owner string, id string, filename string) (io.ReadCloser, error) {
+	m.record("Download", []interface{}{owner, id, filename})
+	if m.DownloadFunc == nil {
+		var r0 io.ReadCloser
+		return r0, fmt.Errorf("dwapimock: FileAPI.Download called without DownloadFunc set")
+	}
+	return m.DownloadFunc(owner, id, filename)
+}
+
+// DownloadAndSave records the call and invokes DownloadAndSaveFunc.
+func (m *FileAPI) DownloadAndSave(owner string, id string, filename string, path string) (dwapi.SuccessResponse, error) {
+	m.record("DownloadAndSave", []interface{}{owner, id, filename, path})
+	if m.DownloadAndSaveFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: FileAPI.DownloadAndSave called without DownloadAndSaveFunc set")
+	}
+	return m.DownloadAndSaveFunc(owner, id, filename, path)
+}
+
+// DownloadDataset records the call and invokes DownloadDatasetFunc.
+func (m *FileAPI) DownloadDataset(owner string, id string) (io.ReadCloser, error) {
+	m.record("DownloadDataset", []interface{}{owner, id})
+	if m.DownloadDatasetFunc == nil {
+		var r0 io.ReadCloser
+		return r0, fmt.Errorf("dwapimock: FileAPI.DownloadDataset called without DownloadDatasetFunc set")
+	}
+	return m.DownloadDatasetFunc(owner, id)
+}
+
+// DownloadAndSaveDataset records the call and invokes DownloadAndSaveDatasetFunc.
+func (m *FileAPI) DownloadAndSaveDataset(owner string, id string, path string) (dwapi.SuccessResponse, error) {
+	m.record("DownloadAndSaveDataset", []interface{}{owner, id, path})
+	if m.DownloadAndSaveDatasetFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: FileAPI.DownloadAndSaveDataset called without DownloadAndSaveDatasetFunc set")
+	}
+	return m.DownloadAndSaveDatasetFunc(owner, id, path)
+}
+
+// Sync records the call and invokes SyncFunc.
+func (m *FileAPI) Sync(owner string, id string) (dwapi.SuccessResponse, error) {
+	m.record("Sync", []interface{}{owner, id})
+	if m.SyncFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: FileAPI.Sync called without SyncFunc set")
+	}
+	return m.SyncFunc(owner, id)
+}
+
+// Upload records the call and invokes UploadFunc.
+func (m *FileAPI) Upload(owner string, id string, filename string, path string, expandArchive bool) (dwapi.SuccessResponse, error) {
+	m.record("Upload", []interface{}{owner, id, filename, path, expandArchive})
+	if m.UploadFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: FileAPI.Upload called without UploadFunc set")
+	}
+	return m.UploadFunc(owner, id, filename, path, expandArchive)
+}
+
+// UploadStream records the call and invokes UploadStreamFunc.
+func (m *FileAPI) UploadStream(owner string, id string, filename string, body io.Reader, expandArchive bool) (dwapi.SuccessResponse, error) {
+	m.record("UploadStream", []interface{}{owner, id, filename, body, expandArchive})
+	if m.UploadStreamFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: FileAPI.UploadStream called without UploadStreamFunc set")
+	}
+	return m.UploadStreamFunc(owner, id, filename, body, expandArchive)
+}
+
+// InsightAPI is a mock implementation of dwapi.InsightAPI. Set the Func field matching a method to control
+// its behavior; methods whose Func is unset return zero values and an error.
+type InsightAPI struct {
+	recorder
+
+	CreateFunc          func(owner string, projectid string, body *dwapi.InsightCreateRequest) (dwapi.InsightCreateResponse, error)
+	DeleteFunc          func(owner string, projectid string, insightid string) (dwapi.SuccessResponse, error)
+	ListFunc            func(owner string, projectid string) ([]dwapi.InsightSummaryResponse, error)
+	ReplaceFunc         func(owner string, projectid string, insightid string, body *dwapi.InsightReplaceRequest) (dwapi.SuccessResponse, error)
+	RetrieveFunc        func(owner string, projectid string, insightid string) (dwapi.InsightSummaryResponse, error)
+	RetrieveVersionFunc func(owner string, projectid string, insightid string, versionid string) (dwapi.InsightSummaryResponse, error)
+	UpdateFunc          func(owner string, projectid string, insightid string, body *dwapi.InsightUpdateRequest) (dwapi.SuccessResponse, error)
+}
+
+var _ dwapi.InsightAPI = (*InsightAPI)(nil)
+
+// Create records the call and invokes CreateFunc.
+func (m *InsightAPI) Create(owner string, projectid string, body *dwapi.InsightCreateRequest) (dwapi.InsightCreateResponse, error) {
+	m.record("Create", []interface{}{owner, projectid, body})
+	if m.CreateFunc == nil {
+		var r0 dwapi.InsightCreateResponse
+		return r0, fmt.Errorf("dwapimock: InsightAPI.Create called without CreateFunc set")
+	}
+	return m.CreateFunc(owner, projectid, body)
+}
+
+// Delete records the call and invokes DeleteFunc.
+func (m *InsightAPI) Delete(owner string, projectid string, insightid string) (dwapi.SuccessResponse, error) {
+	m.record("Delete", []interface{}{owner, projectid, insightid})
+	if m.DeleteFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: InsightAPI.Delete called without DeleteFunc set")
+	}
+	return m.DeleteFunc(owner, projectid, insightid)
+}
+
+// List records the call and invokes ListFunc.
+func (m *InsightAPI) List(owner string, projectid string) ([]dwapi.InsightSummaryResponse, error) {
+	m.record("List", []interface{}{owner, projectid})
+	if m.ListFunc == nil {
+		var r0 []dwapi.InsightSummaryResponse
+		return r0, fmt.Errorf("dwapimock: InsightAPI.List called without ListFunc set")
+	}
+	return m.ListFunc(owner, projectid)
+}
+
+// Replace records the call and invokes ReplaceFunc.
+func (m *InsightAPI) Replace(owner string, projectid string, insightid string, body *dwapi.InsightReplaceRequest) (dwapi.SuccessResponse, error) {
+	m.record("Replace", []interface{}{owner, projectid, insightid, body})
+	if m.ReplaceFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: InsightAPI.Replace called without ReplaceFunc set")
+	}
+	return m.ReplaceFunc(owner, projectid, insightid, body)
+}
+
+// Retrieve records the call and invokes RetrieveFunc.
+func (m *InsightAPI) Retrieve(owner string, projectid string, insightid string) (dwapi.InsightSummaryResponse, error) {
+	m.record("Retrieve", []interface{}{owner, projectid, insightid})
+	if m.RetrieveFunc == nil {
+		var r0 dwapi.InsightSummaryResponse
+		return r0, fmt.Errorf("dwapimock: InsightAPI.Retrieve called without RetrieveFunc set")
+	}
+	return m.RetrieveFunc(owner, projectid, insightid)
+}
+
+// RetrieveVersion records the call and invokes RetrieveVersionFunc.
+func (m *InsightAPI) RetrieveVersion(owner string, projectid string, insightid string, versionid string) (dwapi.InsightSummaryResponse, error) {
+	m.record("RetrieveVersion", []interface{}{owner, projectid, insightid, versionid})
+	if m.RetrieveVersionFunc == nil {
+		var r0 dwapi.InsightSummaryResponse
+		return r0, fmt.Errorf("dwapimock: InsightAPI.RetrieveVersion called without RetrieveVersionFunc set")
+	}
+	return m.RetrieveVersionFunc(owner, projectid, insightid, versionid)
+}
+
+// Update records the call and invokes UpdateFunc.
+func (m *InsightAPI) Update(owner string, projectid string, insightid string, body *dwapi.InsightUpdateRequest) (dwapi.SuccessResponse, error) {
+	m.record("Update", []interface{}{owner, projectid, insightid, body})
+	if m.UpdateFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: InsightAPI.Update called without UpdateFunc set")
+	}
+	return m.UpdateFunc(owner, projectid, insightid, body)
+}
+
+// ProjectAPI is a mock implementation of dwapi.ProjectAPI. Set the Func field matching a method to control
+// its behavior; methods whose Func is unset return zero values and an error.
+type ProjectAPI struct {
+	recorder
+
+	AddFilesFromURLsFunc    func(owner string, projectid string, body *[]dwapi.FileCreateRequest) (dwapi.SuccessResponse, error)
+	ContributingFunc        func() ([]dwapi.ProjectSummaryResponse, error)
+	CreateFunc              func(owner string, body *dwapi.ProjectCreateOrUpdateRequest) (dwapi.ProjectCreateResponse, error)
+	CreateOrReplaceFunc     func(owner string, projectid string, body *dwapi.ProjectCreateOrUpdateRequest) (dwapi.SuccessResponse, error)
+	DeleteFunc              func(owner string, projectid string) (dwapi.SuccessResponse, error)
+	DownloadFileFunc        func(owner string, projectid string, filename string) (io.Reader, error)
+	DownloadAndSaveFileFunc func(owner string, projectid string, filename string, path string) (dwapi.SuccessResponse, error)
+	DownloadFunc            func(owner string, projectid string, filename string) (io.Reader, error)
+	DownloadAndSaveFunc     func(owner string, projectid string, path string) (dwapi.SuccessResponse, error)
+	LikedFunc               func() ([]dwapi.ProjectSummaryResponse, error)
+	LinkDatasetFunc         func(owner string, projectid string, linkedDatasetOwner string, linkedDatasetid string) (dwapi.SuccessResponse, error)
+	ListQueriesFunc         func(owner string, projectid string) ([]dwapi.QuerySummaryResponse, error)
+	OwnedFunc               func() ([]dwapi.ProjectSummaryResponse, error)
+	RetrieveFunc            func(owner string, projectid string) (dwapi.ProjectSummaryResponse, error)
+	RetrieveVersionFunc     func(owner string, projectid string, versionid string) (dwapi.ProjectSummaryResponse, error)
+	SyncFunc                func(owner string, projectid string) (dwapi.SuccessResponse, error)
+	UnlinkDatasetFunc       func(owner string, projectid string, linkedDatasetOwner string, linkedDatasetid string) (dwapi.SuccessResponse, error)
+	UpdateFunc              func(owner string, id string, body *dwapi.ProjectCreateOrUpdateRequest) (dwapi.SuccessResponse, error)
+	UploadFileFunc          func(owner string, id string, filename string, path string, expandArchive bool) (dwapi.SuccessResponse, error)
+}
+
+var _ dwapi.ProjectAPI = (*ProjectAPI)(nil)
+
+// AddFilesFromURLs records the call and invokes AddFilesFromURLsFunc.
+func (m *ProjectAPI) AddFilesFromURLs(owner string, projectid string, body *[]dwapi.FileCreateRequest) (dwapi.SuccessResponse, error) {
+	m.record("AddFilesFromURLs", []interface{}{owner, projectid, body})
+	if m.AddFilesFromURLsFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: ProjectAPI.AddFilesFromURLs called without AddFilesFromURLsFunc set")
+	}
+	return m.AddFilesFromURLsFunc(owner, projectid, body)
+}
+
+// Contributing records the call and invokes ContributingFunc.
+func (m *ProjectAPI) Contributing() ([]dwapi.ProjectSummaryResponse, error) {
+	m.record("Contributing", []interface{}{})
+	if m.ContributingFunc == nil {
+		var r0 []dwapi.ProjectSummaryResponse
+		return r0, fmt.Errorf("dwapimock: ProjectAPI.Contributing called without ContributingFunc set")
+	}
+	return m.ContributingFunc()
+}
+
+// Create records the call and invokes CreateFunc.
+func (m *ProjectAPI) Create(owner string, body *dwapi.ProjectCreateOrUpdateRequest) (dwapi.ProjectCreateResponse, error) {
+	m.record("Create", []interface{}{owner, body})
+	if m.CreateFunc == nil {
+		var r0 dwapi.ProjectCreateResponse
+		return r0, fmt.Errorf("dwapimock: ProjectAPI.Create called without CreateFunc set")
+	}
+	return m.CreateFunc(owner, body)
+}
+
+// CreateOrReplace records the call and invokes CreateOrReplaceFunc.
+func (m *ProjectAPI) CreateOrReplace(owner string, projectid string, body *dwapi.ProjectCreateOrUpdateRequest) (dwapi.SuccessResponse, error) {
+	m.record("CreateOrReplace", []interface{}{owner, projectid, body})
+	if m.CreateOrReplaceFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: ProjectAPI.CreateOrReplace called without CreateOrReplaceFunc set")
+	}
+	return m.CreateOrReplaceFunc(owner, projectid, body)
+}
+
+// Delete records the call and invokes DeleteFunc.
+func (m *ProjectAPI) Delete(owner string, projectid string) (dwapi.SuccessResponse, error) {
+	m.record("Delete", []interface{}{owner, projectid})
+	if m.DeleteFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: ProjectAPI.Delete called without DeleteFunc set")
+	}
+	return m.DeleteFunc(owner, projectid)
+}
+
+// DownloadFile records the call and invokes DownloadFileFunc.
+func (m *ProjectAPI) DownloadFile(owner string, projectid string, filename string) (io.Reader, error) {
+	m.record("DownloadFile", []interface{}{owner, projectid, filename})
+	if m.DownloadFileFunc == nil {
+		var r0 io.Reader
+		return r0, fmt.Errorf("dwapimock: ProjectAPI.DownloadFile called without DownloadFileFunc set")
+	}
+	return m.DownloadFileFunc(owner, projectid, filename)
+}
+
+// DownloadAndSaveFile records the call and invokes DownloadAndSaveFileFunc.
+func (m *ProjectAPI) DownloadAndSaveFile(owner string, projectid string, filename string, path string) (dwapi.SuccessResponse, error) {
+	m.record("DownloadAndSaveFile", []interface{}{owner, projectid, filename, path})
+	if m.DownloadAndSaveFileFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: ProjectAPI.DownloadAndSaveFile called without DownloadAndSaveFileFunc set")
+	}
+	return m.DownloadAndSaveFileFunc(owner, projectid, filename, path)
+}
+
+// Download records the call and invokes DownloadFunc.
+func (m *ProjectAPI) Download(owner string, projectid string, filename string) (io.Reader, error) {
+	m.record("Download", []interface{}{owner, projectid, filename})
+	if m.DownloadFunc == nil {
+		var r0 io.Reader
+		return r0, fmt.Errorf("dwapimock: ProjectAPI.Download called without DownloadFunc set")
+	}
+	return m.DownloadFunc(owner, projectid, filename)
+}
+
+// DownloadAndSave records the call and invokes DownloadAndSaveFunc.
+func (m *ProjectAPI) DownloadAndSave(owner string, projectid string, path string) (dwapi.SuccessResponse, error) {
+	m.record("DownloadAndSave", []interface{}{owner, projectid, path})
+	if m.DownloadAndSaveFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: ProjectAPI.DownloadAndSave called without DownloadAndSaveFunc set")
+	}
+	return m.DownloadAndSaveFunc(owner, projectid, path)
+}
+
+// Liked records the call and invokes LikedFunc.
+func (m *ProjectAPI) Liked() ([]dwapi.ProjectSummaryResponse, error) {
+	m.record("Liked", []interface{}{})
+	if m.LikedFunc == nil {
+		var r0 []dwapi.ProjectSummaryResponse
+		return r0, fmt.Errorf("dwapimock: ProjectAPI.Liked called without LikedFunc set")
+	}
+	return m.LikedFunc()
+}
+
+// LinkDataset records the call and invokes LinkDatasetFunc.
+func (m *ProjectAPI) LinkDataset(owner string, projectid string, linkedDatasetOwner string, linkedDatasetid string) (dwapi.SuccessResponse, error) {
+	m.record("LinkDataset", []interface{}{owner, projectid, linkedDatasetOwner, linkedDatasetid})
+	if m.LinkDatasetFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: ProjectAPI.LinkDataset called without LinkDatasetFunc set")
+	}
+	return m.LinkDatasetFunc(owner, projectid, linkedDatasetOwner, linkedDatasetid)
+}
+
+// ListQueries records the call and invokes ListQueriesFunc.
+func (m *ProjectAPI) ListQueries(owner string, projectid string) ([]dwapi.QuerySummaryResponse, error) {
+	m.record("ListQueries", []interface{}{owner, projectid})
+	if m.ListQueriesFunc == nil {
+		var r0 []dwapi.QuerySummaryResponse
+		return r0, fmt.Errorf("dwapimock: ProjectAPI.ListQueries called without ListQueriesFunc set")
+	}
+	return m.ListQueriesFunc(owner, projectid)
+}
+
+// Owned records the call and invokes OwnedFunc.
+func (m *ProjectAPI) Owned() ([]dwapi.ProjectSummaryResponse, error) {
+	m.record("Owned", []interface{}{})
+	if m.OwnedFunc == nil {
+		var r0 []dwapi.ProjectSummaryResponse
+		return r0, fmt.Errorf("dwapimock: ProjectAPI.Owned called without OwnedFunc set")
+	}
+	return m.OwnedFunc()
+}
+
+// Retrieve records the call and invokes RetrieveFunc.
+func (m *ProjectAPI) Retrieve(owner string, projectid string) (dwapi.ProjectSummaryResponse, error) {
+	m.record("Retrieve", []interface{}{owner, projectid})
+	if m.RetrieveFunc == nil {
+		var r0 dwapi.ProjectSummaryResponse
+		return r0, fmt.Errorf("dwapimock: ProjectAPI.Retrieve called without RetrieveFunc set")
+	}
+	return m.RetrieveFunc(owner, projectid)
+}
+
+// RetrieveVersion records the call and invokes RetrieveVersionFunc.
+func (m *ProjectAPI) RetrieveVersion(owner string, projectid string, versionid string) (dwapi.ProjectSummaryResponse, error) {
+	m.record("RetrieveVersion", []interface{}{owner, projectid, versionid})
+	if m.RetrieveVersionFunc == nil {
+		var r0 dwapi.ProjectSummaryResponse
+		return r0, fmt.Errorf("dwapimock: ProjectAPI.RetrieveVersion called without RetrieveVersionFunc set")
+	}
+	return m.RetrieveVersionFunc(owner, projectid, versionid)
+}
+
+// Sync records the call and invokes SyncFunc.
+func (m *ProjectAPI) Sync(owner string, projectid string) (dwapi.SuccessResponse, error) {
+	m.record("Sync", []interface{}{owner, projectid})
+	if m.SyncFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: ProjectAPI.Sync called without SyncFunc set")
+	}
+	return m.SyncFunc(owner, projectid)
+}
+
+// UnlinkDataset records the call and invokes UnlinkDatasetFunc.
+func (m *ProjectAPI) UnlinkDataset(owner string, projectid string, linkedDatasetOwner string, linkedDatasetid string) (dwapi.SuccessResponse, error) {
+	m.record("UnlinkDataset", []interface{}{owner, projectid, linkedDatasetOwner, linkedDatasetid})
+	if m.UnlinkDatasetFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: ProjectAPI.UnlinkDataset called without UnlinkDatasetFunc set")
+	}
+	return m.UnlinkDatasetFunc(owner, projectid, linkedDatasetOwner, linkedDatasetid)
+}
+
+// Update records the call and invokes UpdateFunc.
+func (m *ProjectAPI) Update(owner string, id string, body *dwapi.ProjectCreateOrUpdateRequest) (dwapi.SuccessResponse, error) {
+	m.record("Update", []interface{}{owner, id, body})
+	if m.UpdateFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: ProjectAPI.Update called without UpdateFunc set")
+	}
+	return m.UpdateFunc(owner, id, body)
+}
+
+// UploadFile records the call and invokes UploadFileFunc.
+func (m *ProjectAPI) UploadFile(owner string, id string, filename string, path string, expandArchive bool) (dwapi.SuccessResponse, error) {
+	m.record("UploadFile", []interface{}{owner, id, filename, path, expandArchive})
+	if m.UploadFileFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: ProjectAPI.UploadFile called without UploadFileFunc set")
+	}
+	return m.UploadFileFunc(owner, id, filename, path, expandArchive)
+}
+
+// QueryAPI is a mock implementation of dwapi.QueryAPI. Set the Func field matching a method to control
+// its behavior; methods whose Func is unset return zero values and an error.
+type QueryAPI struct {
+	recorder
+
+	CreateSavedQueryInDatasetFunc        func(owner string, datasetid string, body *dwapi.QueryCreateRequest) (dwapi.QuerySummaryResponse, error)
+	CreateSavedQueryInProjectFunc        func(owner string, projectid string, body *dwapi.QueryCreateRequest) (dwapi.QuerySummaryResponse, error)
+	DeleteSavedQueryInDatasetFunc        func(owner string, datasetid string, queryid string) (dwapi.SuccessResponse, error)
+	DeleteSavedQueryInProjectFunc        func(owner string, projectid string, queryid string) (dwapi.SuccessResponse, error)
+	DiffVersionsFunc                     func(queryid string, fromVersion string, toVersion string) (string, error)
+	ExecuteBatchFunc                     func(queries map[string]dwapi.BatchQuery, options *dwapi.BatchOptions) map[string]dwapi.BatchResult
+	ExecuteSavedQueryFunc                func(queryid string, acceptType string, body *dwapi.SavedQueryExecutionRequest) (io.ReadCloser, error)
+	ExecuteSavedQueryAndSaveFunc         func(queryid string, acceptType string, path string, body *dwapi.SavedQueryExecutionRequest) (dwapi.SuccessResponse, error)
+	ExecuteSavedQueryIntoFunc            func(queryid string, body *dwapi.SavedQueryExecutionRequest, dest interface{}) error
+	ExecuteSavedQueryRowsFunc            func(queryid string, acceptType string, body *dwapi.SavedQueryExecutionRequest) (*dwapi.Rows, error)
+	ExecuteSavedQueryToFileFunc          func(queryid string, path string, body *dwapi.SavedQueryExecutionRequest) (dwapi.SuccessResponse, error)
+	ExecuteSavedQueryVersionFunc         func(owner string, id string, queryid string, versionid string, acceptType string, body *dwapi.SavedQueryExecutionRequest) (io.ReadCloser, error)
+	ExecuteSPARQLFunc                    func(owner string, id string, acceptType string, body *dwapi.SPARQLQueryRequest) (io.ReadCloser, error)
+	ExecuteSPARQLAndSaveFunc             func(owner string, id string, acceptType string, path string, body *dwapi.SPARQLQueryRequest) (dwapi.SuccessResponse, error)
+	ExecuteSPARQLIntoFunc                func(owner string, id string, body *dwapi.SPARQLQueryRequest, dest interface{}) error
+	ExecuteSPARQLRowsFunc                func(owner string, id string, acceptType string, body *dwapi.SPARQLQueryRequest) (*dwapi.Rows, error)
+	ExecuteSPARQLToFileFunc              func(owner string, id string, path string, body *dwapi.SPARQLQueryRequest) (dwapi.SuccessResponse, error)
+	ExecuteSQLFunc                       func(owner string, id string, acceptType string, body *dwapi.SQLQueryRequest) (io.ReadCloser, error)
+	ExecuteSQLAcrossFunc                 func(datasets []dwapi.DatasetOrProjectIdentifier, acceptType string, body *dwapi.SQLQueryRequest, options *dwapi.BatchOptions) (*dwapi.FanOutRows, error)
+	ExecuteSQLAndSaveFunc                func(owner string, id string, acceptType string, path string, body *dwapi.SQLQueryRequest) (dwapi.SuccessResponse, error)
+	ExecuteSQLChunkedFunc                func(owner string, id string, body *dwapi.SQLQueryRequest, options *dwapi.ChunkOptions) (*dwapi.ChunkedRows, error)
+	ExecuteSQLIntoFunc                   func(owner string, id string, body *dwapi.SQLQueryRequest, dest interface{}) error
+	ExecuteSQLRowsFunc                   func(owner string, id string, acceptType string, body *dwapi.SQLQueryRequest) (*dwapi.Rows, error)
+	ExecuteSQLToFileFunc                 func(owner string, id string, path string, body *dwapi.SQLQueryRequest) (dwapi.SuccessResponse, error)
+	ListQueriesAssociatedWithDatasetFunc func(owner string, datasetid string) ([]dwapi.QuerySummaryResponse, error)
+	ListQueriesAssociatedWithProjectFunc func(owner string, projectid string) ([]dwapi.QuerySummaryResponse, error)
+	ListVersionsFunc                     func(queryid string) ([]dwapi.QuerySummaryResponse, error)
+	RetrieveFunc                         func(queryid string) (dwapi.QuerySummaryResponse, error)
+	RetrieveVersionFunc                  func(queryid string, versionid string) (dwapi.QuerySummaryResponse, error)
+	RollbackSavedQueryInDatasetFunc      func(owner string, datasetid string, queryid string, versionid string) (dwapi.QuerySummaryResponse, error)
+	RollbackSavedQueryInProjectFunc      func(owner string, projectid string, queryid string, versionid string) (dwapi.QuerySummaryResponse, error)
+	UpdateSavedQueryInDatasetFunc        func(owner string, datasetid string, queryid string, body *dwapi.QueryUpdateRequest) (dwapi.QuerySummaryResponse, error)
+	UpdateSavedQueryInProjectFunc        func(owner string, projectid string, queryid string, body *dwapi.QueryUpdateRequest) (dwapi.QuerySummaryResponse, error)
+}
+
+var _ dwapi.QueryAPI = (*QueryAPI)(nil)
+
+// CreateSavedQueryInDataset records the call and invokes CreateSavedQueryInDatasetFunc.
+func (m *QueryAPI) CreateSavedQueryInDataset(owner string, datasetid string, body *dwapi.QueryCreateRequest) (dwapi.QuerySummaryResponse, error) {
+	m.record("CreateSavedQueryInDataset", []interface{}{owner, datasetid, body})
+	if m.CreateSavedQueryInDatasetFunc == nil {
+		var r0 dwapi.QuerySummaryResponse
+		return r0, fmt.Errorf("dwapimock: QueryAPI.CreateSavedQueryInDataset called without CreateSavedQueryInDatasetFunc set")
+	}
+	return m.CreateSavedQueryInDatasetFunc(owner, datasetid, body)
+}
+
+// CreateSavedQueryInProject records the call and invokes CreateSavedQueryInProjectFunc.
+func (m *QueryAPI) CreateSavedQueryInProject(owner string, projectid string, body *dwapi.QueryCreateRequest) (dwapi.QuerySummaryResponse, error) {
+	m.record("CreateSavedQueryInProject", []interface{}{owner, projectid, body})
+	if m.CreateSavedQueryInProjectFunc == nil {
+		var r0 dwapi.QuerySummaryResponse
+		return r0, fmt.Errorf("dwapimock: QueryAPI.CreateSavedQueryInProject called without CreateSavedQueryInProjectFunc set")
+	}
+	return m.CreateSavedQueryInProjectFunc(owner, projectid, body)
+}
+
+// DeleteSavedQueryInDataset records the call and invokes DeleteSavedQueryInDatasetFunc.
+func (m *QueryAPI) DeleteSavedQueryInDataset(owner string, datasetid string, queryid string) (dwapi.SuccessResponse, error) {
+	m.record("DeleteSavedQueryInDataset", []interface{}{owner, datasetid, queryid})
+	if m.DeleteSavedQueryInDatasetFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: QueryAPI.DeleteSavedQueryInDataset called without DeleteSavedQueryInDatasetFunc set")
+	}
+	return m.DeleteSavedQueryInDatasetFunc(owner, datasetid, queryid)
+}
+
+// DeleteSavedQueryInProject records the call and invokes DeleteSavedQueryInProjectFunc.
+func (m *QueryAPI) DeleteSavedQueryInProject(owner string, projectid string, queryid string) (dwapi.SuccessResponse, error) {
+	m.record("DeleteSavedQueryInProject", []interface{}{owner, projectid, queryid})
+	if m.DeleteSavedQueryInProjectFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: QueryAPI.DeleteSavedQueryInProject called without DeleteSavedQueryInProjectFunc set")
+	}
+	return m.DeleteSavedQueryInProjectFunc(owner, projectid, queryid)
+}
+
+// DiffVersions records the call and invokes DiffVersionsFunc.
+func (m *QueryAPI) DiffVersions(queryid string, fromVersion string, toVersion string) (string, error) {
+	m.record("DiffVersions", []interface{}{queryid, fromVersion, toVersion})
+	if m.DiffVersionsFunc == nil {
+		var r0 string
+		return r0, fmt.Errorf("dwapimock: QueryAPI.DiffVersions called without DiffVersionsFunc set")
+	}
+	return m.DiffVersionsFunc(queryid, fromVersion, toVersion)
+}
+
+// ExecuteBatch records the call and invokes ExecuteBatchFunc.
+func (m *QueryAPI) ExecuteBatch(queries map[string]dwapi.BatchQuery, options *dwapi.BatchOptions) map[string]dwapi.BatchResult {
+	m.record("ExecuteBatch", []interface{}{queries, options})
+	if m.ExecuteBatchFunc == nil {
+		var r0 map[string]dwapi.BatchResult
+		return r0
+	}
+	return m.ExecuteBatchFunc(queries, options)
+}
+
+// ExecuteSavedQuery records the call and invokes ExecuteSavedQueryFunc.
+func (m *QueryAPI) ExecuteSavedQuery(queryid string, acceptType string, body *dwapi.SavedQueryExecutionRequest) (io.ReadCloser, error) {
+	m.record("ExecuteSavedQuery", []interface{}{queryid, acceptType, body})
+	if m.ExecuteSavedQueryFunc == nil {
+		var r0 io.ReadCloser
+		return r0, fmt.Errorf("dwapimock: QueryAPI.ExecuteSavedQuery called without ExecuteSavedQueryFunc set")
+	}
+	return m.ExecuteSavedQueryFunc(queryid, acceptType, body)
+}
+
+// ExecuteSavedQueryAndSave records the call and invokes ExecuteSavedQueryAndSaveFunc.
+func (m *QueryAPI) ExecuteSavedQueryAndSave(queryid string, acceptType string, path string, body *dwapi.SavedQueryExecutionRequest) (dwapi.SuccessResponse, error) {
+	m.record("ExecuteSavedQueryAndSave", []interface{}{queryid, acceptType, path, body})
+	if m.ExecuteSavedQueryAndSaveFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: QueryAPI.ExecuteSavedQueryAndSave called without ExecuteSavedQueryAndSaveFunc set")
+	}
+	return m.ExecuteSavedQueryAndSaveFunc(queryid, acceptType, path, body)
+}
+
+// ExecuteSavedQueryInto records the call and invokes ExecuteSavedQueryIntoFunc.
+func (m *QueryAPI) ExecuteSavedQueryInto(queryid string, body *dwapi.SavedQueryExecutionRequest, dest interface{}) error {
+	m.record("ExecuteSavedQueryInto", []interface{}{queryid, body, dest})
+	if m.ExecuteSavedQueryIntoFunc == nil {
+		return fmt.Errorf("dwapimock: QueryAPI.ExecuteSavedQueryInto called without ExecuteSavedQueryIntoFunc set")
+	}
+	return m.ExecuteSavedQueryIntoFunc(queryid, body, dest)
+}
+
+// ExecuteSavedQueryRows records the call and invokes ExecuteSavedQueryRowsFunc.
+func (m *QueryAPI) ExecuteSavedQueryRows(queryid string, acceptType string, body *dwapi.SavedQueryExecutionRequest) (*dwapi.Rows, error) {
+	m.record("ExecuteSavedQueryRows", []interface{}{queryid, acceptType, body})
+	if m.ExecuteSavedQueryRowsFunc == nil {
+		var r0 *dwapi.Rows
+		return r0, fmt.Errorf("dwapimock: QueryAPI.ExecuteSavedQueryRows called without ExecuteSavedQueryRowsFunc set")
+	}
+	return m.ExecuteSavedQueryRowsFunc(queryid, acceptType, body)
+}
+
+// ExecuteSavedQueryToFile records the call and invokes ExecuteSavedQueryToFileFunc.
+func (m *QueryAPI) ExecuteSavedQueryToFile(queryid string, path string, body *dwapi.SavedQueryExecutionRequest) (dwapi.SuccessResponse, error) {
+	m.record("ExecuteSavedQueryToFile", []interface{}{queryid, path, body})
+	if m.ExecuteSavedQueryToFileFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: QueryAPI.ExecuteSavedQueryToFile called without ExecuteSavedQueryToFileFunc set")
+	}
+	return m.ExecuteSavedQueryToFileFunc(queryid, path, body)
+}
+
+// ExecuteSavedQueryVersion records the call and invokes ExecuteSavedQueryVersionFunc.
+func (m *QueryAPI) ExecuteSavedQueryVersion(owner string, id string, queryid string, versionid string, acceptType string, body *dwapi.SavedQueryExecutionRequest) (io.ReadCloser, error) {
+	m.record("ExecuteSavedQueryVersion", []interface{}{owner, id, queryid, versionid, acceptType, body})
+	if m.ExecuteSavedQueryVersionFunc == nil {
+		var r0 io.ReadCloser
+		return r0, fmt.Errorf("dwapimock: QueryAPI.ExecuteSavedQueryVersion called without ExecuteSavedQueryVersionFunc set")
+	}
+	return m.ExecuteSavedQueryVersionFunc(owner, id, queryid, versionid, acceptType, body)
+}
+
+// ExecuteSPARQL records the call and invokes ExecuteSPARQLFunc.
+func (m *QueryAPI) ExecuteSPARQL(owner string, id string, acceptType string, body *dwapi.SPARQLQueryRequest) (io.ReadCloser, error) {
+	m.record("ExecuteSPARQL", []interface{}{owner, id, acceptType, body})
+	if m.ExecuteSPARQLFunc == nil {
+		var r0 io.ReadCloser
+		return r0, fmt.Errorf("dwapimock: QueryAPI.ExecuteSPARQL called without ExecuteSPARQLFunc set")
+	}
+	return m.ExecuteSPARQLFunc(owner, id, acceptType, body)
+}
+
+// ExecuteSPARQLAndSave records the call and invokes ExecuteSPARQLAndSaveFunc.
+func (m *QueryAPI) ExecuteSPARQLAndSave(owner string, id string, acceptType string, path string, body *dwapi.SPARQLQueryRequest) (dwapi.SuccessResponse, error) {
+	m.record("ExecuteSPARQLAndSave", []interface{}{owner, id, acceptType, path, body})
+	if m.ExecuteSPARQLAndSaveFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: QueryAPI.ExecuteSPARQLAndSave called without ExecuteSPARQLAndSaveFunc set")
+	}
+	return m.ExecuteSPARQLAndSaveFunc(owner, id, acceptType, path, body)
+}
+
+// ExecuteSPARQLInto records the call and invokes ExecuteSPARQLIntoFunc.
+func (m *QueryAPI) ExecuteSPARQLInto(owner string, id string, body *dwapi.SPARQLQueryRequest, dest interface{}) error {
+	m.record("ExecuteSPARQLInto", []interface{}{owner, id, body, dest})
+	if m.ExecuteSPARQLIntoFunc == nil {
+		return fmt.Errorf("dwapimock: QueryAPI.ExecuteSPARQLInto called without ExecuteSPARQLIntoFunc set")
+	}
+	return m.ExecuteSPARQLIntoFunc(owner, id, body, dest)
+}
+
+// ExecuteSPARQLRows records the call and invokes ExecuteSPARQLRowsFunc.
+func (m *QueryAPI) ExecuteSPARQLRows(owner string, id string, acceptType string, body *dwapi.SPARQLQueryRequest) (*dwapi.Rows, error) {
+	m.record("ExecuteSPARQLRows", []interface{}{owner, id, acceptType, body})
+	if m.ExecuteSPARQLRowsFunc == nil {
+		var r0 *dwapi.Rows
+		return r0, fmt.Errorf("dwapimock: QueryAPI.ExecuteSPARQLRows called without ExecuteSPARQLRowsFunc set")
+	}
+	return m.ExecuteSPARQLRowsFunc(owner, id, acceptType, body)
+}
+
+// ExecuteSPARQLToFile records the call and invokes ExecuteSPARQLToFileFunc.
+func (m *QueryAPI) ExecuteSPARQLToFile(owner string, id string, path string, body *dwapi.SPARQLQueryRequest) (dwapi.SuccessResponse, error) {
+	m.record("ExecuteSPARQLToFile", []interface{}{owner, id, path, body})
+	if m.ExecuteSPARQLToFileFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: QueryAPI.ExecuteSPARQLToFile called without ExecuteSPARQLToFileFunc set")
+	}
+	return m.ExecuteSPARQLToFileFunc(owner, id, path, body)
+}
+
+// ExecuteSQL records the call and invokes ExecuteSQLFunc.
+func (m *QueryAPI) ExecuteSQL(owner string, id string, acceptType string, body *dwapi.SQLQueryRequest) (io.ReadCloser, error) {
+	m.record("ExecuteSQL", []interface{}{owner, id, acceptType, body})
+	if m.ExecuteSQLFunc == nil {
+		var r0 io.ReadCloser
+		return r0, fmt.Errorf("dwapimock: QueryAPI.ExecuteSQL called without ExecuteSQLFunc set")
+	}
+	return m.ExecuteSQLFunc(owner, id, acceptType, body)
+}
+
+// ExecuteSQLAcross records the call and invokes ExecuteSQLAcrossFunc.
+func (m *QueryAPI) ExecuteSQLAcross(datasets []dwapi.DatasetOrProjectIdentifier, acceptType string, body *dwapi.SQLQueryRequest, options *dwapi.BatchOptions) (*dwapi.FanOutRows, error) {
+	m.record("ExecuteSQLAcross", []interface{}{datasets, acceptType, body, options})
+	if m.ExecuteSQLAcrossFunc == nil {
+		var r0 *dwapi.FanOutRows
+		return r0, fmt.Errorf("dwapimock: QueryAPI.ExecuteSQLAcross called without ExecuteSQLAcrossFunc set")
+	}
+	return m.ExecuteSQLAcrossFunc(datasets, acceptType, body, options)
+}
+
+// ExecuteSQLAndSave records the call and invokes ExecuteSQLAndSaveFunc.
+func (m *QueryAPI) ExecuteSQLAndSave(owner string, id string, acceptType string, path string, body *dwapi.SQLQueryRequest) (dwapi.SuccessResponse, error) {
+	m.record("ExecuteSQLAndSave", []interface{}{owner, id, acceptType, path, body})
+	if m.ExecuteSQLAndSaveFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: QueryAPI.ExecuteSQLAndSave called without ExecuteSQLAndSaveFunc set")
+	}
+	return m.ExecuteSQLAndSaveFunc(owner, id, acceptType, path, body)
+}
+
+// ExecuteSQLChunked records the call and invokes ExecuteSQLChunkedFunc.
+func (m *QueryAPI) ExecuteSQLChunked(owner string, id string, body *dwapi.SQLQueryRequest, options *dwapi.ChunkOptions) (*dwapi.ChunkedRows, error) {
+	m.record("ExecuteSQLChunked", []interface{}{owner, id, body, options})
+	if m.ExecuteSQLChunkedFunc == nil {
+		var r0 *dwapi.ChunkedRows
+		return r0, fmt.Errorf("dwapimock: QueryAPI.ExecuteSQLChunked called without ExecuteSQLChunkedFunc set")
+	}
+	return m.ExecuteSQLChunkedFunc(owner, id, body, options)
+}
+
+// ExecuteSQLInto records the call and invokes ExecuteSQLIntoFunc.
+func (m *QueryAPI) ExecuteSQLInto(owner string, id string, body *dwapi.SQLQueryRequest, dest interface{}) error {
+	m.record("ExecuteSQLInto", []interface{}{owner, id, body, dest})
+	if m.ExecuteSQLIntoFunc == nil {
+		return fmt.Errorf("dwapimock: QueryAPI.ExecuteSQLInto called without ExecuteSQLIntoFunc set")
+	}
+	return m.ExecuteSQLIntoFunc(owner, id, body, dest)
+}
+
+// ExecuteSQLRows records the call and invokes ExecuteSQLRowsFunc.
+func (m *QueryAPI) ExecuteSQLRows(owner string, id string, acceptType string, body *dwapi.SQLQueryRequest) (*dwapi.Rows, error) {
+	m.record("ExecuteSQLRows", []interface{}{owner, id, acceptType, body})
+	if m.ExecuteSQLRowsFunc == nil {
+		var r0 *dwapi.Rows
+		return r0, fmt.Errorf("dwapimock: QueryAPI.ExecuteSQLRows called without ExecuteSQLRowsFunc set")
+	}
+	return m.ExecuteSQLRowsFunc(owner, id, acceptType, body)
+}
+
+// ExecuteSQLToFile records the call and invokes ExecuteSQLToFileFunc.
+func (m *QueryAPI) ExecuteSQLToFile(owner string, id string, path string, body *dwapi.SQLQueryRequest) (dwapi.SuccessResponse, error) {
+	m.record("ExecuteSQLToFile", []interface{}{owner, id, path, body})
+	if m.ExecuteSQLToFileFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: QueryAPI.ExecuteSQLToFile called without ExecuteSQLToFileFunc set")
+	}
+	return m.ExecuteSQLToFileFunc(owner, id, path, body)
+}
+
+// ListQueriesAssociatedWithDataset records the call and invokes ListQueriesAssociatedWithDatasetFunc.
+func (m *QueryAPI) ListQueriesAssociatedWithDataset(owner string, datasetid string) ([]dwapi.QuerySummaryResponse, error) {
+	m.record("ListQueriesAssociatedWithDataset", []interface{}{owner, datasetid})
+	if m.ListQueriesAssociatedWithDatasetFunc == nil {
+		var r0 []dwapi.QuerySummaryResponse
+		return r0, fmt.Errorf("dwapimock: QueryAPI.ListQueriesAssociatedWithDataset called without ListQueriesAssociatedWithDatasetFunc set")
+	}
+	return m.ListQueriesAssociatedWithDatasetFunc(owner, datasetid)
+}
+
+// ListQueriesAssociatedWithProject records the call and invokes ListQueriesAssociatedWithProjectFunc.
+func (m *QueryAPI) ListQueriesAssociatedWithProject(owner string, projectid string) ([]dwapi.QuerySummaryResponse, error) {
+	m.record("ListQueriesAssociatedWithProject", []interface{}{owner, projectid})
+	if m.ListQueriesAssociatedWithProjectFunc == nil {
+		var r0 []dwapi.QuerySummaryResponse
+		return r0, fmt.Errorf("dwapimock: QueryAPI.ListQueriesAssociatedWithProject called without ListQueriesAssociatedWithProjectFunc set")
+	}
+	return m.ListQueriesAssociatedWithProjectFunc(owner, projectid)
+}
+
+// ListVersions records the call and invokes ListVersionsFunc.
+func (m *QueryAPI) ListVersions(queryid string) ([]dwapi.QuerySummaryResponse, error) {
+	m.record("ListVersions", []interface{}{queryid})
+	if m.ListVersionsFunc == nil {
+		var r0 []dwapi.QuerySummaryResponse
+		return r0, fmt.Errorf("dwapimock: QueryAPI.ListVersions called without ListVersionsFunc set")
+	}
+	return m.ListVersionsFunc(queryid)
+}
+
+// Retrieve records the call and invokes RetrieveFunc.
+func (m *QueryAPI) Retrieve(queryid string) (dwapi.QuerySummaryResponse, error) {
+	m.record("Retrieve", []interface{}{queryid})
+	if m.RetrieveFunc == nil {
+		var r0 dwapi.QuerySummaryResponse
+		return r0, fmt.Errorf("dwapimock: QueryAPI.Retrieve called without RetrieveFunc set")
+	}
+	return m.RetrieveFunc(queryid)
+}
+
+// RetrieveVersion records the call and invokes RetrieveVersionFunc.
+func (m *QueryAPI) RetrieveVersion(queryid string, versionid string) (dwapi.QuerySummaryResponse, error) {
+	m.record("RetrieveVersion", []interface{}{queryid, versionid})
+	if m.RetrieveVersionFunc == nil {
+		var r0 dwapi.QuerySummaryResponse
+		return r0, fmt.Errorf("dwapimock: QueryAPI.RetrieveVersion called without RetrieveVersionFunc set")
+	}
+	return m.RetrieveVersionFunc(queryid, versionid)
+}
+
+// RollbackSavedQueryInDataset records the call and invokes RollbackSavedQueryInDatasetFunc.
+func (m *QueryAPI) RollbackSavedQueryInDataset(owner string, datasetid string, queryid string, versionid string) (dwapi.QuerySummaryResponse, error) {
+	m.record("RollbackSavedQueryInDataset", []interface{}{owner, datasetid, queryid, versionid})
+	if m.RollbackSavedQueryInDatasetFunc == nil {
+		var r0 dwapi.QuerySummaryResponse
+		return r0, fmt.Errorf("dwapimock: QueryAPI.RollbackSavedQueryInDataset called without RollbackSavedQueryInDatasetFunc set")
+	}
+	return m.RollbackSavedQueryInDatasetFunc(owner, datasetid, queryid, versionid)
+}
+
+// RollbackSavedQueryInProject records the call and invokes RollbackSavedQueryInProjectFunc.
+func (m *QueryAPI) RollbackSavedQueryInProject(owner string, projectid string, queryid string, versionid string) (dwapi.QuerySummaryResponse, error) {
+	m.record("RollbackSavedQueryInProject", []interface{}{owner, projectid, queryid, versionid})
+	if m.RollbackSavedQueryInProjectFunc == nil {
+		var r0 dwapi.QuerySummaryResponse
+		return r0, fmt.Errorf("dwapimock: QueryAPI.RollbackSavedQueryInProject called without RollbackSavedQueryInProjectFunc set")
+	}
+	return m.RollbackSavedQueryInProjectFunc(owner, projectid, queryid, versionid)
+}
+
+// UpdateSavedQueryInDataset records the call and invokes UpdateSavedQueryInDatasetFunc.
+func (m *QueryAPI) UpdateSavedQueryInDataset(owner string, datasetid string, queryid string, body *dwapi.QueryUpdateRequest) (dwapi.QuerySummaryResponse, error) {
+	m.record("UpdateSavedQueryInDataset", []interface{}{owner, datasetid, queryid, body})
+	if m.UpdateSavedQueryInDatasetFunc == nil {
+		var r0 dwapi.QuerySummaryResponse
+		return r0, fmt.Errorf("dwapimock: QueryAPI.UpdateSavedQueryInDataset called without UpdateSavedQueryInDatasetFunc set")
+	}
+	return m.UpdateSavedQueryInDatasetFunc(owner, datasetid, queryid, body)
+}
+
+// UpdateSavedQueryInProject records the call and invokes UpdateSavedQueryInProjectFunc.
+func (m *QueryAPI) UpdateSavedQueryInProject(owner string, projectid string, queryid string, body *dwapi.QueryUpdateRequest) (dwapi.QuerySummaryResponse, error) {
+	m.record("UpdateSavedQueryInProject", []interface{}{owner, projectid, queryid, body})
+	if m.UpdateSavedQueryInProjectFunc == nil {
+		var r0 dwapi.QuerySummaryResponse
+		return r0, fmt.Errorf("dwapimock: QueryAPI.UpdateSavedQueryInProject called without UpdateSavedQueryInProjectFunc set")
+	}
+	return m.UpdateSavedQueryInProjectFunc(owner, projectid, queryid, body)
+}
+
+// StreamAPI is a mock implementation of dwapi.StreamAPI. Set the Func field matching a method to control
+// its behavior; methods whose Func is unset return zero values and an error.
+type StreamAPI struct {
+	recorder
+
+	AppendFunc            func(owner string, id string, streamid string, body io.Reader) (dwapi.SuccessResponse, error)
+	DeleteFunc            func(owner string, id string, streamid string) (dwapi.SuccessResponse, error)
+	RetrieveSchemaFunc    func(owner string, id string, streamid string) (dwapi.StreamSchema, error)
+	SetOrUpdateSchemaFunc func(owner string, id string, streamid string, body *dwapi.StreamSchemaUpdateRequest) (dwapi.SuccessResponse, error)
+}
+
+var _ dwapi.StreamAPI = (*StreamAPI)(nil)
+
+// Append records the call and invokes AppendFunc.
+func (m *StreamAPI) Append(owner string, id string, streamid string, body io.Reader) (dwapi.SuccessResponse, error) {
+	m.record("Append", []interface{}{owner, id, streamid, body})
+	if m.AppendFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: StreamAPI.Append called without AppendFunc set")
+	}
+	return m.AppendFunc(owner, id, streamid, body)
+}
+
+// Delete records the call and invokes DeleteFunc.
+func (m *StreamAPI) Delete(owner string, id string, streamid string) (dwapi.SuccessResponse, error) {
+	m.record("Delete", []interface{}{owner, id, streamid})
+	if m.DeleteFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: StreamAPI.Delete called without DeleteFunc set")
+	}
+	return m.DeleteFunc(owner, id, streamid)
+}
+
+// RetrieveSchema records the call and invokes RetrieveSchemaFunc.
+func (m *StreamAPI) RetrieveSchema(owner string, id string, streamid string) (dwapi.StreamSchema, error) {
+	m.record("RetrieveSchema", []interface{}{owner, id, streamid})
+	if m.RetrieveSchemaFunc == nil {
+		var r0 dwapi.StreamSchema
+		return r0, fmt.Errorf("dwapimock: StreamAPI.RetrieveSchema called without RetrieveSchemaFunc set")
+	}
+	return m.RetrieveSchemaFunc(owner, id, streamid)
+}
+
+// SetOrUpdateSchema records the call and invokes SetOrUpdateSchemaFunc.
+func (m *StreamAPI) SetOrUpdateSchema(owner string, id string, streamid string, body *dwapi.StreamSchemaUpdateRequest) (dwapi.SuccessResponse, error) {
+	m.record("SetOrUpdateSchema", []interface{}{owner, id, streamid, body})
+	if m.SetOrUpdateSchemaFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: StreamAPI.SetOrUpdateSchema called without SetOrUpdateSchemaFunc set")
+	}
+	return m.SetOrUpdateSchemaFunc(owner, id, streamid, body)
+}
+
+// UserAPI is a mock implementation of dwapi.UserAPI. Set the Func field matching a method to control
+// its behavior; methods whose Func is unset return zero values and an error.
+type UserAPI struct {
+	recorder
+
+	DatasetsContributingFunc func() ([]dwapi.DatasetSummaryResponse, error)
+	DatasetsLikedFunc        func() ([]dwapi.DatasetSummaryResponse, error)
+	DatasetsOwnedFunc        func() ([]dwapi.DatasetSummaryResponse, error)
+	ProjectsContributingFunc func() ([]dwapi.ProjectSummaryResponse, error)
+	ProjectsLikedFunc        func() ([]dwapi.ProjectSummaryResponse, error)
+	ProjectsOwnedFunc        func() ([]dwapi.ProjectSummaryResponse, error)
+	RetrieveFunc             func(agentid string) (dwapi.UserInfoResponse, error)
+	SelfFunc                 func() (dwapi.UserInfoResponse, error)
+}
+
+var _ dwapi.UserAPI = (*UserAPI)(nil)
+
+// DatasetsContributing records the call and invokes DatasetsContributingFunc.
+func (m *UserAPI) DatasetsContributing() ([]dwapi.DatasetSummaryResponse, error) {
+	m.record("DatasetsContributing", []interface{}{})
+	if m.DatasetsContributingFunc == nil {
+		var r0 []dwapi.DatasetSummaryResponse
+		return r0, fmt.Errorf("dwapimock: UserAPI.DatasetsContributing called without DatasetsContributingFunc set")
+	}
+	return m.DatasetsContributingFunc()
+}
+
+// DatasetsLiked records the call and invokes DatasetsLikedFunc.
+func (m *UserAPI) DatasetsLiked() ([]dwapi.DatasetSummaryResponse, error) {
+	m.record("DatasetsLiked", []interface{}{})
+	if m.DatasetsLikedFunc == nil {
+		var r0 []dwapi.DatasetSummaryResponse
+		return r0, fmt.Errorf("dwapimock: UserAPI.DatasetsLiked called without DatasetsLikedFunc set")
+	}
+	return m.DatasetsLikedFunc()
+}
+
+// DatasetsOwned records the call and invokes DatasetsOwnedFunc.
+func (m *UserAPI) DatasetsOwned() ([]dwapi.DatasetSummaryResponse, error) {
+	m.record("DatasetsOwned", []interface{}{})
+	if m.DatasetsOwnedFunc == nil {
+		var r0 []dwapi.DatasetSummaryResponse
+		return r0, fmt.Errorf("dwapimock: UserAPI.DatasetsOwned called without DatasetsOwnedFunc set")
+	}
+	return m.DatasetsOwnedFunc()
+}
+
+// ProjectsContributing records the call and invokes ProjectsContributingFunc.
+func (m *UserAPI) ProjectsContributing() ([]dwapi.ProjectSummaryResponse, error) {
+	m.record("ProjectsContributing", []interface{}{})
+	if m.ProjectsContributingFunc == nil {
+		var r0 []dwapi.ProjectSummaryResponse
+		return r0, fmt.Errorf("dwapimock: UserAPI.ProjectsContributing called without ProjectsContributingFunc set")
+	}
+	return m.ProjectsContributingFunc()
+}
+
+// ProjectsLiked records the call and invokes ProjectsLikedFunc.
+func (m *UserAPI) ProjectsLiked() ([]dwapi.ProjectSummaryResponse, error) {
+	m.record("ProjectsLiked", []interface{}{})
+	if m.ProjectsLikedFunc == nil {
+		var r0 []dwapi.ProjectSummaryResponse
+		return r0, fmt.Errorf("dwapimock: UserAPI.ProjectsLiked called without ProjectsLikedFunc set")
+	}
+	return m.ProjectsLikedFunc()
+}
+
+// ProjectsOwned records the call and invokes ProjectsOwnedFunc.
+func (m *UserAPI) ProjectsOwned() ([]dwapi.ProjectSummaryResponse, error) {
+	m.record("ProjectsOwned", []interface{}{})
+	if m.ProjectsOwnedFunc == nil {
+		var r0 []dwapi.ProjectSummaryResponse
+		return r0, fmt.Errorf("dwapimock: UserAPI.ProjectsOwned called without ProjectsOwnedFunc set")
+	}
+	return m.ProjectsOwnedFunc()
+}
+
+// Retrieve records the call and invokes RetrieveFunc.
+func (m *UserAPI) Retrieve(agentid string) (dwapi.UserInfoResponse, error) {
+	m.record("Retrieve", []interface{}{agentid})
+	if m.RetrieveFunc == nil {
+		var r0 dwapi.UserInfoResponse
+		return r0, fmt.Errorf("dwapimock: UserAPI.Retrieve called without RetrieveFunc set")
+	}
+	return m.RetrieveFunc(agentid)
+}
+
+// Self records the call and invokes SelfFunc.
+func (m *UserAPI) Self() (dwapi.UserInfoResponse, error) {
+	m.record("Self", []interface{}{})
+	if m.SelfFunc == nil {
+		var r0 dwapi.UserInfoResponse
+		return r0, fmt.Errorf("dwapimock: UserAPI.Self called without SelfFunc set")
+	}
+	return m.SelfFunc()
+}
+
+// WebhookAPI is a mock implementation of dwapi.WebhookAPI. Set the Func field matching a method to control
+// its behavior; methods whose Func is unset return zero values and an error.
+type WebhookAPI struct {
+	recorder
+
+	ListFunc                        func() ([]dwapi.Subscription, error)
+	RetrieveAccountSubscriptionFunc func(user string) (dwapi.Subscription, error)
+	RetrieveDatasetSubscriptionFunc func(owner string, datasetid string) (dwapi.Subscription, error)
+	RetrieveProjectSubscriptionFunc func(owner string, projectid string) (dwapi.Subscription, error)
+	SubscribeToAccountFunc          func(user string, body *dwapi.SubscriptionCreateRequest) (dwapi.SuccessResponse, error)
+	SubscribeToDatasetFunc          func(owner string, datasetid string, body *dwapi.SubscriptionCreateRequest) (dwapi.SuccessResponse, error)
+	SubscribeToProjectFunc          func(owner string, projectid string, body *dwapi.SubscriptionCreateRequest) (dwapi.SuccessResponse, error)
+	UnsubscribeFromAccountFunc      func(user string) (dwapi.SuccessResponse, error)
+	UnsubscribeFromDatasetFunc      func(owner string, datasetid string) (dwapi.SuccessResponse, error)
+	UnsubscribeFromProjectFunc      func(owner string, projectid string) (dwapi.SuccessResponse, error)
+}
+
+var _ dwapi.WebhookAPI = (*WebhookAPI)(nil)
+
+// List records the call and invokes ListFunc.
+func (m *WebhookAPI) List() ([]dwapi.Subscription, error) {
+	m.record("List", []interface{}{})
+	if m.ListFunc == nil {
+		var r0 []dwapi.Subscription
+		return r0, fmt.Errorf("dwapimock: WebhookAPI.List called without ListFunc set")
+	}
+	return m.ListFunc()
+}
+
+// RetrieveAccountSubscription records the call and invokes RetrieveAccountSubscriptionFunc.
+func (m *WebhookAPI) RetrieveAccountSubscription(user string) (dwapi.Subscription, error) {
+	m.record("RetrieveAccountSubscription", []interface{}{user})
+	if m.RetrieveAccountSubscriptionFunc == nil {
+		var r0 dwapi.Subscription
+		return r0, fmt.Errorf("dwapimock: WebhookAPI.RetrieveAccountSubscription called without RetrieveAccountSubscriptionFunc set")
+	}
+	return m.RetrieveAccountSubscriptionFunc(user)
+}
+
+// RetrieveDatasetSubscription records the call and invokes RetrieveDatasetSubscriptionFunc.
+func (m *WebhookAPI) RetrieveDatasetSubscription(owner string, datasetid string) (dwapi.Subscription, error) {
+	m.record("RetrieveDatasetSubscription", []interface{}{owner, datasetid})
+	if m.RetrieveDatasetSubscriptionFunc == nil {
+		var r0 dwapi.Subscription
+		return r0, fmt.Errorf("dwapimock: WebhookAPI.RetrieveDatasetSubscription called without RetrieveDatasetSubscriptionFunc set")
+	}
+	return m.RetrieveDatasetSubscriptionFunc(owner, datasetid)
+}
+
+// RetrieveProjectSubscription records the call and invokes RetrieveProjectSubscriptionFunc.
+func (m *WebhookAPI) RetrieveProjectSubscription(owner string, projectid string) (dwapi.Subscription, error) {
+	m.record("RetrieveProjectSubscription", []interface{}{owner, projectid})
+	if m.RetrieveProjectSubscriptionFunc == nil {
+		var r0 dwapi.Subscription
+		return r0, fmt.Errorf("dwapimock: WebhookAPI.RetrieveProjectSubscription called without RetrieveProjectSubscriptionFunc set")
+	}
+	return m.RetrieveProjectSubscriptionFunc(owner, projectid)
+}
+
+// SubscribeToAccount records the call and invokes SubscribeToAccountFunc.
+func (m *WebhookAPI) SubscribeToAccount(user string, body *dwapi.SubscriptionCreateRequest) (dwapi.SuccessResponse, error) {
+	m.record("SubscribeToAccount", []interface{}{user, body})
+	if m.SubscribeToAccountFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: WebhookAPI.SubscribeToAccount called without SubscribeToAccountFunc set")
+	}
+	return m.SubscribeToAccountFunc(user, body)
+}
+
+// SubscribeToDataset records the call and invokes SubscribeToDatasetFunc.
+func (m *WebhookAPI) SubscribeToDataset(owner string, datasetid string, body *dwapi.SubscriptionCreateRequest) (dwapi.SuccessResponse, error) {
+	m.record("SubscribeToDataset", []interface{}{owner, datasetid, body})
+	if m.SubscribeToDatasetFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: WebhookAPI.SubscribeToDataset called without SubscribeToDatasetFunc set")
+	}
+	return m.SubscribeToDatasetFunc(owner, datasetid, body)
+}
+
+// SubscribeToProject records the call and invokes SubscribeToProjectFunc.
+func (m *WebhookAPI) SubscribeToProject(owner string, projectid string, body *dwapi.SubscriptionCreateRequest) (dwapi.SuccessResponse, error) {
+	m.record("SubscribeToProject", []interface{}{owner, projectid, body})
+	if m.SubscribeToProjectFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: WebhookAPI.SubscribeToProject called without SubscribeToProjectFunc set")
+	}
+	return m.SubscribeToProjectFunc(owner, projectid, body)
+}
+
+// UnsubscribeFromAccount records the call and invokes UnsubscribeFromAccountFunc.
+func (m *WebhookAPI) UnsubscribeFromAccount(user string) (dwapi.SuccessResponse, error) {
+	m.record("UnsubscribeFromAccount", []interface{}{user})
+	if m.UnsubscribeFromAccountFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: WebhookAPI.UnsubscribeFromAccount called without UnsubscribeFromAccountFunc set")
+	}
+	return m.UnsubscribeFromAccountFunc(user)
+}
+
+// UnsubscribeFromDataset records the call and invokes UnsubscribeFromDatasetFunc.
+func (m *WebhookAPI) UnsubscribeFromDataset(owner string, datasetid string) (dwapi.SuccessResponse, error) {
+	m.record("UnsubscribeFromDataset", []interface{}{owner, datasetid})
+	if m.UnsubscribeFromDatasetFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: WebhookAPI.UnsubscribeFromDataset called without UnsubscribeFromDatasetFunc set")
+	}
+	return m.UnsubscribeFromDatasetFunc(owner, datasetid)
+}
+
+// UnsubscribeFromProject records the call and invokes UnsubscribeFromProjectFunc.
+func (m *WebhookAPI) UnsubscribeFromProject(owner string, projectid string) (dwapi.SuccessResponse, error) {
+	m.record("UnsubscribeFromProject", []interface{}{owner, projectid})
+	if m.UnsubscribeFromProjectFunc == nil {
+		var r0 dwapi.SuccessResponse
+		return r0, fmt.Errorf("dwapimock: WebhookAPI.UnsubscribeFromProject called without UnsubscribeFromProjectFunc set")
+	}
+	return m.UnsubscribeFromProjectFunc(owner, projectid)
+}
@@ -0,0 +1,449 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+/*
+Package cassette records API requests and responses into cassette files, and replays them in tests.
+
+Record a cassette once against the real API:
+
+	rec, err := cassette.New("testdata/datasets.json", cassette.ModeRecord)
+	dw := dwapi.NewClient(os.Getenv("DW_AUTH_TOKEN"))
+	dw.HTTPClient = rec.Client()
+	// ... make some calls ...
+	err = rec.Save()
+
+and replay it afterwards without network access:
+
+	rec, err := cassette.New("testdata/datasets.json", cassette.ModeReplay)
+	dw := dwapi.NewClient("any-token")
+	dw.HTTPClient = rec.Client()
+
+Bearer tokens, cookies and credential fields in JSON bodies (passwords, tokens, request headers, ...)
+are scrubbed, however deeply nested, before a cassette is written. When replaying, a request that doesn't match any recorded interaction
+fails with an *UnmatchedError.
+*/
+package cassette
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Mode selects whether a Recorder records or replays.
+type Mode int
+
+const (
+	// ModeReplay serves responses from the cassette and never touches the network.
+	ModeReplay Mode = iota
+	// ModeRecord sends requests to the API and records them, replacing the cassette on Save.
+	ModeRecord
+)
+
+// Match selects the parts of a request that must be equal for it to match a recorded one.
+type Match int
+
+const (
+	MatchMethod Match = 1 << iota
+	// MatchPath compares the URL path and query string; the scheme and host are ignored.
+	MatchPath
+	// MatchBody compares request bodies after scrubbing. JSON bodies are compared semantically.
+	MatchBody
+
+	MatchAll = MatchMethod | MatchPath | MatchBody
+)
+
+// Redacted replaces scrubbed values in cassettes.
+const Redacted = "REDACTED"
+
+// DefaultScrubFields lists the JSON fields whose values are scrubbed from request and response bodies,
+// matched case-insensitively at any depth. All strings within an object or array field are scrubbed.
+var DefaultScrubFields = []string{"password", "credentials", "token", "accessToken", "refreshToken", "apiToken",
+	"authorization", "requestHeaders"}
+
+// Cassette is the file format of recorded interactions.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a recorded request and the response it got.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded request.
+type Request struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    Body        `json:"body,omitempty"`
+}
+
+// Response is a recorded response.
+type Response struct {
+	StatusCode int         `json:"status"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       Body        `json:"body,omitempty"`
+}
+
+// Body is a request or response body. It is written as a string when it is valid UTF-8, and as a
+// base64 encoded object otherwise.
+type Body []byte
+
+type binaryBody struct {
+	Base64 string `json:"base64"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (b Body) MarshalJSON() ([]byte, error) {
+	if utf8.Valid(b) {
+		return json.Marshal(string(b))
+	}
+	return json.Marshal(binaryBody{Base64: base64.StdEncoding.EncodeToString(b)})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (b *Body) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*b = Body(s)
+		return nil
+	}
+	var bin binaryBody
+	if err := json.Unmarshal(data, &bin); err != nil {
+		return err
+	}
+	d, err := base64.StdEncoding.DecodeString(bin.Base64)
+	*b = d
+	return err
+}
+
+// UnmatchedError is returned when replaying a request that matches no recorded interaction.
+type UnmatchedError struct {
+	Method string
+	URL    string
+	Body   string
+}
+
+func (e *UnmatchedError) Error() string {
+	msg := fmt.Sprintf("cassette: no recorded interaction matches %s %s", e.Method, e.URL)
+	if e.Body != "" {
+		msg += " with body " + e.Body
+	}
+	return msg
+}
+
+// Recorder is an http.RoundTripper that records or replays interactions.
+type Recorder struct {
+	// Match selects how requests are matched when replaying. It defaults to MatchAll.
+	Match Match
+	// ScrubFields lists the JSON fields scrubbed from bodies. It defaults to DefaultScrubFields.
+	ScrubFields []string
+	// Transport sends requests when recording. It defaults to http.DefaultTransport.
+	Transport http.RoundTripper
+
+	path string
+	mode Mode
+
+	mu        sync.Mutex
+	cassette  Cassette
+	used      []bool
+	unmatched []error
+}
+
+// New returns a Recorder for the cassette at path. In ModeReplay the cassette is loaded and must
+// exist.
+func New(path string, mode Mode) (*Recorder, error) {
+	r := &Recorder{
+		Match:       MatchAll,
+		ScrubFields: DefaultScrubFields,
+		path:        path,
+		mode:        mode,
+	}
+	if mode == ModeReplay {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal(b, &r.cassette); err != nil {
+			return nil, fmt.Errorf("cassette %s: %s", path, err)
+		}
+		r.used = make([]bool, len(r.cassette.Interactions))
+	}
+	return r, nil
+}
+
+// Client returns an http.Client using the recorder as its transport, ready to be assigned to
+// `dwapi.Client.HTTPClient`.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = ioutil.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	if r.mode == ModeRecord {
+		return r.record(req, body)
+	}
+	return r.replay(req, body)
+}
+
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	t := r.Transport
+	if t == nil {
+		t = http.DefaultTransport
+	}
+	resp, err := t.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	reqHeaders := cloneHeader(req.Header)
+	if reqHeaders.Get("Authorization") != "" {
+		reqHeaders.Set("Authorization", "Bearer "+Redacted)
+	}
+	reqHeaders.Del("Cookie")
+	respHeaders := cloneHeader(resp.Header)
+	respHeaders.Del("Set-Cookie")
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request: Request{
+			Method:  req.Method,
+			URL:     requestPath(req.URL),
+			Headers: reqHeaders,
+			Body:    r.scrub(body),
+		},
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Headers:    respHeaders,
+			Body:       r.scrub(respBody),
+		},
+	})
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	scrubbed := r.scrub(body)
+	match := -1
+	for i, in := range r.cassette.Interactions {
+		if r.matches(req, scrubbed, in.Request) {
+			match = i
+			if !r.used[i] {
+				break
+			}
+		}
+	}
+	if match < 0 {
+		err := &UnmatchedError{Method: req.Method, URL: requestPath(req.URL), Body: string(scrubbed)}
+		r.unmatched = append(r.unmatched, err)
+		return nil, err
+	}
+
+	// Requests are matched to the first interaction that hasn't been replayed yet, so the same call
+	// made repeatedly replays the recorded sequence, and then keeps replaying the last one.
+	r.used[match] = true
+	recorded := r.cassette.Interactions[match].Response
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        cloneHeader(recorded.Headers),
+		Body:          ioutil.NopCloser(bytes.NewReader(recorded.Body)),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}, nil
+}
+
+func (r *Recorder) matches(req *http.Request, body []byte, recorded Request) bool {
+	m := r.Match
+	if m == 0 {
+		m = MatchAll
+	}
+	if m&MatchMethod != 0 && req.Method != recorded.Method {
+		return false
+	}
+	if m&MatchPath != 0 && requestPath(req.URL) != recorded.URL {
+		return false
+	}
+	if m&MatchBody != 0 && !sameBody(body, recorded.Body) {
+		return false
+	}
+	return true
+}
+
+// requestPath returns the path and normalized query string of a URL.
+func requestPath(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.EscapedPath()
+	}
+	return u.EscapedPath() + "?" + u.Query().Encode()
+}
+
+func sameBody(a, b []byte) bool {
+	if bytes.Equal(bytes.TrimSpace(a), bytes.TrimSpace(b)) {
+		return true
+	}
+	var av, bv interface{}
+	if json.Unmarshal(a, &av) != nil || json.Unmarshal(b, &bv) != nil {
+		return false
+	}
+	an, _ := json.Marshal(av)
+	bn, _ := json.Marshal(bv)
+	return bytes.Equal(an, bn)
+}
+
+// scrub redacts credential fields in a JSON body. Other bodies are returned as is.
+func (r *Recorder) scrub(body []byte) []byte {
+	var v interface{}
+	if len(body) == 0 || json.Unmarshal(body, &v) != nil {
+		return body
+	}
+	if !r.scrubValue(v) {
+		return body
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+	return b
+}
+
+func (r *Recorder) scrubValue(v interface{}) (changed bool) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, child := range t {
+			if r.isScrubbed(k) {
+				var c bool
+				t[k], c = redact(child)
+				changed = c || changed
+				continue
+			}
+			changed = r.scrubValue(child) || changed
+		}
+	case []interface{}:
+		for _, child := range t {
+			changed = r.scrubValue(child) || changed
+		}
+	}
+	return
+}
+
+// redact replaces every string within v.
+func redact(v interface{}) (redacted interface{}, changed bool) {
+	switch t := v.(type) {
+	case string:
+		return Redacted, t != Redacted
+	case map[string]interface{}:
+		for k, child := range t {
+			var c bool
+			t[k], c = redact(child)
+			changed = c || changed
+		}
+	case []interface{}:
+		for i, child := range t {
+			var c bool
+			t[i], c = redact(child)
+			changed = c || changed
+		}
+	}
+	return v, changed
+}
+
+func (r *Recorder) isScrubbed(field string) bool {
+	fields := r.ScrubFields
+	if fields == nil {
+		fields = DefaultScrubFields
+	}
+	for _, f := range fields {
+		if strings.EqualFold(f, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// Save writes the recorded interactions to the cassette file. It does nothing in ModeReplay.
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+	r.mu.Lock()
+	b, err := json.MarshalIndent(r.cassette, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(r.path, append(b, '\n'), 0644)
+}
+
+// Unmatched returns the errors for every replayed request that matched no interaction.
+func (r *Recorder) Unmatched() []error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]error(nil), r.unmatched...)
+}
+
+// Unused returns the recorded interactions that haven't been replayed. It is empty in ModeRecord.
+func (r *Recorder) Unused() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var unused []Interaction
+	for i, in := range r.cassette.Interactions {
+		if i < len(r.used) && !r.used[i] {
+			unused = append(unused, in)
+		}
+	}
+	return unused
+}
+
+func cloneHeader(h http.Header) http.Header {
+	c := make(http.Header, len(h))
+	for k, v := range h {
+		c[k] = append([]string(nil), v...)
+	}
+	return c
+}
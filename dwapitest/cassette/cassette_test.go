@@ -0,0 +1,148 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package cassette
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/datadotworld/dwapi-go/dwapi"
+	"github.com/datadotworld/dwapi-go/dwapitest"
+	"github.com/stretchr/testify/assert"
+)
+
+func recordCassette(t *testing.T, path string) {
+	srv := dwapitest.NewServer()
+	defer srv.Close()
+	srv.AddDataset(dwapi.DatasetSummaryResponse{Owner: "tim-notes", ID: "my-awesome-dataset", Title: "Recorded"})
+
+	rec, err := New(path, ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dw := dwapi.NewClient("secret.token")
+	dw.BaseURL = srv.URL
+	dw.HTTPClient = rec.Client()
+
+	_, err = dw.Dataset.Retrieve("tim-notes", "my-awesome-dataset")
+	assert.NoError(t, err)
+	_, err = dw.File.AddFilesFromURLs("tim-notes", "my-awesome-dataset", &[]dwapi.FileCreateRequest{{
+		Name: "remote.csv",
+		Source: dwapi.FileSourceCreateOrUpdateRequest{
+			URL:            "https://example.com/remote.csv",
+			Credentials:    dwapi.WebCredentials{User: "me", Password: "hunter2"},
+			RequestHeaders: map[string]string{"Authorization": "Basic bWU6aHVudGVyMw=="},
+		},
+	}})
+	assert.NoError(t, err)
+	_, err = dw.Dataset.Retrieve("tim-notes", "missing-dataset")
+	assert.EqualError(t, err, "404 Not Found")
+
+	assert.NoError(t, rec.Save())
+}
+
+func TestRecorder(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cassette")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cassette.json")
+
+	recordCassette(t, path)
+
+	b, _ := ioutil.ReadFile(path)
+	assert.NotContains(t, string(b), "secret.token")
+	assert.NotContains(t, string(b), "hunter2")
+	assert.NotContains(t, string(b), "bWU6aHVudGVyMw==")
+	assert.Contains(t, string(b), "Bearer REDACTED")
+
+	rec, err := New(path, ModeReplay)
+	if !assert.NoError(t, err) {
+		return
+	}
+	dw := dwapi.NewClient("another.token")
+	dw.BaseURL = "http://replayed.invalid"
+	dw.HTTPClient = rec.Client()
+
+	got, err := dw.Dataset.Retrieve("tim-notes", "my-awesome-dataset")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "Recorded", got.Title)
+	}
+	_, err = dw.Dataset.Retrieve("tim-notes", "missing-dataset")
+	assert.EqualError(t, err, "404 Not Found")
+	assert.Len(t, rec.Unused(), 1)
+
+	// Bodies are compared after scrubbing, so the credentials don't need to match the recording.
+	_, err = dw.File.AddFilesFromURLs("tim-notes", "my-awesome-dataset", &[]dwapi.FileCreateRequest{{
+		Name: "remote.csv",
+		Source: dwapi.FileSourceCreateOrUpdateRequest{
+			URL:            "https://example.com/remote.csv",
+			Credentials:    dwapi.WebCredentials{User: "me", Password: "another-password"},
+			RequestHeaders: map[string]string{"Authorization": "Basic another"},
+		},
+	}})
+	assert.NoError(t, err)
+	assert.Empty(t, rec.Unused())
+
+	_, err = dw.Dataset.Delete("tim-notes", "my-awesome-dataset")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "cassette: no recorded interaction matches DELETE /datasets/tim-notes/my-awesome-dataset")
+	}
+	assert.Len(t, rec.Unmatched(), 1)
+}
+
+func TestRecorder_Match(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cassette")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cassette.json")
+
+	recordCassette(t, path)
+
+	rec, _ := New(path, ModeReplay)
+	rec.Match = MatchMethod | MatchPath
+	dw := dwapi.NewClient("another.token")
+	dw.BaseURL = "http://replayed.invalid"
+	dw.HTTPClient = rec.Client()
+
+	_, err = dw.File.AddFilesFromURLs("tim-notes", "my-awesome-dataset", &[]dwapi.FileCreateRequest{{
+		Name:   "another.csv",
+		Source: dwapi.FileSourceCreateOrUpdateRequest{URL: "https://example.com/another.csv"},
+	}})
+	assert.NoError(t, err)
+}
+
+func TestBody_JSON(t *testing.T) {
+	in := Interaction{Response: Response{StatusCode: 200, Body: Body{0xff, 0x00, 0x01}}}
+	b, err := json.Marshal(in)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Contains(t, string(b), `"body":{"base64":"/wAB"}`)
+
+	var out Interaction
+	if assert.NoError(t, json.Unmarshal(b, &out)) {
+		assert.Equal(t, in, out)
+	}
+}
@@ -0,0 +1,230 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package contract
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/datadotworld/dwapi-go/dwapi"
+)
+
+const (
+	scratchFile    = "contract.csv"
+	scratchContent = "id,name\n1,contract\n"
+	scratchStream  = "contract-stream"
+
+	// SQLQuery and SPARQLQuery are run against the scratch dataset.
+	SQLQuery    = "SELECT * FROM contract"
+	SPARQLQuery = "SELECT * WHERE { ?s ?p ?o } LIMIT 1"
+)
+
+func (r *runner) run() {
+	r.check("User.Self", nil, func() (interface{}, error) {
+		u, err := r.dw.User.Self()
+		if err == nil && r.owner == "" {
+			r.owner = u.ID
+		}
+		return u, err
+	})
+	if r.owner == "" {
+		return
+	}
+	r.report.Owner = r.owner
+	r.check("User.Retrieve", nil, func() (interface{}, error) {
+		return r.dw.User.Retrieve(r.owner)
+	})
+
+	r.check("Dataset.CreateOrReplace", nil, func() (interface{}, error) {
+		return r.dw.Dataset.CreateOrReplace(r.owner, r.id, &dwapi.DatasetReplaceRequest{
+			Title:       r.id,
+			Description: "Scratch dataset of the dwapi contract checks",
+			Visibility:  "PRIVATE",
+		})
+	})
+	defer r.check("Dataset.Delete", []string{"Dataset.CreateOrReplace"}, func() (interface{}, error) {
+		return r.dw.Dataset.Delete(r.owner, r.id)
+	})
+	r.datasetChecks()
+
+	r.check("Project.CreateOrReplace", nil, func() (interface{}, error) {
+		return r.dw.Project.CreateOrReplace(r.owner, r.projectid, &dwapi.ProjectCreateOrUpdateRequest{
+			Title:      r.projectid,
+			Objective:  "Scratch project of the dwapi contract checks",
+			Visibility: "PRIVATE",
+		})
+	})
+	defer r.check("Project.Delete", []string{"Project.CreateOrReplace"}, func() (interface{}, error) {
+		return r.dw.Project.Delete(r.owner, r.projectid)
+	})
+	r.projectChecks()
+
+	r.skip("DOI.Associate", "associating a DOI needs a registered identifier")
+}
+
+func (r *runner) datasetChecks() {
+	dataset := []string{"Dataset.CreateOrReplace"}
+	file := []string{"File.UploadStream"}
+	query := []string{"Query.CreateSavedQueryInDataset"}
+	stream := []string{"Stream.SetOrUpdateSchema"}
+	webhook := []string{"Webhook.SubscribeToDataset"}
+
+	r.check("Dataset.Retrieve", dataset, func() (interface{}, error) {
+		return r.dw.Dataset.Retrieve(r.owner, r.id)
+	})
+	r.check("Dataset.Update", dataset, func() (interface{}, error) {
+		return r.dw.Dataset.Update(r.owner, r.id, &dwapi.DatasetUpdateRequest{Summary: "Updated by the contract checks"})
+	})
+	r.check("User.DatasetsOwned", dataset, func() (interface{}, error) {
+		return r.dw.User.DatasetsOwned()
+	})
+
+	r.check("File.UploadStream", dataset, func() (interface{}, error) {
+		return r.dw.File.UploadStream(r.owner, r.id, scratchFile, strings.NewReader(scratchContent), false)
+	})
+	r.check("File.Download", file, func() (interface{}, error) {
+		content, err := read(r.dw.File.Download(r.owner, r.id, scratchFile))
+		if err == nil && content != scratchContent {
+			err = fmt.Errorf("downloaded %q, uploaded %q", content, scratchContent)
+		}
+		return nil, err
+	})
+	r.check("File.DownloadDataset", file, func() (interface{}, error) {
+		_, err := read(r.dw.File.DownloadDataset(r.owner, r.id))
+		return nil, err
+	})
+
+	r.check("Query.ExecuteSQL", file, func() (interface{}, error) {
+		_, err := read(r.dw.Query.ExecuteSQL(r.owner, r.id, "text/csv", &dwapi.SQLQueryRequest{Query: SQLQuery}))
+		return nil, err
+	})
+	r.check("Query.ExecuteSPARQL", file, func() (interface{}, error) {
+		_, err := read(r.dw.Query.ExecuteSPARQL(r.owner, r.id, "application/sparql-results+json",
+			&dwapi.SPARQLQueryRequest{Query: SPARQLQuery}))
+		return nil, err
+	})
+	r.check("Query.CreateSavedQueryInDataset", file, func() (interface{}, error) {
+		q, err := r.dw.Query.CreateSavedQueryInDataset(r.owner, r.id, &dwapi.QueryCreateRequest{
+			Name: "contract", Content: SQLQuery, Language: "SQL",
+		})
+		r.queryid = q.ID
+		return q, err
+	})
+	r.check("Query.Retrieve", query, func() (interface{}, error) {
+		return r.dw.Query.Retrieve(r.queryid)
+	})
+	r.check("Query.UpdateSavedQueryInDataset", query, func() (interface{}, error) {
+		return r.dw.Query.UpdateSavedQueryInDataset(r.owner, r.id, r.queryid, &dwapi.QueryUpdateRequest{
+			Name: "contract (updated)", Content: SQLQuery,
+		})
+	})
+	r.check("Query.ListQueriesAssociatedWithDataset", query, func() (interface{}, error) {
+		return r.dw.Query.ListQueriesAssociatedWithDataset(r.owner, r.id)
+	})
+	r.check("Query.ExecuteSavedQuery", query, func() (interface{}, error) {
+		_, err := read(r.dw.Query.ExecuteSavedQuery(r.queryid, "text/csv", nil))
+		return nil, err
+	})
+	r.check("Query.ExecuteSavedQueryVersion", query, func() (interface{}, error) {
+		q, err := r.dw.Query.Retrieve(r.queryid)
+		if err != nil {
+			return nil, err
+		}
+		_, err = read(r.dw.Query.ExecuteSavedQueryVersion(r.owner, r.id, r.queryid, q.Version, "text/csv", nil))
+		return nil, err
+	})
+	r.check("Query.DeleteSavedQueryInDataset", query, func() (interface{}, error) {
+		return r.dw.Query.DeleteSavedQueryInDataset(r.owner, r.id, r.queryid)
+	})
+
+	r.check("Stream.SetOrUpdateSchema", dataset, func() (interface{}, error) {
+		return r.dw.Stream.SetOrUpdateSchema(r.owner, r.id, scratchStream, &dwapi.StreamSchemaUpdateRequest{
+			PrimaryKeyFields: []string{"id"},
+			UpdateMethod:     "TRUNCATED",
+		})
+	})
+	r.check("Stream.Append", stream, func() (interface{}, error) {
+		return r.dw.Stream.Append(r.owner, r.id, scratchStream, strings.NewReader(`{"id": 1, "name": "contract"}`))
+	})
+	r.check("Stream.RetrieveSchema", stream, func() (interface{}, error) {
+		return r.dw.Stream.RetrieveSchema(r.owner, r.id, scratchStream)
+	})
+	r.check("Stream.Delete", stream, func() (interface{}, error) {
+		return r.dw.Stream.Delete(r.owner, r.id, scratchStream)
+	})
+
+	r.check("Webhook.SubscribeToDataset", dataset, func() (interface{}, error) {
+		return r.dw.Webhook.SubscribeToDataset(r.owner, r.id, &dwapi.SubscriptionCreateRequest{Events: []string{"ALL"}})
+	})
+	r.check("Webhook.RetrieveDatasetSubscription", webhook, func() (interface{}, error) {
+		return r.dw.Webhook.RetrieveDatasetSubscription(r.owner, r.id)
+	})
+	r.check("Webhook.List", webhook, func() (interface{}, error) {
+		return r.dw.Webhook.List()
+	})
+	r.check("Webhook.UnsubscribeFromDataset", webhook, func() (interface{}, error) {
+		return r.dw.Webhook.UnsubscribeFromDataset(r.owner, r.id)
+	})
+}
+
+func (r *runner) projectChecks() {
+	project := []string{"Project.CreateOrReplace"}
+	linked := []string{"Project.LinkDataset"}
+	insight := []string{"Insight.Create"}
+
+	r.check("Project.Retrieve", project, func() (interface{}, error) {
+		return r.dw.Project.Retrieve(r.owner, r.projectid)
+	})
+	r.check("Project.Update", project, func() (interface{}, error) {
+		return r.dw.Project.Update(r.owner, r.projectid, &dwapi.ProjectCreateOrUpdateRequest{
+			Title:      r.projectid,
+			Summary:    "Updated by the contract checks",
+			Visibility: "PRIVATE",
+		})
+	})
+	r.check("User.ProjectsOwned", project, func() (interface{}, error) {
+		return r.dw.User.ProjectsOwned()
+	})
+	r.check("Project.LinkDataset", append(project, "Dataset.CreateOrReplace"), func() (interface{}, error) {
+		return r.dw.Project.LinkDataset(r.owner, r.projectid, r.owner, r.id)
+	})
+	r.check("Project.UnlinkDataset", linked, func() (interface{}, error) {
+		return r.dw.Project.UnlinkDataset(r.owner, r.projectid, r.owner, r.id)
+	})
+
+	r.check("Insight.Create", project, func() (interface{}, error) {
+		i, err := r.dw.Insight.Create(r.owner, r.projectid, &dwapi.InsightCreateRequest{
+			Title: "contract",
+			Body:  dwapi.InsightBody{MarkdownBody: "Created by the contract checks"},
+		})
+		r.insightid = lastSegment(i.URI)
+		return i, err
+	})
+	r.check("Insight.Retrieve", insight, func() (interface{}, error) {
+		return r.dw.Insight.Retrieve(r.owner, r.projectid, r.insightid)
+	})
+	r.check("Insight.List", insight, func() (interface{}, error) {
+		return r.dw.Insight.List(r.owner, r.projectid)
+	})
+	r.check("Insight.Update", insight, func() (interface{}, error) {
+		return r.dw.Insight.Update(r.owner, r.projectid, r.insightid, &dwapi.InsightUpdateRequest{Title: "contract (updated)"})
+	})
+	r.check("Insight.Delete", insight, func() (interface{}, error) {
+		return r.dw.Insight.Delete(r.owner, r.projectid, r.insightid)
+	})
+}
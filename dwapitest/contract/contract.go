@@ -0,0 +1,257 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+/*
+Package contract checks that a data.world API host still behaves the way the dwapi package expects.
+
+Run exercises every service against the host, in a scratch dataset and project that it creates and
+deletes again, and reports for each call whether it succeeded and whether the responses matched the
+models: fields the models don't know about, and fields the models expect that were missing.
+
+	report := contract.Run(contract.Config{BaseURL: "https://api.data.world/v0", Token: token})
+	report.WriteJSON(os.Stdout)
+
+The dw-contract command runs it from the command line.
+*/
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/datadotworld/dwapi-go/dwapi"
+)
+
+// Status is the outcome of a check.
+type Status string
+
+const (
+	// Pass means the call succeeded and its response matched the model.
+	Pass Status = "pass"
+	// Warn means the call succeeded, but its response had unknown or missing fields.
+	Warn Status = "warn"
+	// Fail means the call failed.
+	Fail Status = "fail"
+	// Skip means the call wasn't made, because a call it depends on failed or it can't be made safely.
+	Skip Status = "skip"
+)
+
+// Config describes the host to check.
+type Config struct {
+	// BaseURL of the API, including the /v0 prefix.
+	BaseURL string
+	Token   string
+	// Owner of the scratch dataset and project; the authenticated user if empty.
+	Owner string
+	// ScratchID is the id of the scratch dataset, and with a "-project" suffix of the scratch project; a
+	// new one based on the time if empty.
+	ScratchID string
+	// HTTPClient sends the requests; a client with a one minute timeout if nil.
+	HTTPClient *http.Client
+}
+
+// Report is the machine-readable result of a contract run.
+type Report struct {
+	BaseURL   string         `json:"baseUrl"`
+	Owner     string         `json:"owner"`
+	ScratchID string         `json:"scratchId"`
+	Started   time.Time      `json:"started"`
+	Finished  time.Time      `json:"finished"`
+	Summary   map[Status]int `json:"summary"`
+	Results   []Result       `json:"results"`
+}
+
+// Result is the outcome of one call.
+type Result struct {
+	Check         string   `json:"check"`
+	Status        Status   `json:"status"`
+	Error         string   `json:"error,omitempty"`
+	Requests      []string `json:"requests,omitempty"`
+	UnknownFields []string `json:"unknownFields,omitempty"`
+	MissingFields []string `json:"missingFields,omitempty"`
+	DurationMs    int64    `json:"durationMs"`
+}
+
+// Compatible reports whether every call that was made succeeded.
+func (r *Report) Compatible() bool {
+	return r.Summary[Fail] == 0
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	return e.Encode(r)
+}
+
+// Run checks the host. Calls that depend on a failed call are skipped, and the scratch dataset and
+// project are deleted even if checks fail.
+func Run(cfg Config) *Report {
+	r := newRunner(cfg)
+	r.run()
+	r.report.Finished = time.Now().UTC()
+	return r.report
+}
+
+type runner struct {
+	dw      *dwapi.Client
+	capture *capture
+	report  *Report
+	passed  map[string]bool
+	unknown map[string]bool
+
+	owner, id, projectid, queryid, insightid string
+}
+
+func newRunner(cfg Config) *runner {
+	base := cfg.HTTPClient
+	if base == nil {
+		base = &http.Client{Timeout: 60 * time.Second}
+	}
+	c := &capture{transport: base.Transport}
+	if c.transport == nil {
+		c.transport = http.DefaultTransport
+	}
+
+	r := &runner{
+		capture: c,
+		passed:  make(map[string]bool),
+		unknown: make(map[string]bool),
+		owner:   cfg.Owner,
+		id:      cfg.ScratchID,
+	}
+	if r.id == "" {
+		r.id = "dwapi-contract-" + time.Now().UTC().Format("20060102-150405")
+	}
+	r.projectid = r.id + "-project"
+
+	r.dw = dwapi.NewClient(cfg.Token)
+	r.dw.BaseURL = strings.TrimSuffix(cfg.BaseURL, "/")
+	r.dw.HTTPClient = &http.Client{Transport: c, Timeout: base.Timeout}
+	r.dw.StrictMode = true
+	r.dw.OnUnknownFields = func(method, endpoint string, fields []string) {
+		for _, f := range fields {
+			r.unknown[f] = true
+		}
+	}
+
+	r.report = &Report{
+		BaseURL:   r.dw.BaseURL,
+		ScratchID: r.id,
+		Started:   time.Now().UTC(),
+		Summary:   map[Status]int{Pass: 0, Warn: 0, Fail: 0, Skip: 0},
+	}
+	return r
+}
+
+// check makes a call unless one of the checks it needs didn't pass, and records the result. The call
+// returns the response to validate against its model, or nil.
+func (r *runner) check(name string, needs []string, call func() (interface{}, error)) {
+	res := Result{Check: name, Status: Pass}
+	defer func() {
+		r.passed[name] = res.Status == Pass || res.Status == Warn
+		r.report.Summary[res.Status]++
+		r.report.Results = append(r.report.Results, res)
+	}()
+
+	for _, n := range needs {
+		if !r.passed[n] {
+			res.Status, res.Error = Skip, fmt.Sprintf("needs %s", n)
+			return
+		}
+	}
+
+	r.capture.reset()
+	r.unknown = make(map[string]bool)
+	start := time.Now()
+	response, err := call()
+	res.DurationMs = int64(time.Since(start) / time.Millisecond)
+	res.Requests = r.capture.requests
+	if err != nil {
+		res.Status, res.Error = Fail, err.Error()
+		return
+	}
+
+	for f := range r.unknown {
+		res.UnknownFields = append(res.UnknownFields, f)
+	}
+	sort.Strings(res.UnknownFields)
+	if response != nil {
+		res.MissingFields = missingFields(r.capture.bodies, reflect.TypeOf(response))
+	}
+	if len(res.UnknownFields) > 0 || len(res.MissingFields) > 0 {
+		res.Status = Warn
+	}
+}
+
+// skip records a check that isn't made.
+func (r *runner) skip(name, reason string) {
+	r.report.Summary[Skip]++
+	r.report.Results = append(r.report.Results, Result{Check: name, Status: Skip, Error: reason})
+}
+
+// read reads and closes a downloaded body, failing if it's empty.
+func read(body io.ReadCloser, err error) (string, error) {
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+	b, err := ioutil.ReadAll(body)
+	if err == nil && len(b) == 0 {
+		err = fmt.Errorf("empty response")
+	}
+	return string(b), err
+}
+
+// lastSegment returns the last segment of a URI, e.g. the id of a created insight.
+func lastSegment(uri string) string {
+	return uri[strings.LastIndex(uri, "/")+1:]
+}
+
+// capture sends requests to the host, keeping the JSON bodies of successful responses to validate.
+type capture struct {
+	transport http.RoundTripper
+	requests  []string
+	bodies    [][]byte
+}
+
+func (c *capture) reset() {
+	c.requests, c.bodies = nil, nil
+}
+
+func (c *capture) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.requests = append(c.requests, req.Method+" "+req.URL.Path)
+	resp, err := c.transport.RoundTrip(req)
+	if err != nil || resp.StatusCode/100 != 2 || !strings.Contains(resp.Header.Get("Content-Type"), "json") {
+		return resp, err
+	}
+	b, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	c.bodies = append(c.bodies, b)
+	resp.Body = ioutil.NopCloser(strings.NewReader(string(b)))
+	return resp, nil
+}
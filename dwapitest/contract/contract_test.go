@@ -0,0 +1,149 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package contract
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/datadotworld/dwapi-go/dwapi"
+	"github.com/datadotworld/dwapi-go/dwapitest"
+	"github.com/stretchr/testify/assert"
+)
+
+func newServer() *dwapitest.Server {
+	srv := dwapitest.NewServer()
+	srv.SetSQLResult(SQLQuery, "text/csv", []byte("id,name\n1,contract\n"))
+	srv.SetSPARQLResult(SPARQLQuery, "application/sparql-results+json", []byte(`{"results": {"bindings": []}}`))
+	return srv
+}
+
+func results(r *Report) map[string]Result {
+	m := make(map[string]Result)
+	for _, res := range r.Results {
+		m[res.Check] = res
+	}
+	return m
+}
+
+func TestRun(t *testing.T) {
+	srv := newServer()
+	defer srv.Close()
+
+	r := Run(Config{BaseURL: srv.URL, Token: dwapitest.Token, ScratchID: "scratch"})
+	for _, res := range r.Results {
+		assert.Contains(t, []Status{Pass, Skip}, res.Status, "%s: %s %v %v", res.Check, res.Error,
+			res.UnknownFields, res.MissingFields)
+	}
+	assert.True(t, r.Compatible())
+	assert.Equal(t, "dwapitest", r.Owner)
+	assert.Equal(t, 1, r.Summary[Skip])
+
+	got := results(r)
+	assert.Equal(t, []string{"PUT /datasets/dwapitest/scratch"}, got["Dataset.CreateOrReplace"].Requests)
+	assert.Equal(t, Pass, got["Insight.Delete"].Status)
+	assert.Equal(t, "Dataset.Delete", r.Results[len(r.Results)-1].Check)
+
+	// The scratch dataset and project are gone.
+	_, ok := srv.Dataset("dwapitest", "scratch")
+	assert.False(t, ok)
+	_, ok = srv.Project("dwapitest", "scratch-project")
+	assert.False(t, ok)
+
+	var buf bytes.Buffer
+	assert.NoError(t, r.WriteJSON(&buf))
+	var decoded Report
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, len(r.Results), len(decoded.Results))
+}
+
+func TestRunReportsChanges(t *testing.T) {
+	srv := newServer()
+	defer srv.Close()
+
+	// A host that drops the dataset summary's owner, adds a field, and has lost the stream endpoints.
+	h := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if strings.HasPrefix(req.URL.Path, "/streams/") {
+			http.Error(w, `{"message": "gone"}`, http.StatusNotFound)
+			return
+		}
+		if req.Method != http.MethodGet || req.URL.Path != "/datasets/dwapitest/scratch" {
+			srv.ServeHTTP(w, req)
+			return
+		}
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, req)
+		var body map[string]interface{}
+		_ = json.Unmarshal(rec.Body.Bytes(), &body)
+		delete(body, "owner")
+		body["newField"] = true
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(body)
+	})
+	host := httptest.NewServer(h)
+	defer host.Close()
+
+	r := Run(Config{BaseURL: host.URL, Token: dwapitest.Token, ScratchID: "scratch"})
+	assert.False(t, r.Compatible())
+
+	got := results(r)
+	retrieve := got["Dataset.Retrieve"]
+	assert.Equal(t, Warn, retrieve.Status)
+	assert.Equal(t, []string{"newField"}, retrieve.UnknownFields)
+	assert.Equal(t, []string{"owner"}, retrieve.MissingFields)
+
+	assert.Equal(t, Fail, got["Stream.SetOrUpdateSchema"].Status)
+	assert.Equal(t, Skip, got["Stream.Append"].Status)
+	assert.Equal(t, "needs Stream.SetOrUpdateSchema", got["Stream.Append"].Error)
+	assert.Equal(t, Pass, got["Dataset.Delete"].Status)
+}
+
+func TestRunWithoutUser(t *testing.T) {
+	srv := newServer()
+	srv.RequireToken("secret")
+	defer srv.Close()
+
+	r := Run(Config{BaseURL: srv.URL, Token: "wrong"})
+	assert.False(t, r.Compatible())
+	assert.Len(t, r.Results, 1)
+	assert.Equal(t, Fail, r.Results[0].Status)
+	assert.Empty(t, srv.Requests()[1:])
+}
+
+func TestMissingFields(t *testing.T) {
+	type item struct {
+		Name string `json:"name"`
+		Size int    `json:"size,omitempty"`
+	}
+	type model struct {
+		ID    string                     `json:"id"`
+		Items []item                     `json:"items"`
+		Extra map[string]json.RawMessage `json:"-"`
+	}
+	bodies := [][]byte{[]byte(`{"ID": "x", "items": [{"name": "a"}, {"size": 1}]}`)}
+	assert.Equal(t, []string{"items[].name"}, missingFields(bodies, reflect.TypeOf(model{})))
+
+	page := [][]byte{[]byte(`{"count": 1, "records": [{"items": []}]}`)}
+	assert.Equal(t, []string{"id"}, missingFields(page, reflect.TypeOf([]model{})))
+	assert.Empty(t, missingFields(nil, reflect.TypeOf(dwapi.SuccessResponse{})))
+}
@@ -0,0 +1,119 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package contract
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// missingFields lists the fields that the model of a response expects, i.e. that aren't tagged
+// omitempty, but that are missing from the JSON bodies of the response, e.g. "files[].name". Pages of
+// paginated responses are validated record by record.
+func missingFields(bodies [][]byte, t reflect.Type) []string {
+	found := make(map[string]bool)
+	for _, b := range bodies {
+		var v interface{}
+		if json.Unmarshal(b, &v) != nil {
+			continue
+		}
+		if page, ok := v.(map[string]interface{}); ok && t.Kind() == reflect.Slice {
+			if records, ok := page["records"]; ok {
+				v = records
+			}
+		}
+		collectMissing(v, t, "", found)
+	}
+
+	fields := make([]string, 0, len(found))
+	for f := range found {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func collectMissing(v interface{}, t reflect.Type, path string, found map[string]bool) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		if path != "" {
+			path += "[]"
+		}
+		items, _ := v.([]interface{})
+		for _, item := range items {
+			collectMissing(item, t.Elem(), path, found)
+		}
+	case reflect.Struct:
+		object, ok := v.(map[string]interface{})
+		if !ok {
+			return
+		}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			tag := strings.Split(f.Tag.Get("json"), ",")
+			if f.PkgPath != "" || tag[0] == "-" {
+				continue
+			}
+			name := tag[0]
+			if name == "" {
+				name = f.Name
+			}
+			value, ok := lookup(object, name)
+			switch {
+			case ok:
+				collectMissing(value, f.Type, join(path, name), found)
+			case !contains(tag[1:], "omitempty"):
+				found[join(path, name)] = true
+			}
+		}
+	}
+}
+
+// lookup finds a field case-insensitively, like encoding/json does.
+func lookup(object map[string]interface{}, name string) (interface{}, bool) {
+	if v, ok := object[name]; ok {
+		return v, true
+	}
+	for k, v := range object {
+		if strings.EqualFold(k, name) {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+func contains(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,402 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+/*
+Package dwapitest provides an in-memory fake of the data.world API for integration tests.
+
+A Server keeps datasets, projects, files, saved queries, insights, streams, webhooks and DOIs in
+memory and serves the endpoints used by the dwapi package, so that a `dwapi.Client` pointed at it
+behaves like it would against the real API:
+
+	srv := dwapitest.NewServer()
+	defer srv.Close()
+
+	srv.AddDataset(dwapi.DatasetSummaryResponse{Owner: "tim-notes", ID: "my-awesome-dataset"})
+	srv.AddFile("tim-notes", "my-awesome-dataset", "data.csv", []byte("a,b\n1,2\n"))
+	srv.SetSQLResult("SELECT * FROM data", "text/csv", []byte("a,b\n1,2\n"))
+
+	dw := srv.NewClient()
+	r, err := dw.Query.ExecuteSQL("tim-notes", "my-awesome-dataset", "text/csv",
+		&dwapi.SQLQueryRequest{Query: "SELECT * FROM data"})
+
+Query results are not computed; they are looked up by query text from the results registered with
+SetSQLResult and SetSPARQLResult.
+*/
+package dwapitest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/datadotworld/dwapi-go/dwapi"
+)
+
+// Token is the API token used by clients returned from `Server.NewClient`.
+const Token = "dwapitest-token"
+
+const timeFormat = "2006-01-02T15:04:05.000Z"
+
+// Request is a request received by a Backend.
+type Request struct {
+	Method string
+	Path   string
+}
+
+// Backend is an http.Handler implementing the data.world API on top of in-memory state. It is safe
+// for concurrent use.
+type Backend struct {
+	// Clock returns the time used for created and updated timestamps. It defaults to time.Now.
+	Clock func() time.Time
+
+	mu       sync.Mutex
+	state    *store
+	pageSize int
+	token    string
+	requests []Request
+}
+
+// Server is an httptest.Server serving a Backend.
+type Server struct {
+	*httptest.Server
+	*Backend
+}
+
+// NewBackend returns an empty Backend.
+func NewBackend() *Backend {
+	return &Backend{
+		Clock: time.Now,
+		state: newStore(),
+	}
+}
+
+// NewServer starts a Server with an empty Backend. The caller should call Close when finished.
+func NewServer() *Server {
+	b := NewBackend()
+	return &Server{
+		Server:  httptest.NewServer(b),
+		Backend: b,
+	}
+}
+
+// NewClient returns a client for the server.
+func (s *Server) NewClient() *dwapi.Client {
+	c := dwapi.NewClient(Token)
+	c.BaseURL = s.URL
+	return c
+}
+
+// SetPageSize limits the number of records returned per page by paginated endpoints. A size of
+// zero, the default, returns all records in a single page.
+func (b *Backend) SetPageSize(n int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.pageSize = n
+}
+
+// RequireToken makes the backend reject requests that don't carry the token with a 401. By default
+// any bearer token is accepted, but a missing one is still rejected.
+func (b *Backend) RequireToken(token string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.token = token
+}
+
+// Requests returns the requests received so far, in order.
+func (b *Backend) Requests() []Request {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]Request(nil), b.requests...)
+}
+
+func (b *Backend) now() string {
+	return b.Clock().UTC().Format(timeFormat)
+}
+
+// SetCurrentUser replaces the authenticated user. New datasets, projects and queries are owned by
+// the current user, which defaults to "dwapitest".
+func (b *Backend) SetCurrentUser(user dwapi.UserInfoResponse) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.fillUser(&user)
+	b.state.User = user
+}
+
+// AddUser adds a user that can be retrieved with `User.Retrieve`.
+func (b *Backend) AddUser(user dwapi.UserInfoResponse) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.fillUser(&user)
+	b.state.Users[user.ID] = user
+}
+
+func (b *Backend) fillUser(user *dwapi.UserInfoResponse) {
+	now := b.now()
+	if user.Created == "" {
+		user.Created = now
+	}
+	if user.Updated == "" {
+		user.Updated = now
+	}
+}
+
+// AddDataset adds or replaces a dataset. Files listed in the summary are added without contents;
+// empty timestamps, status, access level and version are filled in.
+func (b *Backend) AddDataset(d dwapi.DatasetSummaryResponse) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	if d.Created == "" {
+		d.Created = now
+	}
+	if d.Status == "" {
+		d.Status = "LOADED"
+	}
+	if d.AccessLevel == "" {
+		d.AccessLevel = "ADMIN"
+	}
+	if d.Visibility == "" {
+		d.Visibility = "PRIVATE"
+	}
+	e := &entry{Dataset: &d, Files: map[string]*file{}}
+	for _, f := range d.Files {
+		e.Files[f.Name] = &file{Summary: f}
+	}
+	d.Files = nil
+	b.state.Entries[key(d.Owner, d.ID)] = e
+	b.state.touch(e, now)
+}
+
+// AddProject adds or replaces a project. Files listed in the summary are added without contents;
+// empty timestamps, status, access level and version are filled in.
+func (b *Backend) AddProject(p dwapi.ProjectSummaryResponse) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	if p.Created == "" {
+		p.Created = now
+	}
+	if p.Status == "" {
+		p.Status = "LOADED"
+	}
+	if p.AccessLevel == "" {
+		p.AccessLevel = "ADMIN"
+	}
+	if p.Visibility == "" {
+		p.Visibility = "PRIVATE"
+	}
+	e := &entry{IsProject: true, Project: &p, Files: map[string]*file{}}
+	for _, f := range p.Files {
+		e.Files[f.Name] = &file{Summary: f}
+	}
+	p.Files = nil
+	b.state.Entries[key(p.Owner, p.ID)] = e
+	b.state.touch(e, now)
+}
+
+// AddFile adds or replaces a file in a dataset or project. It returns false if the dataset or project
+// doesn't exist.
+func (b *Backend) AddFile(owner, id, filename string, content []byte) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	e, ok := b.state.Entries[key(owner, id)]
+	if !ok {
+		return false
+	}
+	b.putFile(e, filename, content)
+	b.state.touch(e, b.now())
+	return true
+}
+
+// AddSavedQuery adds a saved query to a dataset or project and returns it with its id, owner,
+// version and timestamps filled in. It returns false if the dataset or project doesn't exist.
+func (b *Backend) AddSavedQuery(owner, id string, q dwapi.QuerySummaryResponse) (dwapi.QuerySummaryResponse, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.state.Entries[key(owner, id)]; !ok {
+		return q, false
+	}
+	now := b.now()
+	if q.ID == "" {
+		q.ID = b.state.nextID()
+	}
+	if q.Owner == "" {
+		q.Owner = b.state.User.ID
+	}
+	if q.Language == "" {
+		q.Language = "SQL"
+	}
+	if q.Created == "" {
+		q.Created = now
+	}
+	q.Updated = now
+	q.Version = b.state.nextVersion()
+	b.state.Queries[q.ID] = &savedQuery{Parent: key(owner, id), Versions: []dwapi.QuerySummaryResponse{q}}
+	return q, true
+}
+
+// AddInsight adds an insight to a project and returns it with its id, author, version and
+// timestamps filled in. It returns false if the project doesn't exist.
+func (b *Backend) AddInsight(owner, projectid string, i dwapi.InsightSummaryResponse) (
+	dwapi.InsightSummaryResponse, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	e, ok := b.state.Entries[key(owner, projectid)]
+	if !ok || !e.IsProject {
+		return i, false
+	}
+	now := b.now()
+	if i.ID == "" {
+		i.ID = b.state.nextID()
+	}
+	if i.Author == "" {
+		i.Author = b.state.User.ID
+	}
+	if i.Created == "" {
+		i.Created = now
+	}
+	i.Updated = now
+	i.Version = b.state.nextVersion()
+	b.state.Insights[key(owner, projectid, i.ID)] = &insight{Versions: []dwapi.InsightSummaryResponse{i}}
+	return i, true
+}
+
+// Like bookmarks a dataset or project for the current user.
+func (b *Backend) Like(owner, id string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if k := key(owner, id); !contains(b.state.Liked, k) {
+		b.state.Liked = append(b.state.Liked, k)
+	}
+}
+
+// AddContributor makes the current user a contributor to a dataset or project.
+func (b *Backend) AddContributor(owner, id string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if k := key(owner, id); !contains(b.state.Contributing, k) {
+		b.state.Contributing = append(b.state.Contributing, k)
+	}
+}
+
+// SetSQLResult registers the body returned for a SQL query with the given accept type. Queries are
+// matched on their text, ignoring leading and trailing whitespace, in any dataset or project.
+func (b *Backend) SetSQLResult(query, acceptType string, body []byte) {
+	b.setResult("SQL", query, acceptType, body)
+}
+
+// SetSPARQLResult registers the body returned for a SPARQL query with the given accept type. Queries
+// are matched on their text, ignoring leading and trailing whitespace, in any dataset or project.
+func (b *Backend) SetSPARQLResult(query, acceptType string, body []byte) {
+	b.setResult("SPARQL", query, acceptType, body)
+}
+
+func (b *Backend) setResult(language, query, acceptType string, body []byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	k := resultKey(language, query)
+	if b.state.Results[k] == nil {
+		b.state.Results[k] = map[string]cannedResult{}
+	}
+	b.state.Results[k][acceptType] = cannedResult{Body: body}
+}
+
+// Dataset returns the current state of a dataset.
+func (b *Backend) Dataset(owner, id string) (dwapi.DatasetSummaryResponse, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	e, ok := b.state.Entries[key(owner, id)]
+	if !ok || e.IsProject {
+		return dwapi.DatasetSummaryResponse{}, false
+	}
+	return e.dataset(), true
+}
+
+// Project returns the current state of a project.
+func (b *Backend) Project(owner, id string) (dwapi.ProjectSummaryResponse, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	e, ok := b.state.Entries[key(owner, id)]
+	if !ok || !e.IsProject {
+		return dwapi.ProjectSummaryResponse{}, false
+	}
+	return e.project(), true
+}
+
+// FileContent returns the contents of a file in a dataset or project.
+func (b *Backend) FileContent(owner, id, filename string) ([]byte, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	e, ok := b.state.Entries[key(owner, id)]
+	if !ok {
+		return nil, false
+	}
+	f, ok := e.Files[filename]
+	if !ok {
+		return nil, false
+	}
+	return append([]byte(nil), f.Content...), true
+}
+
+// SavedQuery returns the current version of a saved query.
+func (b *Backend) SavedQuery(queryid string) (dwapi.QuerySummaryResponse, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	q, ok := b.state.Queries[queryid]
+	if !ok {
+		return dwapi.QuerySummaryResponse{}, false
+	}
+	return q.current(), true
+}
+
+// Insight returns the current version of an insight.
+func (b *Backend) Insight(owner, projectid, insightid string) (dwapi.InsightSummaryResponse, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	i, ok := b.state.Insights[key(owner, projectid, insightid)]
+	if !ok {
+		return dwapi.InsightSummaryResponse{}, false
+	}
+	return i.current(), true
+}
+
+// StreamRecords returns the records appended to a stream, in order.
+func (b *Backend) StreamRecords(owner, id, streamid string) []json.RawMessage {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s, ok := b.state.Streams[key(owner, id, streamid)]
+	if !ok {
+		return nil
+	}
+	return append([]json.RawMessage(nil), s.Records...)
+}
+
+// Subscriptions returns the current user's webhook subscriptions.
+func (b *Backend) Subscriptions() []dwapi.Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.subscriptions()
+}
+
+var _ http.Handler = (*Backend)(nil)
@@ -0,0 +1,366 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package dwapitest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/datadotworld/dwapi-go/dwapi"
+	"github.com/stretchr/testify/assert"
+)
+
+var testOwner = "tim-notes"
+
+func setup() (*Server, *dwapi.Client) {
+	srv := NewServer()
+	srv.Clock = func() time.Time { return time.Date(2018, 8, 3, 14, 56, 41, 777e6, time.UTC) }
+	srv.SetCurrentUser(dwapi.UserInfoResponse{ID: testOwner, DisplayName: "Tim"})
+	return srv, srv.NewClient()
+}
+
+func TestServer_Datasets(t *testing.T) {
+	srv, dw := setup()
+	defer srv.Close()
+
+	created, err := dw.Dataset.Create(testOwner, &dwapi.DatasetCreateRequest{
+		Title:      "My Awesome Dataset",
+		Visibility: "OPEN",
+		Tags:       []string{"awesome"},
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "https://data.world/tim-notes/my-awesome-dataset", created.URI)
+	}
+
+	_, err = dw.Dataset.Create(testOwner, &dwapi.DatasetCreateRequest{Title: "My Awesome Dataset", Visibility: "OPEN"})
+	assert.EqualError(t, err, "409 Conflict")
+
+	first, err := dw.Dataset.Retrieve(testOwner, "my-awesome-dataset")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "My Awesome Dataset", first.Title)
+		assert.Equal(t, "2018-08-03T14:56:41.777Z", first.Created)
+	}
+
+	_, err = dw.Dataset.Update(testOwner, "my-awesome-dataset", &dwapi.DatasetUpdateRequest{Summary: "Updated"})
+	assert.NoError(t, err)
+
+	got, _ := srv.Dataset(testOwner, "my-awesome-dataset")
+	assert.Equal(t, "Updated", got.Summary)
+	assert.NotEqual(t, first.Version, got.Version)
+
+	old, err := dw.Dataset.RetrieveVersion(testOwner, "my-awesome-dataset", first.Version)
+	if assert.NoError(t, err) {
+		assert.Equal(t, first, old)
+	}
+
+	owned, err := dw.Dataset.Owned()
+	if assert.NoError(t, err) {
+		assert.Len(t, owned, 1)
+	}
+
+	_, err = dw.Dataset.Delete(testOwner, "my-awesome-dataset")
+	assert.NoError(t, err)
+	_, err = dw.Dataset.Retrieve(testOwner, "my-awesome-dataset")
+	assert.EqualError(t, err, "404 Not Found")
+}
+
+func TestServer_Pagination(t *testing.T) {
+	srv, dw := setup()
+	defer srv.Close()
+
+	srv.SetPageSize(2)
+	for i := 0; i < 3; i++ {
+		srv.AddDataset(dwapi.DatasetSummaryResponse{Owner: testOwner, ID: fmt.Sprintf("dataset-%d", i)})
+	}
+
+	got, err := dw.User.DatasetsOwned()
+	if assert.NoError(t, err) {
+		assert.Len(t, got, 3)
+		assert.Equal(t, "dataset-2", got[2].ID)
+	}
+
+	var pages []string
+	for _, r := range srv.Requests() {
+		pages = append(pages, r.Path)
+	}
+	assert.Equal(t, []string{"/user/datasets/own", "/user/datasets/own"}, pages)
+}
+
+func TestServer_Files(t *testing.T) {
+	srv, dw := setup()
+	defer srv.Close()
+
+	srv.AddDataset(dwapi.DatasetSummaryResponse{Owner: testOwner, ID: "my-awesome-dataset"})
+
+	_, err := dw.File.UploadStream(testOwner, "my-awesome-dataset", "data.csv", strings.NewReader("a,b\n1,2\n"), false)
+	assert.NoError(t, err)
+
+	content, ok := srv.FileContent(testOwner, "my-awesome-dataset", "data.csv")
+	if assert.True(t, ok) {
+		assert.Equal(t, "a,b\n1,2\n", string(content))
+	}
+
+	r, err := dw.File.Download(testOwner, "my-awesome-dataset", "data.csv")
+	if assert.NoError(t, err) {
+		b, _ := ioutil.ReadAll(r)
+		assert.Equal(t, "a,b\n1,2\n", string(b))
+		r.Close()
+	}
+
+	_, err = dw.File.AddFilesFromURLs(testOwner, "my-awesome-dataset", &[]dwapi.FileCreateRequest{{
+		Name:   "remote.csv",
+		Source: dwapi.FileSourceCreateOrUpdateRequest{URL: "https://example.com/remote.csv"},
+	}})
+	assert.NoError(t, err)
+	_, err = dw.File.Sync(testOwner, "my-awesome-dataset")
+	assert.NoError(t, err)
+
+	d, _ := srv.Dataset(testOwner, "my-awesome-dataset")
+	if assert.Len(t, d.Files, 2) {
+		assert.Equal(t, "OK", d.Files[1].Source.SyncStatus)
+	}
+
+	_, err = dw.File.Delete(testOwner, "my-awesome-dataset", "missing.csv")
+	assert.EqualError(t, err, "404 Not Found")
+}
+
+func TestServer_Queries(t *testing.T) {
+	srv, dw := setup()
+	defer srv.Close()
+
+	srv.AddProject(dwapi.ProjectSummaryResponse{Owner: testOwner, ID: "my-awesome-project"})
+	srv.SetSQLResult("SELECT * FROM Tables", "text/csv", []byte("tableId\ndata\n"))
+
+	q, err := dw.Query.CreateSavedQueryInProject(testOwner, "my-awesome-project", &dwapi.QueryCreateRequest{
+		Name:     "Metadata",
+		Content:  "SELECT * FROM Tables",
+		Language: "SQL",
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	updated, err := dw.Query.UpdateSavedQueryInProject(testOwner, "my-awesome-project", q.ID, &dwapi.QueryUpdateRequest{
+		Name:    "Metadata",
+		Content: "SELECT * FROM Tables ",
+	})
+	if assert.NoError(t, err) {
+		assert.NotEqual(t, q.Version, updated.Version)
+	}
+	first, err := dw.Query.RetrieveVersion(q.ID, q.Version)
+	if assert.NoError(t, err) {
+		assert.Equal(t, q, first)
+	}
+
+	r, err := dw.Query.ExecuteSavedQuery(q.ID, "text/csv", &dwapi.SavedQueryExecutionRequest{})
+	if assert.NoError(t, err) {
+		b, _ := ioutil.ReadAll(r)
+		assert.Equal(t, "tableId\ndata\n", string(b))
+		r.Close()
+	}
+
+	_, err = dw.Query.ExecuteSQL(testOwner, "my-awesome-project", "application/json",
+		&dwapi.SQLQueryRequest{Query: "SELECT * FROM Tables"})
+	assert.EqualError(t, err, "406 Not Acceptable")
+	_, err = dw.Query.ExecuteSPARQL(testOwner, "my-awesome-project", "",
+		&dwapi.SPARQLQueryRequest{Query: "SELECT * WHERE { ?s ?p ?o }"})
+	assert.EqualError(t, err, "400 Bad Request")
+
+	list, err := dw.Project.ListQueries(testOwner, "my-awesome-project")
+	if assert.NoError(t, err) {
+		assert.Equal(t, []dwapi.QuerySummaryResponse{updated}, list)
+	}
+}
+
+func TestServer_QueryVersions(t *testing.T) {
+	srv, dw := setup()
+	defer srv.Close()
+
+	srv.AddDataset(dwapi.DatasetSummaryResponse{Owner: testOwner, ID: "my-awesome-dataset"})
+	srv.SetSQLResult("SELECT name FROM people", "text/csv", []byte("name\nAbe\n"))
+
+	q, ok := srv.AddSavedQuery(testOwner, "my-awesome-dataset", dwapi.QuerySummaryResponse{
+		Name:      "People",
+		Body:      "SELECT name FROM people",
+		Language:  "SQL",
+		Published: true,
+	})
+	if !assert.True(t, ok) {
+		return
+	}
+	updated, err := dw.Query.UpdateSavedQueryInDataset(testOwner, "my-awesome-dataset", q.ID, &dwapi.QueryUpdateRequest{
+		Name:      "People",
+		Content:   "SELECT name, age FROM people",
+		Published: true,
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	r, err := dw.Query.ExecuteSavedQueryVersion(testOwner, "my-awesome-dataset", q.ID, q.Version, "text/csv", nil)
+	if assert.NoError(t, err) {
+		b, _ := ioutil.ReadAll(r)
+		assert.Equal(t, "name\nAbe\n", string(b))
+		r.Close()
+	}
+
+	diff, err := dw.Query.DiffVersions(q.ID, q.Version, updated.Version)
+	if assert.NoError(t, err) {
+		assert.Contains(t, diff, "-SELECT name FROM people\n+SELECT name, age FROM people\n")
+	}
+
+	rolledBack, err := dw.Query.RollbackSavedQueryInDataset(testOwner, "my-awesome-dataset", q.ID, q.Version)
+	if assert.NoError(t, err) {
+		assert.Equal(t, q.Body, rolledBack.Body)
+		assert.True(t, rolledBack.Published)
+		current, _ := srv.SavedQuery(q.ID)
+		assert.Equal(t, rolledBack, current)
+	}
+	old, err := dw.Query.RetrieveVersion(q.ID, updated.Version)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "SELECT name, age FROM people", old.Body)
+	}
+}
+
+func TestServer_Streams(t *testing.T) {
+	srv, dw := setup()
+	defer srv.Close()
+
+	srv.AddDataset(dwapi.DatasetSummaryResponse{Owner: testOwner, ID: "my-awesome-dataset"})
+
+	_, err := dw.Stream.Append(testOwner, "my-awesome-dataset", "events",
+		strings.NewReader("{\"id\": 1}\n{\"id\": 2}\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, []json.RawMessage{json.RawMessage(`{"id": 1}`), json.RawMessage(`{"id": 2}`)},
+		srv.StreamRecords(testOwner, "my-awesome-dataset", "events"))
+
+	_, err = dw.Stream.Append(testOwner, "my-awesome-dataset", "events", strings.NewReader("not json\n"))
+	assert.EqualError(t, err, "400 Bad Request")
+
+	_, err = dw.Stream.SetOrUpdateSchema(testOwner, "my-awesome-dataset", "events", &dwapi.StreamSchemaUpdateRequest{
+		PrimaryKeyFields: []string{"id"},
+		UpdateMethod:     "TRUNCATED",
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, srv.StreamRecords(testOwner, "my-awesome-dataset", "events"))
+
+	schema, err := dw.Stream.RetrieveSchema(testOwner, "my-awesome-dataset", "events")
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"id"}, schema.PrimaryKeyFields)
+	}
+}
+
+func TestServer_Insights(t *testing.T) {
+	srv, dw := setup()
+	defer srv.Close()
+
+	srv.AddProject(dwapi.ProjectSummaryResponse{Owner: testOwner, ID: "my-awesome-project"})
+
+	_, err := dw.Insight.Create(testOwner, "my-awesome-project", &dwapi.InsightCreateRequest{
+		Title: "My Awesome Insight",
+		Body:  dwapi.InsightBody{ImageURL: "https://www.link.to.image.com/img.jpg"},
+	})
+	assert.NoError(t, err)
+
+	list, err := dw.Insight.List(testOwner, "my-awesome-project")
+	if !assert.NoError(t, err) || !assert.Len(t, list, 1) {
+		return
+	}
+	_, err = dw.Insight.Update(testOwner, "my-awesome-project", list[0].ID, &dwapi.InsightUpdateRequest{
+		Title: "My Updated Insight",
+	})
+	assert.NoError(t, err)
+
+	got, _ := srv.Insight(testOwner, "my-awesome-project", list[0].ID)
+	assert.Equal(t, "My Updated Insight", got.Title)
+	assert.Equal(t, list[0].Body, got.Body)
+}
+
+func TestServer_WebhooksAndDOIs(t *testing.T) {
+	srv, dw := setup()
+	defer srv.Close()
+
+	srv.AddDataset(dwapi.DatasetSummaryResponse{Owner: testOwner, ID: "my-awesome-dataset"})
+
+	_, err := dw.Webhook.SubscribeToDataset(testOwner, "my-awesome-dataset",
+		&dwapi.SubscriptionCreateRequest{Events: []string{"ALL"}})
+	assert.NoError(t, err)
+	_, err = dw.Webhook.SubscribeToDataset(testOwner, "missing-dataset",
+		&dwapi.SubscriptionCreateRequest{Events: []string{"ALL"}})
+	assert.EqualError(t, err, "404 Not Found")
+
+	subs, err := dw.Webhook.List()
+	if assert.NoError(t, err) {
+		assert.Equal(t, srv.Subscriptions(), subs)
+		assert.Equal(t, "my-awesome-dataset", subs[0].Dataset.ID)
+	}
+
+	_, err = dw.DOI.Associate(testOwner, "my-awesome-dataset", "10.1000/182")
+	assert.NoError(t, err)
+	d, _ := srv.Dataset(testOwner, "my-awesome-dataset")
+	if assert.Len(t, d.Dois, 1) {
+		assert.Equal(t, "10.1000/182", d.Dois[0].Doi)
+	}
+}
+
+func TestServer_Unauthorized(t *testing.T) {
+	srv, dw := setup()
+	defer srv.Close()
+
+	srv.RequireToken("another-token")
+	_, err := dw.User.Self()
+	assert.EqualError(t, err, "401 Unauthorized")
+}
+
+func TestBackend_SnapshotRestore(t *testing.T) {
+	srv, dw := setup()
+	defer srv.Close()
+
+	err := srv.LoadFixture(&Fixture{
+		Datasets: []dwapi.DatasetSummaryResponse{{Owner: testOwner, ID: "my-awesome-dataset"}},
+		Files:    []FixtureFile{{Owner: testOwner, ID: "my-awesome-dataset", Name: "data.csv", Content: "a\n1\n"}},
+	}, "")
+	assert.NoError(t, err)
+
+	b := new(bytes.Buffer)
+	assert.NoError(t, srv.Snapshot(b))
+
+	restored := NewServer()
+	defer restored.Close()
+	assert.NoError(t, restored.Restore(b))
+
+	content, ok := restored.FileContent(testOwner, "my-awesome-dataset", "data.csv")
+	if assert.True(t, ok) {
+		assert.Equal(t, "a\n1\n", string(content))
+	}
+	want, _ := dw.Dataset.Retrieve(testOwner, "my-awesome-dataset")
+	got, err := restored.NewClient().Dataset.Retrieve(testOwner, "my-awesome-dataset")
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, got)
+	}
+
+	err = srv.LoadFixture(&Fixture{
+		Files: []FixtureFile{{Owner: testOwner, ID: "missing-dataset", Name: "data.csv"}},
+	}, "")
+	assert.EqualError(t, err, "file data.csv: dataset tim-notes/missing-dataset not found")
+}
@@ -0,0 +1,302 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+/*
+Package faultinject provides an HTTP transport that injects faults into API calls, for testing how
+code using the dwapi package behaves when data.world is slow, unavailable or misbehaving.
+
+Faults are described by rules that select requests by method and path, and by call count or
+probability:
+
+	t := faultinject.New(1,
+		faultinject.Rule{Method: "GET", Nth: 2, StatusCode: 503},
+		faultinject.Rule{Method: "GET", Path: "/file_download/**", Truncate: true, TruncateAfter: 512},
+		faultinject.Rule{Probability: 0.1, Latency: 2 * time.Second},
+	)
+	dw := dwapi.NewClient(token)
+	dw.HTTPClient = t.Client()
+
+Requests that no rule fires on are passed through to the underlying transport unchanged.
+*/
+package faultinject
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrConnectionReset is returned for requests that a rule resets.
+var ErrConnectionReset = errors.New("faultinject: connection reset by peer")
+
+// Rule selects requests and describes the fault injected into them.
+//
+// A request is selected when it matches Method and Path, and then, out of the matching requests,
+// when it is the Nth one, or every Every-th one, and passes the Probability check. Zero values
+// select everything. The first rule selecting a request is applied to it.
+type Rule struct {
+	// Method is the HTTP method to match; empty matches any method.
+	Method string
+	// Path is a `path.Match` pattern matched against the request path without the /v0 prefix, e.g.
+	// "/datasets/*/*". A trailing "/**" matches any number of further segments, e.g.
+	// "/file_download/**". Empty matches any path.
+	Path string
+	// Nth selects only the nth matching request, counting from 1.
+	Nth int
+	// Every selects every Every-th matching request.
+	Every int
+	// Probability is the chance of selecting a matching request, between 0 and 1. 0 always selects.
+	Probability float64
+	// Times limits the number of faults injected by the rule; 0 is unlimited.
+	Times int
+
+	// Latency delays the request before it's sent or answered.
+	Latency time.Duration
+	// Reset fails the request with ErrConnectionReset without sending it.
+	Reset bool
+	// StatusCode answers the request with this status and Body without sending it.
+	StatusCode int
+	Body       string
+	Header     http.Header
+	// Truncate cuts the response body short after TruncateAfter bytes, failing further reads with
+	// io.ErrUnexpectedEOF.
+	Truncate      bool
+	TruncateAfter int
+
+	matched  int
+	injected int
+}
+
+// Injection records a fault injected into a request.
+type Injection struct {
+	Rule   int
+	Method string
+	Path   string
+}
+
+// Transport is an http.RoundTripper that injects faults into requests according to its rules.
+type Transport struct {
+	// Transport sends the requests that aren't answered by a rule; http.DefaultTransport if nil.
+	Transport http.RoundTripper
+
+	mu         sync.Mutex
+	rules      []*Rule
+	rand       *rand.Rand
+	injections []Injection
+}
+
+// New returns a transport applying the rules. Probabilities are drawn from a source seeded with seed,
+// so a given sequence of requests always gets the same faults.
+func New(seed int64, rules ...Rule) *Transport {
+	t := &Transport{rand: rand.New(rand.NewSource(seed))}
+	for i := range rules {
+		r := rules[i]
+		r.matched, r.injected = 0, 0
+		t.rules = append(t.rules, &r)
+	}
+	return t
+}
+
+// Client returns an HTTP client using the transport, for use as `dwapi.Client.HTTPClient`.
+func (t *Transport) Client() *http.Client {
+	return &http.Client{Transport: t}
+}
+
+// Injections lists the faults injected so far.
+func (t *Transport) Injections() []Injection {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]Injection(nil), t.injections...)
+}
+
+// Reset clears the call counts and injected faults, without changing the rules.
+func (t *Transport) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, r := range t.rules {
+		r.matched, r.injected = 0, 0
+	}
+	t.injections = nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rule := t.selectRule(req)
+	if rule == nil {
+		return t.transport().RoundTrip(req)
+	}
+
+	if rule.Latency > 0 {
+		timer := time.NewTimer(rule.Latency)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			closeBody(req)
+			return nil, req.Context().Err()
+		}
+	}
+
+	if rule.Reset {
+		closeBody(req)
+		return nil, ErrConnectionReset
+	}
+
+	var response *http.Response
+	if rule.StatusCode != 0 {
+		closeBody(req)
+		response = newResponse(req, rule)
+	} else {
+		var err error
+		if response, err = t.transport().RoundTrip(req); err != nil {
+			return nil, err
+		}
+	}
+
+	if rule.Truncate {
+		response.Body = &truncatedBody{r: response.Body, remaining: rule.TruncateAfter}
+		response.ContentLength = -1
+		response.Header.Del("Content-Length")
+	}
+	return response, nil
+}
+
+func (t *Transport) transport() http.RoundTripper {
+	if t.Transport != nil {
+		return t.Transport
+	}
+	return http.DefaultTransport
+}
+
+// selectRule counts the request against every rule it matches, and returns the first rule selecting it.
+func (t *Transport) selectRule(req *http.Request) *Rule {
+	p := strings.TrimPrefix(req.URL.Path, "/v0")
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var selected *Rule
+	for i, r := range t.rules {
+		if !r.matches(req.Method, p) {
+			continue
+		}
+		r.matched++
+		if selected != nil || !r.selects(t.rand) {
+			continue
+		}
+		r.injected++
+		selected = r
+		t.injections = append(t.injections, Injection{Rule: i, Method: req.Method, Path: req.URL.Path})
+	}
+	return selected
+}
+
+func (r *Rule) matches(method, p string) bool {
+	if r.Method != "" && !strings.EqualFold(r.Method, method) {
+		return false
+	}
+	if r.Path == "" {
+		return true
+	}
+	if prefix := strings.TrimSuffix(r.Path, "/**"); prefix != r.Path {
+		// Match the prefix against the same number of leading segments.
+		n := len(strings.Split(prefix, "/"))
+		segments := strings.Split(p, "/")
+		if len(segments) < n {
+			return false
+		}
+		p = strings.Join(segments[:n], "/")
+		ok, _ := path.Match(prefix, p)
+		return ok
+	}
+	ok, _ := path.Match(r.Path, p)
+	return ok
+}
+
+func (r *Rule) selects(rnd *rand.Rand) bool {
+	if r.Times > 0 && r.injected >= r.Times {
+		return false
+	}
+	if r.Nth > 0 && r.matched != r.Nth {
+		return false
+	}
+	if r.Every > 0 && r.matched%r.Every != 0 {
+		return false
+	}
+	if r.Probability > 0 && rnd.Float64() >= r.Probability {
+		return false
+	}
+	return true
+}
+
+func newResponse(req *http.Request, r *Rule) *http.Response {
+	header := make(http.Header)
+	for k, v := range r.Header {
+		header[k] = append([]string(nil), v...)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode)),
+		StatusCode:    r.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewBufferString(r.Body)),
+		ContentLength: int64(len(r.Body)),
+		Request:       req,
+	}
+}
+
+func closeBody(req *http.Request) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+}
+
+// truncatedBody reads up to remaining bytes from r, then fails with io.ErrUnexpectedEOF.
+type truncatedBody struct {
+	r         io.ReadCloser
+	remaining int
+}
+
+func (b *truncatedBody) Read(p []byte) (int, error) {
+	if b.remaining <= 0 {
+		return 0, io.ErrUnexpectedEOF
+	}
+	if len(p) > b.remaining {
+		p = p[:b.remaining]
+	}
+	n, err := b.r.Read(p)
+	b.remaining -= n
+	if err == io.EOF {
+		// The body ended before the cut, so it's returned intact.
+		return n, io.EOF
+	}
+	return n, err
+}
+
+func (b *truncatedBody) Close() error {
+	return b.r.Close()
+}
@@ -0,0 +1,183 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package faultinject
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/datadotworld/dwapi-go/dwapi"
+	"github.com/datadotworld/dwapi-go/dwapitest"
+	"github.com/stretchr/testify/assert"
+)
+
+func setup(rules ...Rule) (*dwapitest.Server, *Transport, *dwapi.Client) {
+	srv := dwapitest.NewServer()
+	srv.AddDataset(dwapi.DatasetSummaryResponse{Owner: "dwapitest", ID: "first-dataset"})
+	srv.AddDataset(dwapi.DatasetSummaryResponse{Owner: "dwapitest", ID: "second-dataset"})
+	srv.AddFile("dwapitest", "first-dataset", "data.csv", []byte(strings.Repeat("a,b\n", 100)))
+
+	t := New(1, rules...)
+	dw := srv.NewClient()
+	dw.HTTPClient = t.Client()
+	return srv, t, dw
+}
+
+func TestTransport_StatusCode(t *testing.T) {
+	srv, ft, dw := setup(Rule{Method: "GET", Path: "/datasets/*/*", Nth: 2, StatusCode: 503, Body: "unavailable"})
+	defer srv.Close()
+
+	_, err := dw.Dataset.Retrieve("dwapitest", "first-dataset")
+	assert.NoError(t, err)
+	_, err = dw.Dataset.Retrieve("dwapitest", "first-dataset")
+	assert.EqualError(t, err, "503 Service Unavailable")
+	_, err = dw.Dataset.Retrieve("dwapitest", "first-dataset")
+	assert.NoError(t, err)
+
+	assert.Equal(t, []Injection{{Rule: 0, Method: "GET", Path: "/datasets/dwapitest/first-dataset"}}, ft.Injections())
+	// The faulted request never reached the server.
+	assert.Len(t, srv.Requests(), 2)
+}
+
+func TestTransport_Reset(t *testing.T) {
+	srv, _, dw := setup(Rule{Method: "DELETE", Reset: true, Times: 1})
+	defer srv.Close()
+
+	_, err := dw.Dataset.Delete("dwapitest", "first-dataset")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), ErrConnectionReset.Error())
+	}
+	_, ok := srv.Dataset("dwapitest", "first-dataset")
+	assert.True(t, ok)
+
+	_, err = dw.Dataset.Delete("dwapitest", "first-dataset")
+	assert.NoError(t, err)
+}
+
+func TestTransport_Latency(t *testing.T) {
+	srv, _, dw := setup(Rule{Latency: 50 * time.Millisecond})
+	defer srv.Close()
+
+	start := time.Now()
+	_, err := dw.Dataset.Retrieve("dwapitest", "first-dataset")
+	assert.NoError(t, err)
+	assert.True(t, time.Since(start) >= 50*time.Millisecond)
+
+	dw.HTTPClient.Timeout = 10 * time.Millisecond
+	_, err = dw.Dataset.Retrieve("dwapitest", "first-dataset")
+	assert.Error(t, err)
+}
+
+func TestTransport_Truncate(t *testing.T) {
+	srv, _, dw := setup(Rule{Path: "/file_download/**", Truncate: true, TruncateAfter: 10})
+	defer srv.Close()
+
+	r, err := dw.File.Download("dwapitest", "first-dataset", "data.csv")
+	if assert.NoError(t, err) {
+		b, err := ioutil.ReadAll(r)
+		r.Close()
+		assert.Equal(t, io.ErrUnexpectedEOF, err)
+		assert.Len(t, b, 10)
+	}
+
+	dir, err := ioutil.TempDir("", "faultinject")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	_, err = dw.File.DownloadAndSave("dwapitest", "first-dataset", "data.csv", filepath.Join(dir, "data.csv"))
+	assert.Equal(t, io.ErrUnexpectedEOF, err)
+
+	// Shorter bodies are returned intact.
+	ft := New(1, Rule{Truncate: true, TruncateAfter: 1000})
+	dw.HTTPClient = ft.Client()
+	r, err = dw.File.Download("dwapitest", "first-dataset", "data.csv")
+	if assert.NoError(t, err) {
+		b, err := ioutil.ReadAll(r)
+		r.Close()
+		assert.NoError(t, err)
+		assert.Len(t, b, 400)
+	}
+}
+
+func TestTransport_Pagination(t *testing.T) {
+	srv, _, dw := setup(Rule{Path: "/user/datasets/own", Nth: 2, StatusCode: 500})
+	defer srv.Close()
+	srv.SetPageSize(1)
+
+	_, err := dw.User.DatasetsOwned()
+	assert.EqualError(t, err, "500 Internal Server Error")
+
+	got, err := dw.User.DatasetsOwned()
+	assert.NoError(t, err)
+	assert.Len(t, got, 2)
+}
+
+func TestTransport_Selection(t *testing.T) {
+	tests := []struct {
+		name string
+		rule Rule
+		want []bool
+	}{
+		{"always", Rule{}, []bool{true, true, true, true}},
+		{"method", Rule{Method: "post"}, []bool{false, false, false, false}},
+		{"path", Rule{Path: "/datasets/*"}, []bool{false, false, false, false}},
+		{"prefix", Rule{Path: "/datasets/**"}, []bool{true, true, true, true}},
+		{"nth", Rule{Nth: 3}, []bool{false, false, true, false}},
+		{"every", Rule{Every: 2}, []bool{false, true, false, true}},
+		{"times", Rule{Every: 2, Times: 1}, []bool{false, true, false, false}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.rule.StatusCode = 500
+			srv, _, dw := setup(tt.rule)
+			defer srv.Close()
+
+			var got []bool
+			for range tt.want {
+				_, err := dw.Dataset.Retrieve("dwapitest", "first-dataset")
+				got = append(got, err != nil)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTransport_Probability(t *testing.T) {
+	run := func() []bool {
+		srv, _, dw := setup(Rule{Probability: 0.5, StatusCode: 500})
+		defer srv.Close()
+
+		var got []bool
+		for i := 0; i < 20; i++ {
+			_, err := dw.Dataset.Retrieve("dwapitest", "first-dataset")
+			got = append(got, err != nil)
+		}
+		return got
+	}
+
+	first := run()
+	assert.Equal(t, first, run())
+	assert.Contains(t, first, true)
+	assert.Contains(t, first, false)
+}
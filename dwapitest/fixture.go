@@ -0,0 +1,178 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package dwapitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/datadotworld/dwapi-go/dwapi"
+)
+
+// Fixture describes seed data for a Backend. Fixtures are usually kept as JSON files, e.g.:
+//
+//	{
+//	  "datasets": [{"owner": "tim-notes", "id": "my-awesome-dataset", "title": "My Awesome Dataset"}],
+//	  "files": [{"owner": "tim-notes", "id": "my-awesome-dataset", "name": "data.csv", "path": "data.csv"}],
+//	  "results": [{"language": "SQL", "query": "SELECT * FROM data", "acceptType": "text/csv", "path": "data.csv"}]
+//	}
+//
+// Relative paths are resolved against the directory containing the fixture file.
+type Fixture struct {
+	User     *dwapi.UserInfoResponse        `json:"user,omitempty"`
+	Users    []dwapi.UserInfoResponse       `json:"users,omitempty"`
+	Datasets []dwapi.DatasetSummaryResponse `json:"datasets,omitempty"`
+	Projects []dwapi.ProjectSummaryResponse `json:"projects,omitempty"`
+	Files    []FixtureFile                  `json:"files,omitempty"`
+	Queries  []FixtureQuery                 `json:"queries,omitempty"`
+	Insights []FixtureInsight               `json:"insights,omitempty"`
+	Results  []FixtureResult                `json:"results,omitempty"`
+}
+
+// FixtureFile is a file in a dataset or project. Its contents are either given inline in Content or
+// read from Path.
+type FixtureFile struct {
+	Owner   string `json:"owner"`
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Content string `json:"content,omitempty"`
+	Path    string `json:"path,omitempty"`
+}
+
+// FixtureQuery is a saved query in a dataset or project.
+type FixtureQuery struct {
+	Owner string                     `json:"owner"`
+	ID    string                     `json:"id"`
+	Query dwapi.QuerySummaryResponse `json:"query"`
+}
+
+// FixtureInsight is an insight in a project.
+type FixtureInsight struct {
+	Owner     string                       `json:"owner"`
+	ProjectID string                       `json:"projectId"`
+	Insight   dwapi.InsightSummaryResponse `json:"insight"`
+}
+
+// FixtureResult is a canned query result. Its body is either given inline in Body or read from Path.
+type FixtureResult struct {
+	Language   string `json:"language"`
+	Query      string `json:"query"`
+	AcceptType string `json:"acceptType"`
+	Body       string `json:"body,omitempty"`
+	Path       string `json:"path,omitempty"`
+}
+
+// LoadFixtureFile reads a JSON fixture from a file and loads it into the backend.
+func (b *Backend) LoadFixtureFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var fixture Fixture
+	if err = json.NewDecoder(f).Decode(&fixture); err != nil {
+		return fmt.Errorf("%s: %s", path, err)
+	}
+	return b.LoadFixture(&fixture, filepath.Dir(path))
+}
+
+// LoadFixture loads seed data into the backend, adding to or replacing its current state. Relative
+// paths in the fixture are resolved against dir.
+func (b *Backend) LoadFixture(f *Fixture, dir string) error {
+	read := func(inline, path string) ([]byte, error) {
+		if path == "" {
+			return []byte(inline), nil
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		return ioutil.ReadFile(path)
+	}
+
+	if f.User != nil {
+		b.SetCurrentUser(*f.User)
+	}
+	for _, u := range f.Users {
+		b.AddUser(u)
+	}
+	for _, d := range f.Datasets {
+		b.AddDataset(d)
+	}
+	for _, p := range f.Projects {
+		b.AddProject(p)
+	}
+	for _, file := range f.Files {
+		content, err := read(file.Content, file.Path)
+		if err != nil {
+			return err
+		}
+		if !b.AddFile(file.Owner, file.ID, file.Name, content) {
+			return fmt.Errorf("file %s: dataset %s/%s not found", file.Name, file.Owner, file.ID)
+		}
+	}
+	for _, q := range f.Queries {
+		if _, ok := b.AddSavedQuery(q.Owner, q.ID, q.Query); !ok {
+			return fmt.Errorf("query %s: dataset %s/%s not found", q.Query.Name, q.Owner, q.ID)
+		}
+	}
+	for _, i := range f.Insights {
+		if _, ok := b.AddInsight(i.Owner, i.ProjectID, i.Insight); !ok {
+			return fmt.Errorf("insight %s: project %s/%s not found", i.Insight.Title, i.Owner, i.ProjectID)
+		}
+	}
+	for _, r := range f.Results {
+		body, err := read(r.Body, r.Path)
+		if err != nil {
+			return err
+		}
+		switch strings.ToUpper(r.Language) {
+		case "SQL":
+			b.SetSQLResult(r.Query, r.AcceptType, body)
+		case "SPARQL":
+			b.SetSPARQLResult(r.Query, r.AcceptType, body)
+		default:
+			return fmt.Errorf("result for %q: unknown language %q", r.Query, r.Language)
+		}
+	}
+	return nil
+}
+
+// Snapshot writes the complete state of the backend as JSON.
+func (b *Backend) Snapshot(w io.Writer) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return json.NewEncoder(w).Encode(b.state)
+}
+
+// Restore replaces the state of the backend with one written by Snapshot.
+func (b *Backend) Restore(r io.Reader) error {
+	s := newStore()
+	if err := json.NewDecoder(r).Decode(s); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.state = s
+	return nil
+}
@@ -0,0 +1,1429 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package dwapitest
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/datadotworld/dwapi-go/dwapi"
+)
+
+type errorResponse struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type paginatedResponse struct {
+	Count         int           `json:"count"`
+	NextPageToken string        `json:"nextPageToken,omitempty"`
+	Records       []interface{} `json:"records"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, format string, args ...interface{}) {
+	writeJSON(w, status, errorResponse{Code: status, Message: fmt.Sprintf(format, args...)})
+}
+
+func writeSuccess(w http.ResponseWriter, message string) {
+	writeJSON(w, http.StatusOK, dwapi.SuccessResponse{Message: message})
+}
+
+func notFound(w http.ResponseWriter, what string) {
+	writeError(w, http.StatusNotFound, "%s not found", what)
+}
+
+func methodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	writeError(w, http.StatusMethodNotAllowed, "method %s not allowed on %s", r.Method, r.URL.Path)
+}
+
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: %s", err)
+		return false
+	}
+	return true
+}
+
+// ServeHTTP implements the data.world API. Paths are accepted with or without the /v0 prefix.
+func (b *Backend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/v0")
+	b.requests = append(b.requests, Request{Method: r.Method, Path: path})
+
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Bearer ") || auth == "Bearer " ||
+		(b.token != "" && auth != "Bearer "+b.token) {
+		writeError(w, http.StatusUnauthorized, "missing or invalid API token")
+		return
+	}
+
+	segs := strings.Split(strings.Trim(path, "/"), "/")
+	switch segs[0] {
+	case "user":
+		b.serveUser(w, r, segs[1:])
+	case "users":
+		b.serveUsers(w, r, segs[1:])
+	case "datasets":
+		b.serveEntries(w, r, segs[1:], false)
+	case "projects":
+		b.serveEntries(w, r, segs[1:], true)
+	case "file_download":
+		b.serveFileDownload(w, r, segs[1:])
+	case "download":
+		b.serveDownload(w, r, segs[1:])
+	case "uploads":
+		b.serveUpload(w, r, segs[1:])
+	case "queries":
+		b.serveQueries(w, r, segs[1:])
+	case "sql":
+		b.serveQuery(w, r, segs[1:], "SQL")
+	case "sparql":
+		b.serveQuery(w, r, segs[1:], "SPARQL")
+	case "streams":
+		b.serveStreams(w, r, segs[1:])
+	case "insights":
+		b.serveInsights(w, r, segs[1:])
+	default:
+		notFound(w, path)
+	}
+}
+
+// paginate writes a page of records, honoring the `next` token issued with the previous page.
+func (b *Backend) paginate(w http.ResponseWriter, r *http.Request, records []interface{}) {
+	offset := 0
+	if next := r.URL.Query().Get("next"); next != "" {
+		d, err := base64.RawURLEncoding.DecodeString(next)
+		if err == nil {
+			offset, err = strconv.Atoi(strings.TrimPrefix(string(d), "offset:"))
+		}
+		if err != nil || offset < 0 || offset > len(records) {
+			writeError(w, http.StatusBadRequest, "invalid pagination token %q", next)
+			return
+		}
+	}
+
+	end := len(records)
+	if b.pageSize > 0 && offset+b.pageSize < end {
+		end = offset + b.pageSize
+	}
+	page := paginatedResponse{
+		Count:   len(records),
+		Records: records[offset:end],
+	}
+	if page.Records == nil {
+		page.Records = []interface{}{}
+	}
+	if end < len(records) {
+		page.NextPageToken = base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("offset:%d", end)))
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+func (b *Backend) serveUser(w http.ResponseWriter, r *http.Request, segs []string) {
+	switch {
+	case len(segs) == 0:
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w, r)
+			return
+		}
+		writeJSON(w, http.StatusOK, b.state.User)
+	case len(segs) == 2 && (segs[0] == "datasets" || segs[0] == "projects"):
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w, r)
+			return
+		}
+		records, ok := b.userEntries(segs[0] == "projects", segs[1])
+		if !ok {
+			notFound(w, r.URL.Path)
+			return
+		}
+		b.paginate(w, r, records)
+	case segs[0] == "webhooks":
+		b.serveWebhooks(w, r, segs[1:])
+	default:
+		notFound(w, r.URL.Path)
+	}
+}
+
+func (b *Backend) userEntries(projects bool, relation string) ([]interface{}, bool) {
+	var keys []string
+	switch relation {
+	case "own":
+		for k, e := range b.state.Entries {
+			if e.owner() == b.state.User.ID {
+				keys = append(keys, k)
+			}
+		}
+	case "liked":
+		keys = append(keys, b.state.Liked...)
+	case "contributing":
+		keys = append(keys, b.state.Contributing...)
+	default:
+		return nil, false
+	}
+	sort.Strings(keys)
+
+	records := []interface{}{}
+	for _, k := range keys {
+		e, ok := b.state.Entries[k]
+		if ok && e.IsProject == projects {
+			records = append(records, e.summary())
+		}
+	}
+	return records, true
+}
+
+func (b *Backend) serveUsers(w http.ResponseWriter, r *http.Request, segs []string) {
+	if len(segs) != 1 {
+		notFound(w, r.URL.Path)
+		return
+	}
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, r)
+		return
+	}
+	if segs[0] == b.state.User.ID {
+		writeJSON(w, http.StatusOK, b.state.User)
+		return
+	}
+	u, ok := b.state.Users[segs[0]]
+	if !ok {
+		notFound(w, "user "+segs[0])
+		return
+	}
+	writeJSON(w, http.StatusOK, u)
+}
+
+// lookup finds a dataset or project. Files, syncs and downloads are addressed through the dataset
+// endpoints for both, so `project` may be nil to accept either.
+func (b *Backend) lookup(w http.ResponseWriter, owner, id string, project *bool) (*entry, bool) {
+	e, ok := b.state.Entries[key(owner, id)]
+	if !ok || (project != nil && e.IsProject != *project) {
+		what := "dataset"
+		if project != nil && *project {
+			what = "project"
+		}
+		notFound(w, fmt.Sprintf("%s %s/%s", what, owner, id))
+		return nil, false
+	}
+	return e, true
+}
+
+func (b *Backend) serveEntries(w http.ResponseWriter, r *http.Request, segs []string, project bool) {
+	switch {
+	case len(segs) == 1:
+		if r.Method != http.MethodPost {
+			methodNotAllowed(w, r)
+			return
+		}
+		if project {
+			b.createProject(w, r, segs[0])
+		} else {
+			b.createDataset(w, r, segs[0])
+		}
+		return
+	case len(segs) < 2:
+		notFound(w, r.URL.Path)
+		return
+	}
+
+	owner, id, rest := segs[0], segs[1], segs[2:]
+	if len(rest) == 0 {
+		b.serveEntry(w, r, owner, id, project)
+		return
+	}
+
+	switch rest[0] {
+	case "v":
+		b.serveEntryVersion(w, r, owner, id, project, rest[1:])
+	case "dois":
+		// DOIs contain slashes, which the client doesn't escape.
+		if len(rest) < 2 || project {
+			notFound(w, r.URL.Path)
+			return
+		}
+		b.serveDOI(w, r, owner, id, "", strings.Join(rest[1:], "/"))
+	case "files":
+		b.serveFiles(w, r, owner, id, rest[1:])
+	case "sync":
+		if r.Method != http.MethodGet && r.Method != http.MethodPost {
+			methodNotAllowed(w, r)
+			return
+		}
+		b.sync(w, owner, id)
+	case "queries":
+		b.serveEntryQueries(w, r, owner, id, project, rest[1:])
+	case "linkedDatasets":
+		if len(rest) != 3 || !project {
+			notFound(w, r.URL.Path)
+			return
+		}
+		b.serveLinkedDataset(w, r, owner, id, rest[1], rest[2])
+	default:
+		notFound(w, r.URL.Path)
+	}
+}
+
+func (b *Backend) newFile(req dwapi.FileCreateRequest, now string) *file {
+	src := req.Source
+	return &file{Summary: dwapi.FileSummaryResponse{
+		Created:     now,
+		Description: req.Description,
+		Labels:      req.Labels,
+		Name:        req.Name,
+		Updated:     now,
+		Source: dwapi.FileSourceResponse{
+			Authorization:  src.Authorization,
+			Credentials:    src.Credentials,
+			ExpandArchive:  src.ExpandArchive,
+			Method:         src.Method,
+			OauthToken:     src.OauthToken,
+			RequestEntity:  src.RequestEntity,
+			RequestHeaders: src.RequestHeaders,
+			SyncStatus:     "NEW",
+			URL:            src.URL,
+		},
+	}}
+}
+
+func (b *Backend) putFile(e *entry, name string, content []byte) {
+	now := b.now()
+	f, ok := e.Files[name]
+	if !ok {
+		f = &file{Summary: dwapi.FileSummaryResponse{Name: name, Created: now}}
+		e.Files[name] = f
+	}
+	f.Content = content
+	f.Summary.SizeInBytes = uint(len(content))
+	f.Summary.Updated = now
+}
+
+func (b *Backend) createDataset(w http.ResponseWriter, r *http.Request, owner string) {
+	var req dwapi.DatasetCreateRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	id := slug(req.Title)
+	if id == "" || req.Visibility == "" {
+		writeError(w, http.StatusBadRequest, "title and visibility are required")
+		return
+	}
+	if _, exists := b.state.Entries[key(owner, id)]; exists {
+		writeError(w, http.StatusConflict, "dataset %s/%s already exists", owner, id)
+		return
+	}
+
+	now := b.now()
+	e := &entry{
+		Dataset: &dwapi.DatasetSummaryResponse{
+			AccessLevel: "ADMIN",
+			Created:     now,
+			Description: req.Description,
+			ID:          id,
+			License:     req.License,
+			Owner:       owner,
+			Status:      "LOADED",
+			Summary:     req.Summary,
+			Tags:        req.Tags,
+			Title:       req.Title,
+			Visibility:  req.Visibility,
+		},
+		Files: map[string]*file{},
+	}
+	for _, f := range req.Files {
+		e.Files[f.Name] = b.newFile(f, now)
+	}
+	b.state.Entries[key(owner, id)] = e
+	b.state.touch(e, now)
+	writeJSON(w, http.StatusOK, dwapi.DatasetCreateResponse{
+		Message: "Dataset created successfully.",
+		URI:     fmt.Sprintf("https://data.world/%s/%s", owner, id),
+	})
+}
+
+func (b *Backend) linkedDatasets(w http.ResponseWriter, reqs []dwapi.LinkedDatasetCreateOrUpdateRequest) (
+	[]dwapi.LinkedDatasetSummaryResponse, bool) {
+	var linked []dwapi.LinkedDatasetSummaryResponse
+	for _, l := range reqs {
+		project := false
+		e, ok := b.lookup(w, l.Owner, l.ID, &project)
+		if !ok {
+			return nil, false
+		}
+		linked = append(linked, linkedSummary(e.Dataset))
+	}
+	return linked, true
+}
+
+func linkedSummary(d *dwapi.DatasetSummaryResponse) dwapi.LinkedDatasetSummaryResponse {
+	return dwapi.LinkedDatasetSummaryResponse{
+		AccessLevel: d.AccessLevel,
+		Created:     d.Created,
+		Description: d.Description,
+		ID:          d.ID,
+		License:     d.License,
+		Owner:       d.Owner,
+		Summary:     d.Summary,
+		Tags:        d.Tags,
+		Title:       d.Title,
+		Updated:     d.Updated,
+		Version:     d.Version,
+		Visibility:  d.Visibility,
+	}
+}
+
+func (b *Backend) createProject(w http.ResponseWriter, r *http.Request, owner string) {
+	var req dwapi.ProjectCreateOrUpdateRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	id := slug(req.Title)
+	if id == "" || req.Visibility == "" {
+		writeError(w, http.StatusBadRequest, "title and visibility are required")
+		return
+	}
+	if _, exists := b.state.Entries[key(owner, id)]; exists {
+		writeError(w, http.StatusConflict, "project %s/%s already exists", owner, id)
+		return
+	}
+	linked, ok := b.linkedDatasets(w, req.LinkedDatasets)
+	if !ok {
+		return
+	}
+
+	now := b.now()
+	e := &entry{
+		IsProject: true,
+		Project: &dwapi.ProjectSummaryResponse{
+			AccessLevel:    "ADMIN",
+			Created:        now,
+			ID:             id,
+			License:        req.License,
+			LinkedDatasets: linked,
+			Objective:      req.Objective,
+			Owner:          owner,
+			Status:         "LOADED",
+			Summary:        req.Summary,
+			Tags:           req.Tags,
+			Title:          req.Title,
+			Visibility:     req.Visibility,
+		},
+		Files: map[string]*file{},
+	}
+	for _, f := range req.Files {
+		e.Files[f.Name] = b.newFile(f, now)
+	}
+	b.state.Entries[key(owner, id)] = e
+	b.state.touch(e, now)
+	writeJSON(w, http.StatusOK, dwapi.ProjectCreateResponse{
+		Message: "Project created successfully.",
+		URI:     fmt.Sprintf("https://data.world/%s/%s", owner, id),
+	})
+}
+
+func (b *Backend) serveEntry(w http.ResponseWriter, r *http.Request, owner, id string, project bool) {
+	if r.Method == http.MethodGet && !project {
+		// Projects can be retrieved through the dataset endpoints too.
+		e, ok := b.lookup(w, owner, id, nil)
+		if !ok {
+			return
+		}
+		writeJSON(w, http.StatusOK, e.asDataset())
+		return
+	}
+	if r.Method == http.MethodPut {
+		b.replaceEntry(w, r, owner, id, project)
+		return
+	}
+
+	e, ok := b.lookup(w, owner, id, &project)
+	if !ok {
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, e.summary())
+	case http.MethodPatch:
+		if project {
+			b.updateProject(w, r, e)
+		} else {
+			b.updateDataset(w, r, e)
+		}
+	case http.MethodDelete:
+		b.deleteEntry(owner, id)
+		writeSuccess(w, "Deleted successfully.")
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+func (e *entry) asDataset() dwapi.DatasetSummaryResponse {
+	if !e.IsProject {
+		return e.dataset()
+	}
+	p := e.project()
+	return dwapi.DatasetSummaryResponse{
+		AccessLevel: p.AccessLevel,
+		Created:     p.Created,
+		Files:       p.Files,
+		ID:          p.ID,
+		IsProject:   true,
+		License:     p.License,
+		Owner:       p.Owner,
+		Status:      p.Status,
+		Summary:     p.Summary,
+		Tags:        p.Tags,
+		Title:       p.Title,
+		Updated:     p.Updated,
+		Version:     p.Version,
+		Visibility:  p.Visibility,
+	}
+}
+
+func (b *Backend) deleteEntry(owner, id string) {
+	k := key(owner, id)
+	delete(b.state.Entries, k)
+	for qid, q := range b.state.Queries {
+		if q.Parent == k {
+			delete(b.state.Queries, qid)
+		}
+	}
+	for ik := range b.state.Insights {
+		if strings.HasPrefix(ik, k+"/") {
+			delete(b.state.Insights, ik)
+		}
+	}
+	for sk := range b.state.Streams {
+		if strings.HasPrefix(sk, k+"/") {
+			delete(b.state.Streams, sk)
+		}
+	}
+	b.state.Liked = remove(b.state.Liked, k)
+	b.state.Contributing = remove(b.state.Contributing, k)
+}
+
+func (b *Backend) replaceEntry(w http.ResponseWriter, r *http.Request, owner, id string, project bool) {
+	now := b.now()
+	created := now
+	if e, exists := b.state.Entries[key(owner, id)]; exists {
+		if e.IsProject != project {
+			writeError(w, http.StatusConflict, "%s/%s already exists", owner, id)
+			return
+		}
+		if project {
+			created = e.Project.Created
+		} else {
+			created = e.Dataset.Created
+		}
+	}
+
+	var e *entry
+	if project {
+		var req dwapi.ProjectCreateOrUpdateRequest
+		if !decode(w, r, &req) {
+			return
+		}
+		linked, ok := b.linkedDatasets(w, req.LinkedDatasets)
+		if !ok {
+			return
+		}
+		e = &entry{IsProject: true, Project: &dwapi.ProjectSummaryResponse{
+			AccessLevel:    "ADMIN",
+			Created:        created,
+			ID:             id,
+			License:        req.License,
+			LinkedDatasets: linked,
+			Objective:      req.Objective,
+			Owner:          owner,
+			Status:         "LOADED",
+			Summary:        req.Summary,
+			Tags:           req.Tags,
+			Title:          req.Title,
+			Visibility:     req.Visibility,
+		}, Files: map[string]*file{}}
+		for _, f := range req.Files {
+			e.Files[f.Name] = b.newFile(f, now)
+		}
+	} else {
+		var req dwapi.DatasetReplaceRequest
+		if !decode(w, r, &req) {
+			return
+		}
+		e = &entry{Dataset: &dwapi.DatasetSummaryResponse{
+			AccessLevel: "ADMIN",
+			Created:     created,
+			Description: req.Description,
+			ID:          id,
+			License:     req.License,
+			Owner:       owner,
+			Status:      "LOADED",
+			Summary:     req.Summary,
+			Tags:        req.Tags,
+			Title:       req.Title,
+			Visibility:  req.Visibility,
+		}, Files: map[string]*file{}}
+		for _, f := range req.Files {
+			e.Files[f.Name] = b.newFile(f, now)
+		}
+	}
+	if old, exists := b.state.Entries[key(owner, id)]; exists {
+		e.Versions = old.Versions
+	}
+	b.state.Entries[key(owner, id)] = e
+	b.state.touch(e, now)
+	writeSuccess(w, "Created or replaced successfully.")
+}
+
+func (b *Backend) updateDataset(w http.ResponseWriter, r *http.Request, e *entry) {
+	var req dwapi.DatasetUpdateRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	d := e.Dataset
+	if req.Description != "" {
+		d.Description = req.Description
+	}
+	if req.License != "" {
+		d.License = req.License
+	}
+	if req.Summary != "" {
+		d.Summary = req.Summary
+	}
+	if req.Tags != nil {
+		d.Tags = req.Tags
+	}
+	if req.Title != "" {
+		d.Title = req.Title
+	}
+	if req.Visibility != "" {
+		d.Visibility = req.Visibility
+	}
+	now := b.now()
+	for _, f := range req.Files {
+		e.Files[f.Name] = b.newFile(f, now)
+	}
+	b.state.touch(e, now)
+	writeSuccess(w, "Updated successfully.")
+}
+
+func (b *Backend) updateProject(w http.ResponseWriter, r *http.Request, e *entry) {
+	var req dwapi.ProjectCreateOrUpdateRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	p := e.Project
+	if req.LinkedDatasets != nil {
+		linked, ok := b.linkedDatasets(w, req.LinkedDatasets)
+		if !ok {
+			return
+		}
+		p.LinkedDatasets = linked
+	}
+	if req.License != "" {
+		p.License = req.License
+	}
+	if req.Objective != "" {
+		p.Objective = req.Objective
+	}
+	if req.Summary != "" {
+		p.Summary = req.Summary
+	}
+	if req.Tags != nil {
+		p.Tags = req.Tags
+	}
+	if req.Title != "" {
+		p.Title = req.Title
+	}
+	if req.Visibility != "" {
+		p.Visibility = req.Visibility
+	}
+	now := b.now()
+	for _, f := range req.Files {
+		e.Files[f.Name] = b.newFile(f, now)
+	}
+	b.state.touch(e, now)
+	writeSuccess(w, "Updated successfully.")
+}
+
+func (b *Backend) serveEntryVersion(w http.ResponseWriter, r *http.Request, owner, id string, project bool,
+	segs []string) {
+	if len(segs) == 0 {
+		notFound(w, r.URL.Path)
+		return
+	}
+	if len(segs) >= 3 && segs[1] == "dois" && !project {
+		b.serveDOI(w, r, owner, id, segs[0], strings.Join(segs[2:], "/"))
+		return
+	}
+	if len(segs) != 1 {
+		notFound(w, r.URL.Path)
+		return
+	}
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, r)
+		return
+	}
+	e, ok := b.lookup(w, owner, id, &project)
+	if !ok {
+		return
+	}
+	v, ok := e.Versions[segs[0]]
+	if !ok {
+		notFound(w, "version "+segs[0])
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(v)
+}
+
+func (b *Backend) serveDOI(w http.ResponseWriter, r *http.Request, owner, id, versionid, doi string) {
+	project := false
+	e, ok := b.lookup(w, owner, id, &project)
+	if !ok {
+		return
+	}
+	if versionid != "" {
+		if _, ok := e.Versions[versionid]; !ok {
+			notFound(w, "version "+versionid)
+			return
+		}
+	}
+
+	d := e.Dataset
+	dois := &d.Dois
+	if versionid != "" {
+		dois = &d.VersionDois
+	}
+	switch r.Method {
+	case http.MethodPut:
+		for _, existing := range *dois {
+			if existing.Doi == doi {
+				writeSuccess(w, "DOI already associated.")
+				return
+			}
+		}
+		*dois = append(*dois, dwapi.DigitalObjectIdentifier{Created: b.now(), Doi: doi})
+		writeSuccess(w, "DOI associated successfully.")
+	case http.MethodDelete:
+		var kept []dwapi.DigitalObjectIdentifier
+		for _, existing := range *dois {
+			if existing.Doi != doi {
+				kept = append(kept, existing)
+			}
+		}
+		if len(kept) == len(*dois) {
+			notFound(w, "DOI "+doi)
+			return
+		}
+		*dois = kept
+		writeSuccess(w, "DOI deleted successfully.")
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+func (b *Backend) serveFiles(w http.ResponseWriter, r *http.Request, owner, id string, segs []string) {
+	e, ok := b.lookup(w, owner, id, nil)
+	if !ok {
+		return
+	}
+	switch {
+	case len(segs) == 0 && r.Method == http.MethodPost:
+		var reqs []dwapi.FileCreateRequest
+		if !decode(w, r, &reqs) {
+			return
+		}
+		now := b.now()
+		for _, f := range reqs {
+			if f.Name == "" || f.Source.URL == "" {
+				writeError(w, http.StatusBadRequest, "file name and source URL are required")
+				return
+			}
+			e.Files[f.Name] = b.newFile(f, now)
+		}
+		b.state.touch(e, now)
+		writeSuccess(w, "Files added successfully.")
+	case len(segs) == 1 && r.Method == http.MethodDelete:
+		if _, ok := e.Files[segs[0]]; !ok {
+			notFound(w, "file "+segs[0])
+			return
+		}
+		delete(e.Files, segs[0])
+		b.state.touch(e, b.now())
+		writeSuccess(w, "File deleted successfully.")
+	case len(segs) <= 1:
+		methodNotAllowed(w, r)
+	default:
+		notFound(w, r.URL.Path)
+	}
+}
+
+func (b *Backend) sync(w http.ResponseWriter, owner, id string) {
+	e, ok := b.lookup(w, owner, id, nil)
+	if !ok {
+		return
+	}
+	now := b.now()
+	for _, f := range e.Files {
+		if f.Summary.Source.URL != "" {
+			f.Summary.Source.SyncStatus = "OK"
+			f.Summary.Source.LastSyncStart = now
+			f.Summary.Source.LastSyncSuccess = now
+		}
+	}
+	b.state.touch(e, now)
+	writeSuccess(w, "Sync started.")
+}
+
+func (b *Backend) serveLinkedDataset(w http.ResponseWriter, r *http.Request, owner, projectid, linkedOwner,
+	linkedid string) {
+	project := true
+	e, ok := b.lookup(w, owner, projectid, &project)
+	if !ok {
+		return
+	}
+	p := e.Project
+	switch r.Method {
+	case http.MethodPut:
+		project = false
+		d, ok := b.lookup(w, linkedOwner, linkedid, &project)
+		if !ok {
+			return
+		}
+		for _, l := range p.LinkedDatasets {
+			if l.Owner == linkedOwner && l.ID == linkedid {
+				writeSuccess(w, "Dataset already linked.")
+				return
+			}
+		}
+		p.LinkedDatasets = append(p.LinkedDatasets, linkedSummary(d.Dataset))
+		b.state.touch(e, b.now())
+		writeSuccess(w, "Dataset linked successfully.")
+	case http.MethodDelete:
+		var kept []dwapi.LinkedDatasetSummaryResponse
+		for _, l := range p.LinkedDatasets {
+			if l.Owner != linkedOwner || l.ID != linkedid {
+				kept = append(kept, l)
+			}
+		}
+		if len(kept) == len(p.LinkedDatasets) {
+			notFound(w, fmt.Sprintf("linked dataset %s/%s", linkedOwner, linkedid))
+			return
+		}
+		p.LinkedDatasets = kept
+		b.state.touch(e, b.now())
+		writeSuccess(w, "Dataset unlinked successfully.")
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+func (b *Backend) serveEntryQueries(w http.ResponseWriter, r *http.Request, owner, id string, project bool,
+	segs []string) {
+	e, ok := b.lookup(w, owner, id, &project)
+	if !ok {
+		return
+	}
+	parent := key(e.owner(), e.id())
+
+	if len(segs) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			var ids []string
+			for qid, q := range b.state.Queries {
+				if q.Parent == parent {
+					ids = append(ids, qid)
+				}
+			}
+			sort.Strings(ids)
+			records := []interface{}{}
+			for _, qid := range ids {
+				records = append(records, b.state.Queries[qid].current())
+			}
+			b.paginate(w, r, records)
+		case http.MethodPost:
+			var req dwapi.QueryCreateRequest
+			if !decode(w, r, &req) {
+				return
+			}
+			if req.Name == "" || req.Content == "" || req.Language == "" {
+				writeError(w, http.StatusBadRequest, "name, content and language are required")
+				return
+			}
+			now := b.now()
+			q := dwapi.QuerySummaryResponse{
+				Body:      req.Content,
+				Created:   now,
+				ID:        b.state.nextID(),
+				Language:  strings.ToUpper(req.Language),
+				Name:      req.Name,
+				Owner:     b.state.User.ID,
+				Published: req.Published,
+				Updated:   now,
+				Version:   b.state.nextVersion(),
+			}
+			b.state.Queries[q.ID] = &savedQuery{Parent: parent, Versions: []dwapi.QuerySummaryResponse{q}}
+			writeJSON(w, http.StatusOK, q)
+		default:
+			methodNotAllowed(w, r)
+		}
+		return
+	}
+
+	if len(segs) != 1 {
+		notFound(w, r.URL.Path)
+		return
+	}
+	q, ok := b.state.Queries[segs[0]]
+	if !ok || q.Parent != parent {
+		notFound(w, "query "+segs[0])
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, q.current())
+	case http.MethodPut:
+		var req dwapi.QueryUpdateRequest
+		if !decode(w, r, &req) {
+			return
+		}
+		if req.Name == "" || req.Content == "" {
+			writeError(w, http.StatusBadRequest, "name and content are required")
+			return
+		}
+		next := q.current()
+		next.Name = req.Name
+		next.Body = req.Content
+		next.Published = req.Published
+		next.Updated = b.now()
+		next.Version = b.state.nextVersion()
+		q.Versions = append(q.Versions, next)
+		writeJSON(w, http.StatusOK, next)
+	case http.MethodDelete:
+		delete(b.state.Queries, segs[0])
+		writeSuccess(w, "Query deleted successfully.")
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+func (b *Backend) serveFileDownload(w http.ResponseWriter, r *http.Request, segs []string) {
+	if len(segs) != 3 {
+		notFound(w, r.URL.Path)
+		return
+	}
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, r)
+		return
+	}
+	e, ok := b.lookup(w, segs[0], segs[1], nil)
+	if !ok {
+		return
+	}
+	f, ok := e.Files[segs[2]]
+	if !ok {
+		notFound(w, "file "+segs[2])
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	_, _ = w.Write(f.Content)
+}
+
+func (b *Backend) serveDownload(w http.ResponseWriter, r *http.Request, segs []string) {
+	if len(segs) != 2 {
+		notFound(w, r.URL.Path)
+		return
+	}
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, r)
+		return
+	}
+	e, ok := b.lookup(w, segs[0], segs[1], nil)
+	if !ok {
+		return
+	}
+
+	buf := new(bytes.Buffer)
+	z := zip.NewWriter(buf)
+	for _, f := range e.fileSummaries() {
+		fw, err := z.Create(f.Name)
+		if err == nil {
+			_, err = fw.Write(e.Files[f.Name].Content)
+		}
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "%s", err)
+			return
+		}
+	}
+	if err := z.Close(); err != nil {
+		writeError(w, http.StatusInternalServerError, "%s", err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/zip")
+	_, _ = w.Write(buf.Bytes())
+}
+
+func (b *Backend) serveUpload(w http.ResponseWriter, r *http.Request, segs []string) {
+	if len(segs) != 4 || segs[2] != "files" {
+		notFound(w, r.URL.Path)
+		return
+	}
+	if r.Method != http.MethodPut {
+		methodNotAllowed(w, r)
+		return
+	}
+	e, ok := b.lookup(w, segs[0], segs[1], nil)
+	if !ok {
+		return
+	}
+	content, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+
+	name := segs[3]
+	if r.URL.Query().Get("expandArchive") == "true" && strings.HasSuffix(strings.ToLower(name), ".zip") {
+		z, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid archive: %s", err)
+			return
+		}
+		for _, zf := range z.File {
+			if zf.FileInfo().IsDir() {
+				continue
+			}
+			rc, err := zf.Open()
+			if err != nil {
+				writeError(w, http.StatusBadRequest, "invalid archive: %s", err)
+				return
+			}
+			c, err := ioutil.ReadAll(rc)
+			rc.Close()
+			if err != nil {
+				writeError(w, http.StatusBadRequest, "invalid archive: %s", err)
+				return
+			}
+			b.putFile(e, zf.Name, c)
+		}
+	} else {
+		b.putFile(e, name, content)
+	}
+	b.state.touch(e, b.now())
+	writeSuccess(w, "File uploaded.")
+}
+
+func (b *Backend) serveQueries(w http.ResponseWriter, r *http.Request, segs []string) {
+	if len(segs) == 0 {
+		notFound(w, r.URL.Path)
+		return
+	}
+	q, ok := b.state.Queries[segs[0]]
+	if !ok {
+		notFound(w, "query "+segs[0])
+		return
+	}
+
+	switch {
+	case len(segs) == 1 && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, q.current())
+	case len(segs) == 3 && segs[1] == "v" && r.Method == http.MethodGet:
+		v, ok := q.version(segs[2])
+		if !ok {
+			notFound(w, "version "+segs[2])
+			return
+		}
+		writeJSON(w, http.StatusOK, v)
+	case len(segs) == 2 && segs[1] == "results" && r.Method == http.MethodPost:
+		var req dwapi.SavedQueryExecutionRequest
+		if r.ContentLength != 0 && !decode(w, r, &req) {
+			return
+		}
+		current := q.current()
+		b.writeResult(w, r, current.Language, current.Body)
+	case len(segs) <= 3:
+		methodNotAllowed(w, r)
+	default:
+		notFound(w, r.URL.Path)
+	}
+}
+
+func (b *Backend) serveQuery(w http.ResponseWriter, r *http.Request, segs []string, language string) {
+	if len(segs) != 2 {
+		notFound(w, r.URL.Path)
+		return
+	}
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, r)
+		return
+	}
+	if _, ok := b.lookup(w, segs[0], segs[1], nil); !ok {
+		return
+	}
+	var req struct {
+		Query string `json:"query"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	b.writeResult(w, r, language, req.Query)
+}
+
+// writeResult writes the canned result registered for a query. Without an Accept header, the result
+// registered under the alphabetically first accept type is returned.
+func (b *Backend) writeResult(w http.ResponseWriter, r *http.Request, language, query string) {
+	results, ok := b.state.Results[resultKey(language, query)]
+	if !ok {
+		writeError(w, http.StatusBadRequest, "dwapitest: no result registered for %s query %q", language, query)
+		return
+	}
+
+	accept := r.Header.Get("Accept")
+	if accept == "" {
+		var types []string
+		for t := range results {
+			types = append(types, t)
+		}
+		sort.Strings(types)
+		accept = types[0]
+	}
+	result, ok := results[accept]
+	if !ok {
+		writeError(w, http.StatusNotAcceptable, "dwapitest: no %s result registered for %s query %q",
+			accept, language, query)
+		return
+	}
+	w.Header().Set("Content-Type", accept)
+	_, _ = w.Write(result.Body)
+}
+
+func (b *Backend) serveStreams(w http.ResponseWriter, r *http.Request, segs []string) {
+	if len(segs) < 3 || len(segs) > 4 {
+		notFound(w, r.URL.Path)
+		return
+	}
+	if _, ok := b.lookup(w, segs[0], segs[1], nil); !ok {
+		return
+	}
+	k := key(segs[0], segs[1], segs[2])
+	s := b.state.Streams[k]
+
+	switch {
+	case len(segs) == 3 && r.Method == http.MethodPost:
+		var records []json.RawMessage
+		scanner := bufio.NewScanner(r.Body)
+		scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+		for scanner.Scan() {
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+			if !json.Valid(line) {
+				writeError(w, http.StatusBadRequest, "invalid JSON record %q", line)
+				return
+			}
+			records = append(records, append(json.RawMessage(nil), line...))
+		}
+		if err := scanner.Err(); err != nil {
+			writeError(w, http.StatusBadRequest, "%s", err)
+			return
+		}
+		if s == nil {
+			s = &stream{}
+			b.state.Streams[k] = s
+		}
+		s.Records = append(s.Records, records...)
+		w.WriteHeader(http.StatusAccepted)
+	case len(segs) == 4 && segs[3] == "records" && r.Method == http.MethodDelete:
+		if s == nil {
+			notFound(w, "stream "+segs[2])
+			return
+		}
+		s.Records = nil
+		writeSuccess(w, "Records deleted successfully.")
+	case len(segs) == 4 && segs[3] == "schema" && r.Method == http.MethodGet:
+		if s == nil {
+			notFound(w, "stream "+segs[2])
+			return
+		}
+		writeJSON(w, http.StatusOK, s.Schema)
+	case len(segs) == 4 && segs[3] == "schema" && r.Method == http.MethodPatch:
+		var req dwapi.StreamSchemaUpdateRequest
+		if !decode(w, r, &req) {
+			return
+		}
+		if s == nil {
+			s = &stream{}
+			b.state.Streams[k] = s
+		}
+		s.Schema = dwapi.StreamSchema{
+			PrimaryKeyFields: req.PrimaryKeyFields,
+			SequenceField:    req.SequenceField,
+		}
+		if req.UpdateMethod == "TRUNCATED" {
+			s.Records = nil
+		}
+		writeSuccess(w, "Schema updated successfully.")
+	case len(segs) == 3 || segs[3] == "records" || segs[3] == "schema":
+		methodNotAllowed(w, r)
+	default:
+		notFound(w, r.URL.Path)
+	}
+}
+
+func (b *Backend) serveInsights(w http.ResponseWriter, r *http.Request, segs []string) {
+	if len(segs) < 2 {
+		notFound(w, r.URL.Path)
+		return
+	}
+	owner, projectid := segs[0], segs[1]
+	project := true
+	if _, ok := b.lookup(w, owner, projectid, &project); !ok {
+		return
+	}
+
+	if len(segs) == 2 {
+		switch r.Method {
+		case http.MethodGet:
+			var keys []string
+			prefix := key(owner, projectid) + "/"
+			for k := range b.state.Insights {
+				if strings.HasPrefix(k, prefix) {
+					keys = append(keys, k)
+				}
+			}
+			sort.Strings(keys)
+			records := []interface{}{}
+			for _, k := range keys {
+				records = append(records, b.state.Insights[k].current())
+			}
+			b.paginate(w, r, records)
+		case http.MethodPost:
+			var req dwapi.InsightCreateRequest
+			if !decode(w, r, &req) {
+				return
+			}
+			if req.Title == "" {
+				writeError(w, http.StatusBadRequest, "title is required")
+				return
+			}
+			now := b.now()
+			i := dwapi.InsightSummaryResponse{
+				Author:          b.state.User.ID,
+				Body:            req.Body,
+				Created:         now,
+				DataSourceLinks: req.DataSourceLinks,
+				Description:     req.Description,
+				ID:              b.state.nextID(),
+				SourceLink:      req.SourceLink,
+				Thumbnail:       req.Thumbnail,
+				Title:           req.Title,
+				Updated:         now,
+				Version:         b.state.nextVersion(),
+			}
+			b.state.Insights[key(owner, projectid, i.ID)] = &insight{Versions: []dwapi.InsightSummaryResponse{i}}
+			writeJSON(w, http.StatusOK, dwapi.InsightCreateResponse{
+				Message: "Insight created successfully.",
+				URI:     fmt.Sprintf("https://data.world/%s/%s/insights/%s", owner, projectid, i.ID),
+			})
+		default:
+			methodNotAllowed(w, r)
+		}
+		return
+	}
+
+	k := key(owner, projectid, segs[2])
+	i, ok := b.state.Insights[k]
+	if !ok {
+		notFound(w, "insight "+segs[2])
+		return
+	}
+	if len(segs) > 3 && (len(segs) != 5 || segs[3] != "v") {
+		notFound(w, r.URL.Path)
+		return
+	}
+	if len(segs) == 5 {
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w, r)
+			return
+		}
+		v, ok := i.version(segs[4])
+		if !ok {
+			notFound(w, "version "+segs[4])
+			return
+		}
+		writeJSON(w, http.StatusOK, v)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, i.current())
+	case http.MethodPut:
+		var req dwapi.InsightReplaceRequest
+		if !decode(w, r, &req) {
+			return
+		}
+		current := i.current()
+		next := dwapi.InsightSummaryResponse{
+			Author:          current.Author,
+			Body:            req.Body,
+			Created:         current.Created,
+			DataSourceLinks: req.DataSourceLinks,
+			Description:     req.Description,
+			ID:              current.ID,
+			SourceLink:      req.SourceLink,
+			Thumbnail:       req.Thumbnail,
+			Title:           req.Title,
+			Updated:         b.now(),
+			Version:         b.state.nextVersion(),
+		}
+		i.Versions = append(i.Versions, next)
+		writeSuccess(w, "Insight replaced successfully.")
+	case http.MethodPatch:
+		var req dwapi.InsightUpdateRequest
+		if !decode(w, r, &req) {
+			return
+		}
+		next := i.current()
+		if req.Body != (dwapi.InsightBody{}) {
+			next.Body = req.Body
+		}
+		if req.DataSourceLinks != nil {
+			next.DataSourceLinks = req.DataSourceLinks
+		}
+		if req.Description != "" {
+			next.Description = req.Description
+		}
+		if req.SourceLink != "" {
+			next.SourceLink = req.SourceLink
+		}
+		if req.Thumbnail != "" {
+			next.Thumbnail = req.Thumbnail
+		}
+		if req.Title != "" {
+			next.Title = req.Title
+		}
+		next.Updated = b.now()
+		next.Version = b.state.nextVersion()
+		i.Versions = append(i.Versions, next)
+		writeSuccess(w, "Insight updated successfully.")
+	case http.MethodDelete:
+		delete(b.state.Insights, k)
+		writeSuccess(w, "Insight deleted successfully.")
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+func (b *Backend) subscriptions() []dwapi.Subscription {
+	var keys []string
+	for k := range b.state.Webhooks {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var subs []dwapi.Subscription
+	for _, k := range keys {
+		subs = append(subs, b.state.Webhooks[k])
+	}
+	return subs
+}
+
+func (b *Backend) serveWebhooks(w http.ResponseWriter, r *http.Request, segs []string) {
+	if len(segs) == 0 {
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w, r)
+			return
+		}
+		records := []interface{}{}
+		for _, s := range b.subscriptions() {
+			records = append(records, s)
+		}
+		b.paginate(w, r, records)
+		return
+	}
+
+	var sub dwapi.Subscription
+	switch {
+	case len(segs) == 2 && segs[0] == "users":
+		sub.User = dwapi.UserIdentifier{ID: segs[1]}
+	case len(segs) == 3 && (segs[0] == "datasets" || segs[0] == "projects"):
+		project := segs[0] == "projects"
+		if _, ok := b.lookup(w, segs[1], segs[2], &project); !ok {
+			return
+		}
+		id := dwapi.DatasetOrProjectIdentifier{Owner: segs[1], ID: segs[2]}
+		if project {
+			sub.Project = id
+		} else {
+			sub.Dataset = id
+		}
+	default:
+		notFound(w, r.URL.Path)
+		return
+	}
+
+	k := strings.Join(segs, "/")
+	switch r.Method {
+	case http.MethodGet:
+		existing, ok := b.state.Webhooks[k]
+		if !ok {
+			notFound(w, "subscription "+k)
+			return
+		}
+		writeJSON(w, http.StatusOK, existing)
+	case http.MethodPut:
+		var req dwapi.SubscriptionCreateRequest
+		if !decode(w, r, &req) {
+			return
+		}
+		if len(req.Events) == 0 {
+			writeError(w, http.StatusBadRequest, "events are required")
+			return
+		}
+		sub.Events = req.Events
+		b.state.Webhooks[k] = sub
+		writeSuccess(w, "Subscribed successfully.")
+	case http.MethodDelete:
+		if _, ok := b.state.Webhooks[k]; !ok {
+			notFound(w, "subscription "+k)
+			return
+		}
+		delete(b.state.Webhooks, k)
+		writeSuccess(w, "Unsubscribed successfully.")
+	default:
+		methodNotAllowed(w, r)
+	}
+}
@@ -0,0 +1,230 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package dwapitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/datadotworld/dwapi-go/dwapi"
+)
+
+// store holds the complete state of a Backend. Every field is exported so the state can be
+// serialized as JSON.
+type store struct {
+	Counter      int                                `json:"counter"`
+	User         dwapi.UserInfoResponse             `json:"user"`
+	Users        map[string]dwapi.UserInfoResponse  `json:"users"`
+	Entries      map[string]*entry                  `json:"entries"`
+	Liked        []string                           `json:"liked,omitempty"`
+	Contributing []string                           `json:"contributing,omitempty"`
+	Queries      map[string]*savedQuery             `json:"queries"`
+	Insights     map[string]*insight                `json:"insights"`
+	Streams      map[string]*stream                 `json:"streams"`
+	Webhooks     map[string]dwapi.Subscription      `json:"webhooks"`
+	Results      map[string]map[string]cannedResult `json:"results"`
+}
+
+// entry is a dataset or a project. data.world addresses files, syncs and downloads of both through
+// the same endpoints, so they share a single namespace of owner/id keys.
+type entry struct {
+	IsProject bool                          `json:"isProject"`
+	Dataset   *dwapi.DatasetSummaryResponse `json:"dataset,omitempty"`
+	Project   *dwapi.ProjectSummaryResponse `json:"project,omitempty"`
+	Files     map[string]*file              `json:"files"`
+	Versions  map[string]json.RawMessage    `json:"versions"`
+}
+
+type file struct {
+	Summary dwapi.FileSummaryResponse `json:"summary"`
+	Content []byte                    `json:"content,omitempty"`
+}
+
+type savedQuery struct {
+	Parent   string                       `json:"parent"`
+	Versions []dwapi.QuerySummaryResponse `json:"versions"`
+}
+
+type insight struct {
+	Versions []dwapi.InsightSummaryResponse `json:"versions"`
+}
+
+type stream struct {
+	Schema  dwapi.StreamSchema `json:"schema"`
+	Records []json.RawMessage  `json:"records,omitempty"`
+}
+
+type cannedResult struct {
+	Body []byte `json:"body"`
+}
+
+func newStore() *store {
+	return &store{
+		User: dwapi.UserInfoResponse{
+			ID:          "dwapitest",
+			DisplayName: "dwapitest",
+		},
+		Users:    map[string]dwapi.UserInfoResponse{},
+		Entries:  map[string]*entry{},
+		Queries:  map[string]*savedQuery{},
+		Insights: map[string]*insight{},
+		Streams:  map[string]*stream{},
+		Webhooks: map[string]dwapi.Subscription{},
+		Results:  map[string]map[string]cannedResult{},
+	}
+}
+
+func key(parts ...string) string {
+	return strings.Join(parts, "/")
+}
+
+func resultKey(language, query string) string {
+	return strings.ToUpper(language) + "\x00" + strings.TrimSpace(query)
+}
+
+var nonIdentifier = regexp.MustCompile(`[^a-z0-9]+`)
+
+// slug derives an id from a title the way data.world does for new datasets and projects.
+func slug(title string) string {
+	return strings.Trim(nonIdentifier.ReplaceAllString(strings.ToLower(title), "-"), "-")
+}
+
+// nextVersion returns a new opaque version identifier.
+func (s *store) nextVersion() string {
+	s.Counter++
+	return fmt.Sprintf("v%d", s.Counter)
+}
+
+// nextID returns a new identifier shaped like the UUIDs data.world uses for queries and insights.
+func (s *store) nextID() string {
+	s.Counter++
+	return fmt.Sprintf("00000000-0000-4000-8000-%012d", s.Counter)
+}
+
+func (e *entry) owner() string {
+	if e.IsProject {
+		return e.Project.Owner
+	}
+	return e.Dataset.Owner
+}
+
+func (e *entry) id() string {
+	if e.IsProject {
+		return e.Project.ID
+	}
+	return e.Dataset.ID
+}
+
+func (e *entry) fileSummaries() []dwapi.FileSummaryResponse {
+	var names []string
+	for name := range e.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var files []dwapi.FileSummaryResponse
+	for _, name := range names {
+		files = append(files, e.Files[name].Summary)
+	}
+	return files
+}
+
+func (e *entry) dataset() dwapi.DatasetSummaryResponse {
+	d := *e.Dataset
+	d.Files = e.fileSummaries()
+	return d
+}
+
+func (e *entry) project() dwapi.ProjectSummaryResponse {
+	p := *e.Project
+	p.Files = e.fileSummaries()
+	return p
+}
+
+func (e *entry) summary() interface{} {
+	if e.IsProject {
+		return e.project()
+	}
+	return e.dataset()
+}
+
+// touch records a change to the entry: it assigns a new version and keeps a copy of the summary so
+// that it can be retrieved by version later on.
+func (s *store) touch(e *entry, now string) {
+	version := s.nextVersion()
+	if e.IsProject {
+		e.Project.Updated = now
+		e.Project.Version = version
+	} else {
+		e.Dataset.Updated = now
+		e.Dataset.Version = version
+	}
+	if e.Versions == nil {
+		e.Versions = map[string]json.RawMessage{}
+	}
+	b, _ := json.Marshal(e.summary())
+	e.Versions[version] = b
+}
+
+func (q *savedQuery) current() dwapi.QuerySummaryResponse {
+	return q.Versions[len(q.Versions)-1]
+}
+
+func (q *savedQuery) version(versionid string) (dwapi.QuerySummaryResponse, bool) {
+	for _, v := range q.Versions {
+		if v.Version == versionid {
+			return v, true
+		}
+	}
+	return dwapi.QuerySummaryResponse{}, false
+}
+
+func (i *insight) current() dwapi.InsightSummaryResponse {
+	return i.Versions[len(i.Versions)-1]
+}
+
+func (i *insight) version(versionid string) (dwapi.InsightSummaryResponse, bool) {
+	for _, v := range i.Versions {
+		if v.Version == versionid {
+			return v, true
+		}
+	}
+	return dwapi.InsightSummaryResponse{}, false
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func remove(list []string, s string) []string {
+	var out []string
+	for _, v := range list {
+		if v != s {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,304 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package dwsql
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/datadotworld/dwapi-go/dwapi"
+)
+
+// Ident quotes a table or column name as a data.world SQL identifier. Names are always quoted with
+// backticks, with any backtick in the name doubled, so reserved words such as "order" are safe as
+// they are. Tables are named as data.world normalizes the names of uploaded files, which TableName
+// does, not by the file names themselves.
+func Ident(name string) string {
+	return dwapi.SQLIdent(name)
+}
+
+// Column quotes a column name qualified by a table name or alias, for use in expressions.
+func Column(table, column string) string {
+	return Ident(table) + "." + Ident(column)
+}
+
+// TableName returns the name of the table of an uploaded file, as data.world normalizes it: without
+// its extension, and the extension before a compression one such as .gz, in lower case, and with
+// every run of characters other than letters and digits replaced by an underscore, e.g. sales_2018
+// for "Sales 2018.csv".
+func TableName(filename string) string {
+	name := strings.ToLower(filename)
+	ext := path.Ext(name)
+	name = strings.TrimSuffix(name, ext)
+	switch ext {
+	case ".gz", ".bz2", ".zip":
+		name = strings.TrimSuffix(name, path.Ext(name))
+	}
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, "_")
+}
+
+// Builder builds a SELECT query. Table and column names passed to it are quoted with Ident, while
+// expressions and conditions are written as they are, with ? placeholders for their arguments.
+// Methods record the first error, which is returned by SQL and Request.
+//
+//	req, err := dwsql.From("sales_2018").Alias("s").
+//		Select("region").
+//		SelectExpr("SUM("+dwsql.Column("s", "total")+") AS total").
+//		Where(dwsql.Column("s", "year")+" >= ?", 2017).
+//		GroupBy("region").
+//		OrderByDesc("total").
+//		Limit(10).
+//		Request()
+//	resp, err := dw.Query.ExecuteSQL("my-username", "my-awesome-dataset", "text/csv", req)
+type Builder struct {
+	distinct      bool
+	columns       []string
+	table, alias  string
+	joins         []string
+	where, having []string
+	groupBy       []string
+	orderBy       []string
+	limit, offset int
+	err           error
+
+	// Arguments are kept by clause, so they follow the order of the placeholders in the query
+	// whatever order the methods are called in.
+	columnArgs, joinArgs, whereArgs, havingArgs []interface{}
+}
+
+// From starts a query of a table.
+func From(table string) *Builder {
+	b := &Builder{table: table, limit: -1}
+	if table == "" {
+		b.err = errors.New("dwsql: no table to select from")
+	}
+	return b
+}
+
+// Alias names the table of the FROM clause.
+func (b *Builder) Alias(alias string) *Builder {
+	b.alias = alias
+	return b
+}
+
+// Distinct selects only distinct rows.
+func (b *Builder) Distinct() *Builder {
+	b.distinct = true
+	return b
+}
+
+// Select adds columns by name. Without columns, all columns are selected.
+func (b *Builder) Select(columns ...string) *Builder {
+	for _, c := range columns {
+		b.columns = append(b.columns, Ident(c))
+	}
+	return b
+}
+
+// SelectExpr adds an expression, such as an aggregate or a computed column.
+func (b *Builder) SelectExpr(expr string, args ...interface{}) *Builder {
+	if b.bind(&b.columnArgs, expr, args) {
+		b.columns = append(b.columns, expr)
+	}
+	return b
+}
+
+// Join adds an inner join of a table, with an optional alias.
+func (b *Builder) Join(table, alias, on string, args ...interface{}) *Builder {
+	return b.join("JOIN", table, alias, on, args)
+}
+
+// LeftJoin adds a left outer join of a table, with an optional alias.
+func (b *Builder) LeftJoin(table, alias, on string, args ...interface{}) *Builder {
+	return b.join("LEFT JOIN", table, alias, on, args)
+}
+
+func (b *Builder) join(kind, table, alias, on string, args []interface{}) *Builder {
+	if on == "" {
+		b.fail(fmt.Errorf("dwsql: join of %s has no condition", Ident(table)))
+		return b
+	}
+	if b.bind(&b.joinArgs, on, args) {
+		b.joins = append(b.joins, kind+" "+tableRef(table, alias)+" ON "+on)
+	}
+	return b
+}
+
+// Where adds a condition. Conditions are combined with AND.
+func (b *Builder) Where(cond string, args ...interface{}) *Builder {
+	if b.bind(&b.whereArgs, cond, args) {
+		b.where = append(b.where, cond)
+	}
+	return b
+}
+
+// WhereEq adds the condition that a column equals a value.
+func (b *Builder) WhereEq(column string, value interface{}) *Builder {
+	return b.Where(Ident(column)+" = ?", value)
+}
+
+// WhereIn adds the condition that a column equals one of the values. With no values, no rows match.
+func (b *Builder) WhereIn(column string, values ...interface{}) *Builder {
+	if len(values) == 0 {
+		return b.Where("1 = 0")
+	}
+	return b.Where(Ident(column)+" IN ("+strings.Repeat(", ?", len(values))[2:]+")", values...)
+}
+
+// GroupBy adds grouping columns by name.
+func (b *Builder) GroupBy(columns ...string) *Builder {
+	for _, c := range columns {
+		b.groupBy = append(b.groupBy, Ident(c))
+	}
+	return b
+}
+
+// Having adds a condition on groups. Conditions are combined with AND.
+func (b *Builder) Having(cond string, args ...interface{}) *Builder {
+	if b.bind(&b.havingArgs, cond, args) {
+		b.having = append(b.having, cond)
+	}
+	return b
+}
+
+// OrderBy adds columns to sort by in ascending order.
+func (b *Builder) OrderBy(columns ...string) *Builder {
+	for _, c := range columns {
+		b.orderBy = append(b.orderBy, Ident(c))
+	}
+	return b
+}
+
+// OrderByDesc adds a column to sort by in descending order.
+func (b *Builder) OrderByDesc(column string) *Builder {
+	b.orderBy = append(b.orderBy, Ident(column)+" DESC")
+	return b
+}
+
+// Limit limits the number of rows.
+func (b *Builder) Limit(n int) *Builder {
+	if n < 0 {
+		b.fail(fmt.Errorf("dwsql: invalid limit %d", n))
+	}
+	b.limit = n
+	return b
+}
+
+// Offset skips rows.
+func (b *Builder) Offset(n int) *Builder {
+	if n < 0 {
+		b.fail(fmt.Errorf("dwsql: invalid offset %d", n))
+	}
+	b.offset = n
+	return b
+}
+
+// SQL returns the query and its arguments, in the order of its placeholders.
+func (b *Builder) SQL() (string, []interface{}, error) {
+	if b.err != nil {
+		return "", nil, b.err
+	}
+	var sb strings.Builder
+	sb.WriteString("SELECT ")
+	if b.distinct {
+		sb.WriteString("DISTINCT ")
+	}
+	if len(b.columns) == 0 {
+		sb.WriteString("*")
+	} else {
+		sb.WriteString(strings.Join(b.columns, ", "))
+	}
+	sb.WriteString(" FROM " + tableRef(b.table, b.alias))
+	for _, j := range b.joins {
+		sb.WriteString(" " + j)
+	}
+	if len(b.where) > 0 {
+		sb.WriteString(" WHERE " + conjunction(b.where))
+	}
+	if len(b.groupBy) > 0 {
+		sb.WriteString(" GROUP BY " + strings.Join(b.groupBy, ", "))
+	}
+	if len(b.having) > 0 {
+		sb.WriteString(" HAVING " + conjunction(b.having))
+	}
+	if len(b.orderBy) > 0 {
+		sb.WriteString(" ORDER BY " + strings.Join(b.orderBy, ", "))
+	}
+	if b.limit >= 0 {
+		sb.WriteString(" LIMIT " + strconv.Itoa(b.limit))
+	}
+	if b.offset > 0 {
+		sb.WriteString(" OFFSET " + strconv.Itoa(b.offset))
+	}
+	var args []interface{}
+	for _, a := range [][]interface{}{b.columnArgs, b.joinArgs, b.whereArgs, b.havingArgs} {
+		args = append(args, a...)
+	}
+	return sb.String(), args, nil
+}
+
+// Request returns the query as a request for QueryService.ExecuteSQL, with its arguments bound as
+// parameters.
+func (b *Builder) Request() (*dwapi.SQLQueryRequest, error) {
+	query, args, err := b.SQL()
+	if err != nil {
+		return nil, err
+	}
+	return dwapi.NewSQLQueryRequest(query, args...)
+}
+
+// bind adds the arguments of a fragment to those of its clause, checking that they match its
+// placeholders.
+func (b *Builder) bind(clause *[]interface{}, fragment string, args []interface{}) bool {
+	if b.err != nil {
+		return false
+	}
+	if n := dwapi.CountPlaceholders(fragment); n != len(args) {
+		b.fail(fmt.Errorf("dwsql: %q expects %d arguments, got %d", fragment, n, len(args)))
+		return false
+	}
+	*clause = append(*clause, args...)
+	return true
+}
+
+func (b *Builder) fail(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+func tableRef(table, alias string) string {
+	if alias == "" {
+		return Ident(table)
+	}
+	return Ident(table) + " AS " + Ident(alias)
+}
+
+func conjunction(conds []string) string {
+	if len(conds) == 1 {
+		return conds[0]
+	}
+	return "(" + strings.Join(conds, ") AND (") + ")"
+}
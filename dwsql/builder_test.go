@@ -0,0 +1,109 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package dwsql
+
+import (
+	"testing"
+
+	"github.com/datadotworld/dwapi-go/dwapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdent(t *testing.T) {
+	assert.Equal(t, "`sales 2018-Q1`", Ident("sales 2018-Q1"))
+	assert.Equal(t, "`order`", Ident("order"))
+	assert.Equal(t, "`a``b`", Ident("a`b"))
+	assert.Equal(t, "`s`.`total`", Column("s", "total"))
+}
+
+func TestTableName(t *testing.T) {
+	tests := map[string]string{
+		"sales 2018.csv":       "sales_2018",
+		"Sales 2018 (Q1).xlsx": "sales_2018_q1",
+		"orders.csv.gz":        "orders",
+		"--weird__name--.json": "weird_name",
+		"v1.2 results.csv":     "v1_2_results",
+		"no_extension":         "no_extension",
+		"Café Menu.tsv":        "café_menu",
+	}
+	for filename, want := range tests {
+		assert.Equal(t, want, TableName(filename), filename)
+	}
+}
+
+func TestBuilder(t *testing.T) {
+	query, args, err := From("sales_2018").Alias("s").
+		Where(Column("s", "year")+" >= ?", 2017).
+		LeftJoin("regions", "r", Column("s", "region_id")+" = "+Column("r", "id")+" AND "+Column("r", "active")+" = ?", true).
+		Select("region").
+		SelectExpr("SUM("+Column("s", "total")+") AS total").
+		WhereIn("channel", "web", "store").
+		GroupBy("region").
+		Having("SUM("+Column("s", "total")+") > ?", 100).
+		OrderByDesc("total").
+		OrderBy("region").
+		Limit(10).
+		Offset(20).
+		SQL()
+	if assert.NoError(t, err) {
+		assert.Equal(t, "SELECT `region`, SUM(`s`.`total`) AS total FROM `sales_2018` AS `s`"+
+			" LEFT JOIN `regions` AS `r` ON `s`.`region_id` = `r`.`id` AND `r`.`active` = ?"+
+			" WHERE (`s`.`year` >= ?) AND (`channel` IN (?, ?))"+
+			" GROUP BY `region` HAVING SUM(`s`.`total`) > ?"+
+			" ORDER BY `total` DESC, `region` LIMIT 10 OFFSET 20", query)
+		assert.Equal(t, []interface{}{true, 2017, "web", "store", 100}, args)
+	}
+
+	query, args, err = From("people").Distinct().Join("visits", "", "`people`.`id` = `visits`.`person`").SQL()
+	if assert.NoError(t, err) {
+		assert.Equal(t, "SELECT DISTINCT * FROM `people` JOIN `visits` ON `people`.`id` = `visits`.`person`", query)
+		assert.Empty(t, args)
+	}
+
+	query, _, err = From("people").WhereIn("id").Limit(0).SQL()
+	if assert.NoError(t, err) {
+		assert.Equal(t, "SELECT * FROM `people` WHERE 1 = 0 LIMIT 0", query)
+	}
+}
+
+func TestBuilder_Request(t *testing.T) {
+	req, err := From("people").Select("name").WhereEq("region", "EMEA").Request()
+	if assert.NoError(t, err) {
+		assert.Equal(t, &dwapi.SQLQueryRequest{
+			Query:      "SELECT `name` FROM `people` WHERE `region` = ?",
+			Parameters: map[string]string{"$data_world_param0": `"EMEA"`},
+		}, req)
+	}
+}
+
+func TestBuilder_Errors(t *testing.T) {
+	tests := map[*Builder]string{
+		From(""):                               "dwsql: no table to select from",
+		From("people").Where("id = ?"):         `dwsql: "id = ?" expects 1 arguments, got 0`,
+		From("people").Join("visits", "v", ""): "dwsql: join of `visits` has no condition",
+		From("people").Limit(-1):               "dwsql: invalid limit -1",
+		From("people").Offset(-5).Limit(-1):    "dwsql: invalid offset -5",
+	}
+	for b, want := range tests {
+		_, err := b.Request()
+		assert.EqualError(t, err, want)
+	}
+
+	_, err := From("people").WhereEq("id", struct{}{}).Request()
+	assert.EqualError(t, err, "dwapi: parameter 1: unsupported type struct {}")
+}
@@ -0,0 +1,176 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package dwsql
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/datadotworld/dwapi-go/dwapi"
+)
+
+// resultType is requested for the table schema that types the columns.
+const resultType = "application/json"
+
+type conn struct {
+	query     dwapi.QueryAPI
+	owner, id string
+}
+
+var (
+	_ driver.QueryerContext     = (*conn)(nil)
+	_ driver.ExecerContext      = (*conn)(nil)
+	_ driver.ConnPrepareContext = (*conn)(nil)
+	_ driver.ConnBeginTx        = (*conn)(nil)
+)
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	return &stmt{conn: c, query: query, inputs: dwapi.CountPlaceholders(query)}, nil
+}
+
+func (c *conn) Close() error {
+	return nil
+}
+
+func (c *conn) Begin() (driver.Tx, error) {
+	return nil, ErrNotSupported
+}
+
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	return nil, ErrNotSupported
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return nil, ErrNotSupported
+}
+
+// QueryContext runs the query. The API call itself can't be cancelled, so the context is only checked
+// before it's made.
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	params, err := parameters(args)
+	if err != nil {
+		return nil, err
+	}
+	if n := dwapi.CountPlaceholders(query); n != len(args) {
+		return nil, fmt.Errorf("dwsql: expected %d arguments, got %d", n, len(args))
+	}
+	r, err := c.query.ExecuteSQLRows(c.owner, c.id, resultType, &dwapi.SQLQueryRequest{
+		Query:              query,
+		Parameters:         params,
+		IncludeTableSchema: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &rows{rows: r}, nil
+}
+
+type stmt struct {
+	conn   *conn
+	query  string
+	inputs int
+}
+
+var (
+	_ driver.StmtQueryContext = (*stmt)(nil)
+	_ driver.StmtExecContext  = (*stmt)(nil)
+)
+
+func (s *stmt) Close() error {
+	return nil
+}
+
+func (s *stmt) NumInput() int {
+	return s.inputs
+}
+
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, ErrNotSupported
+}
+
+func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return nil, ErrNotSupported
+}
+
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	named := make([]driver.NamedValue, len(args))
+	for i, v := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: v}
+	}
+	return s.QueryContext(context.Background(), named)
+}
+
+func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return s.conn.QueryContext(ctx, s.query, args)
+}
+
+type rows struct {
+	rows *dwapi.Rows
+}
+
+var (
+	_ driver.RowsColumnTypeDatabaseTypeName = (*rows)(nil)
+	_ driver.RowsColumnTypeScanType         = (*rows)(nil)
+)
+
+func (r *rows) Columns() []string {
+	return r.rows.Columns()
+}
+
+func (r *rows) Close() error {
+	return r.rows.Close()
+}
+
+func (r *rows) Next(dest []driver.Value) error {
+	if !r.rows.Next() {
+		if err := r.rows.Err(); err != nil {
+			return err
+		}
+		return io.EOF
+	}
+	values := make([]interface{}, len(dest))
+	ptrs := make([]interface{}, len(dest))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	if err := r.rows.Scan(ptrs...); err != nil {
+		return err
+	}
+	for i, v := range values {
+		dest[i] = v
+	}
+	return nil
+}
+
+func (r *rows) ColumnTypeDatabaseTypeName(index int) string {
+	return r.rows.ColumnTypes()[index].DatabaseTypeName()
+}
+
+func (r *rows) ColumnTypeScanType(index int) reflect.Type {
+	return r.rows.ColumnTypes()[index].ScanType()
+}
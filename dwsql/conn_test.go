@@ -0,0 +1,162 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package dwsql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/datadotworld/dwapi-go/dwapi"
+	"github.com/stretchr/testify/assert"
+)
+
+const schemaResults = `[
+	{"fields": [
+		{"name": "name", "type": "string"},
+		{"name": "visits", "type": "integer"},
+		{"name": "score", "type": "number"},
+		{"name": "active", "type": "boolean"},
+		{"name": "joined", "type": "datetime"}
+	]},
+	{"name": "Ada", "visits": 3, "score": 1.5, "active": true, "joined": "2018-08-03T15:56:41Z"},
+	{"name": "Grace", "visits": null, "score": 2, "active": false, "joined": null}
+]`
+
+func openDB(t *testing.T, handler http.HandlerFunc) (*sql.DB, func()) {
+	srv := httptest.NewServer(handler)
+	dw := dwapi.NewClient("secret")
+	dw.BaseURL = srv.URL
+	db := sql.OpenDB(NewConnector(dw, "my-username", "my-awesome-dataset"))
+	return db, func() {
+		db.Close()
+		srv.Close()
+	}
+}
+
+func TestQuery(t *testing.T) {
+	var got dwapi.SQLQueryRequest
+	db, done := openDB(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/sql/my-username/my-awesome-dataset", r.URL.Path)
+		assert.Equal(t, resultType, r.Header.Get("Accept"))
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		fmt.Fprint(w, schemaResults)
+	})
+	defer done()
+
+	rows, err := db.Query("SELECT * FROM people WHERE region = ? AND visits > ?", "EMEA", 2)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer rows.Close()
+	assert.Equal(t, dwapi.SQLQueryRequest{
+		Query: "SELECT * FROM people WHERE region = ? AND visits > ?",
+		Parameters: map[string]string{
+			"$data_world_param0": `"EMEA"`,
+			"$data_world_param1": `"2"^^<http://www.w3.org/2001/XMLSchema#integer>`,
+		},
+		IncludeTableSchema: true,
+	}, got)
+
+	types, err := rows.ColumnTypes()
+	if assert.NoError(t, err) {
+		var names []string
+		var scanTypes []reflect.Type
+		for _, ct := range types {
+			names = append(names, ct.DatabaseTypeName())
+			scanTypes = append(scanTypes, ct.ScanType())
+		}
+		assert.Equal(t, []string{"xsd:string", "xsd:integer", "xsd:decimal", "xsd:boolean", "xsd:dateTime"}, names)
+		assert.Equal(t, []reflect.Type{reflect.TypeOf(""), reflect.TypeOf(int64(0)), reflect.TypeOf(0.0),
+			reflect.TypeOf(false), reflect.TypeOf(time.Time{})}, scanTypes)
+	}
+
+	var (
+		name   string
+		visits sql.NullInt64
+		score  float64
+		active bool
+		joined *time.Time
+	)
+	assert.True(t, rows.Next())
+	if assert.NoError(t, rows.Scan(&name, &visits, &score, &active, &joined)) {
+		assert.Equal(t, "Ada", name)
+		assert.Equal(t, sql.NullInt64{Int64: 3, Valid: true}, visits)
+		assert.Equal(t, 1.5, score)
+		assert.True(t, active)
+		assert.Equal(t, time.Date(2018, 8, 3, 15, 56, 41, 0, time.UTC), *joined)
+	}
+	assert.True(t, rows.Next())
+	if assert.NoError(t, rows.Scan(&name, &visits, &score, &active, &joined)) {
+		assert.Equal(t, "Grace", name)
+		assert.False(t, visits.Valid)
+		assert.Equal(t, 2.0, score)
+		assert.Nil(t, joined)
+	}
+	assert.False(t, rows.Next())
+	assert.NoError(t, rows.Err())
+}
+
+func TestQuery_Errors(t *testing.T) {
+	db, done := openDB(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message": "bad query"}`, http.StatusBadRequest)
+	})
+	defer done()
+
+	_, err := db.Query("SELECT")
+	assert.EqualError(t, err, "400 Bad Request")
+
+	_, err = db.Query("SELECT * FROM people WHERE id = ?")
+	assert.EqualError(t, err, "dwsql: expected 1 arguments, got 0")
+
+	_, err = db.Query("SELECT * FROM people WHERE id = @id", sql.Named("id", 1))
+	assert.EqualError(t, err, `dwsql: named parameter "id" is not supported, use ?`)
+
+	_, err = db.Query("SELECT * FROM people WHERE id = ?", nil)
+	assert.EqualError(t, err, "dwsql: parameter 1: NULL can't be a parameter")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = db.QueryContext(ctx, "SELECT")
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestNotSupported(t *testing.T) {
+	db, done := openDB(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s", r.URL)
+	})
+	defer done()
+
+	_, err := db.Exec("DELETE FROM people")
+	assert.Equal(t, ErrNotSupported, err)
+	_, err = db.Begin()
+	assert.Equal(t, ErrNotSupported, err)
+
+	s, err := db.Prepare("DELETE FROM people WHERE id = ?")
+	if assert.NoError(t, err) {
+		_, err = s.Exec(1)
+		assert.Equal(t, ErrNotSupported, err)
+		s.Close()
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+/*
+Package dwsql is a database/sql driver that runs SQL queries against a data.world dataset or project.
+
+	import _ "github.com/datadotworld/dwapi-go/dwsql"
+
+	db, err := sql.Open("dataworld", "dataworld://my-username/my-awesome-dataset?token="+token)
+	rows, err := db.Query("SELECT name, total FROM sales WHERE region = ?", "EMEA")
+
+The DSN names the owner and id of the dataset or project. Its query parameters are:
+
+	token    the API token; $DW_AUTH_TOKEN if not set
+	baseurl  the base URL of the API, e.g. http://localhost:8080/v0; as for dwapi.NewClient if not set
+
+Queries are run with `QueryService.ExecuteSQL`, with positional ? parameters sent as query parameters
+rather than interpolated into the query. Column types come from the table schema of the results.
+data.world queries are read-only: Exec and transactions fail with ErrNotSupported.
+
+NewConnector connects through an existing *dwapi.Client instead, for use with sql.OpenDB.
+
+From builds SELECT queries with quoted identifiers and bound arguments, for the driver or for
+QueryService.ExecuteSQL.
+*/
+package dwsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/datadotworld/dwapi-go/dwapi"
+)
+
+// DriverName is the name the driver is registered under.
+const DriverName = "dataworld"
+
+// ErrNotSupported is returned for statements and transactions that data.world queries can't run.
+var ErrNotSupported = errors.New("dwsql: not supported by data.world queries")
+
+func init() {
+	sql.Register(DriverName, &Driver{})
+}
+
+// Driver is the database/sql driver for data.world.
+type Driver struct{}
+
+// Open returns a connection for the DSN.
+func (d *Driver) Open(dsn string) (driver.Conn, error) {
+	c, err := d.OpenConnector(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return c.Connect(context.Background())
+}
+
+// OpenConnector parses the DSN once for all connections of a sql.DB.
+func (d *Driver) OpenConnector(dsn string) (driver.Connector, error) {
+	cfg, err := ParseDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+	client := dwapi.NewClient(cfg.Token)
+	if cfg.BaseURL != "" {
+		client.BaseURL = strings.TrimSuffix(cfg.BaseURL, "/")
+	}
+	return &connector{client: client, owner: cfg.Owner, id: cfg.ID, driver: d}, nil
+}
+
+// Config is a parsed DSN.
+type Config struct {
+	Owner   string
+	ID      string
+	Token   string
+	BaseURL string
+}
+
+// ParseDSN parses a DSN of the form dataworld://owner/id?token=...&baseurl=...
+func ParseDSN(dsn string) (*Config, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("dwsql: invalid DSN: %s", err)
+	}
+	if u.Scheme != DriverName {
+		return nil, fmt.Errorf("dwsql: invalid DSN %q: the scheme must be %s://", dsn, DriverName)
+	}
+	cfg := &Config{
+		Owner:   u.Host,
+		ID:      strings.Trim(u.Path, "/"),
+		Token:   u.Query().Get("token"),
+		BaseURL: u.Query().Get("baseurl"),
+	}
+	if cfg.Owner == "" || cfg.ID == "" || strings.Contains(cfg.ID, "/") {
+		return nil, fmt.Errorf("dwsql: invalid DSN %q: expected %s://owner/id", dsn, DriverName)
+	}
+	if cfg.Token == "" {
+		cfg.Token = os.Getenv("DW_AUTH_TOKEN")
+	}
+	return cfg, nil
+}
+
+// NewConnector returns a connector that queries the dataset or project through client.
+//
+//	db := sql.OpenDB(dwsql.NewConnector(dw, "my-username", "my-awesome-dataset"))
+func NewConnector(client *dwapi.Client, owner, id string) driver.Connector {
+	return &connector{client: client, owner: owner, id: id, driver: &Driver{}}
+}
+
+type connector struct {
+	client    *dwapi.Client
+	owner, id string
+	driver    *Driver
+}
+
+func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return &conn{query: c.client.Query, owner: c.owner, id: c.id}, nil
+}
+
+func (c *connector) Driver() driver.Driver {
+	return c.driver
+}
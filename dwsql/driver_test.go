@@ -0,0 +1,78 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package dwsql
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+
+	"github.com/datadotworld/dwapi-go/dwapi"
+	"github.com/datadotworld/dwapi-go/dwapitest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDSN(t *testing.T) {
+	cfg, err := ParseDSN("dataworld://my-username/my-awesome-dataset?token=secret&baseurl=http://localhost:8080/v0")
+	if assert.NoError(t, err) {
+		assert.Equal(t, &Config{
+			Owner:   "my-username",
+			ID:      "my-awesome-dataset",
+			Token:   "secret",
+			BaseURL: "http://localhost:8080/v0",
+		}, cfg)
+	}
+
+	_ = os.Setenv("DW_AUTH_TOKEN", "env.token")
+	defer os.Unsetenv("DW_AUTH_TOKEN")
+	cfg, err = ParseDSN("dataworld://my-username/my-awesome-dataset")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "env.token", cfg.Token)
+	}
+
+	for dsn, want := range map[string]string{
+		"postgres://my-username/my-awesome-dataset": `dwsql: invalid DSN "postgres://my-username/my-awesome-dataset": the scheme must be dataworld://`,
+		"dataworld://my-username":                   `dwsql: invalid DSN "dataworld://my-username": expected dataworld://owner/id`,
+		"dataworld://my-username/a/b":               `dwsql: invalid DSN "dataworld://my-username/a/b": expected dataworld://owner/id`,
+	} {
+		_, err = ParseDSN(dsn)
+		assert.EqualError(t, err, want)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	srv := dwapitest.NewServer()
+	defer srv.Close()
+	srv.AddDataset(dwapi.DatasetSummaryResponse{Owner: "my-username", ID: "my-awesome-dataset"})
+	srv.SetSQLResult("SELECT name FROM people", resultType, []byte(`[{"name": "Ada"}]`))
+
+	db, err := sql.Open(DriverName, "dataworld://my-username/my-awesome-dataset?token=secret&baseurl="+srv.URL)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer db.Close()
+
+	var name string
+	if assert.NoError(t, db.QueryRow("SELECT name FROM people").Scan(&name)) {
+		assert.Equal(t, "Ada", name)
+	}
+	assert.Equal(t, "/sql/my-username/my-awesome-dataset", srv.Requests()[0].Path)
+
+	_, err = sql.Open(DriverName, "dataworld://my-username")
+	assert.EqualError(t, err, `dwsql: invalid DSN "dataworld://my-username": expected dataworld://owner/id`)
+}
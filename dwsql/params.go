@@ -0,0 +1,46 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package dwsql
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+
+	"github.com/datadotworld/dwapi-go/dwapi"
+)
+
+// parameters names the positional arguments $data_world_param0, $data_world_param1, ... as the API
+// expects, with their values written as RDF literals.
+func parameters(args []driver.NamedValue) (map[string]string, error) {
+	if len(args) == 0 {
+		return nil, nil
+	}
+	params := make(map[string]string, len(args))
+	for _, a := range args {
+		if a.Name != "" {
+			return nil, fmt.Errorf("dwsql: named parameter %q is not supported, use ?", a.Name)
+		}
+		lit, err := dwapi.FormatParameter(a.Value)
+		if err != nil {
+			return nil, fmt.Errorf("dwsql: parameter %d: %s", a.Ordinal, err)
+		}
+		params["$data_world_param"+strconv.Itoa(a.Ordinal-1)] = lit
+	}
+	return params, nil
+}
@@ -0,0 +1,41 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package dwsql
+
+import (
+	"database/sql/driver"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParameters(t *testing.T) {
+	params, err := parameters([]driver.NamedValue{{Ordinal: 1, Value: "EMEA"}, {Ordinal: 2, Value: int64(3)}})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"$data_world_param0": `"EMEA"`,
+		"$data_world_param1": `"3"^^<http://www.w3.org/2001/XMLSchema#integer>`,
+	}, params)
+
+	params, err = parameters(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, params)
+
+	_, err = parameters([]driver.NamedValue{{Ordinal: 1, Value: struct{}{}}})
+	assert.EqualError(t, err, "dwsql: parameter 1: unsupported type struct {}")
+}
@@ -0,0 +1,344 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+/*
+Package querysync keeps the saved queries of a dataset or project in step with a directory of query
+files. Each `.sql`, `.rq` or `.sparql` file is a saved query named after the file, without its
+extension.
+
+	target := querysync.Target{Owner: "my-username", ID: "my-awesome-dataset"}
+	plan, err := querysync.NewPlan(dw.Query, target, "queries", &querysync.Options{Delete: true})
+	fmt.Print(plan)
+	err = querysync.Apply(dw.Query, plan)
+
+Export writes the saved queries of a dataset or project to such a directory.
+*/
+package querysync
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/datadotworld/dwapi-go/dwapi"
+)
+
+// The languages of saved queries.
+const (
+	SQL    = "SQL"
+	SPARQL = "SPARQL"
+)
+
+var extensions = map[string]string{
+	".sql":    SQL,
+	".rq":     SPARQL,
+	".sparql": SPARQL,
+}
+
+// Target is the dataset or project whose saved queries are synced.
+type Target struct {
+	Owner   string
+	ID      string
+	Project bool
+}
+
+func (t Target) String() string {
+	return t.Owner + "/" + t.ID
+}
+
+func (t Target) list(q dwapi.QueryAPI) ([]dwapi.QuerySummaryResponse, error) {
+	if t.Project {
+		return q.ListQueriesAssociatedWithProject(t.Owner, t.ID)
+	}
+	return q.ListQueriesAssociatedWithDataset(t.Owner, t.ID)
+}
+
+func (t Target) create(q dwapi.QueryAPI, body *dwapi.QueryCreateRequest) (dwapi.QuerySummaryResponse, error) {
+	if t.Project {
+		return q.CreateSavedQueryInProject(t.Owner, t.ID, body)
+	}
+	return q.CreateSavedQueryInDataset(t.Owner, t.ID, body)
+}
+
+func (t Target) update(q dwapi.QueryAPI, queryid string, body *dwapi.QueryUpdateRequest) error {
+	var err error
+	if t.Project {
+		_, err = q.UpdateSavedQueryInProject(t.Owner, t.ID, queryid, body)
+	} else {
+		_, err = q.UpdateSavedQueryInDataset(t.Owner, t.ID, queryid, body)
+	}
+	return err
+}
+
+func (t Target) delete(q dwapi.QueryAPI, queryid string) error {
+	var err error
+	if t.Project {
+		_, err = q.DeleteSavedQueryInProject(t.Owner, t.ID, queryid)
+	} else {
+		_, err = q.DeleteSavedQueryInDataset(t.Owner, t.ID, queryid)
+	}
+	return err
+}
+
+// File is a query file.
+type File struct {
+	Name     string
+	Language string
+	Path     string
+	Content  string
+}
+
+// ReadDir reads the query files in dir. Other files and subdirectories are ignored, and two files
+// with the same name, such as `sales.sql` and `sales.rq`, are an error.
+func ReadDir(dir string) ([]File, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []File
+	seen := map[string]string{}
+	for _, info := range infos {
+		ext := filepath.Ext(info.Name())
+		language, ok := extensions[strings.ToLower(ext)]
+		if !ok || info.IsDir() {
+			continue
+		}
+		name := strings.TrimSuffix(info.Name(), ext)
+		if other, ok := seen[name]; ok {
+			return nil, fmt.Errorf("querysync: %s and %s are both named %q", other, info.Name(), name)
+		}
+		seen[name] = info.Name()
+		path := filepath.Join(dir, info.Name())
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, File{Name: name, Language: language, Path: path, Content: normalize(string(content))})
+	}
+	return files, nil
+}
+
+// normalize trims the surrounding whitespace of a query, so that a trailing newline in a file doesn't
+// count as a change.
+func normalize(content string) string {
+	return strings.TrimSpace(content)
+}
+
+// Kind is the kind of an Action.
+type Kind int
+
+const (
+	// Create creates a saved query for a new file.
+	Create Kind = iota
+	// Update changes the content or the published state of a saved query.
+	Update
+	// Replace creates a saved query in another language and then deletes the old one, as the language
+	// of a query can't be updated. The query gets a new id, and so a new link.
+	Replace
+	// Delete deletes a saved query that has no file.
+	Delete
+)
+
+var kindSymbols = map[Kind]string{Create: "+", Update: "~", Replace: "-/+", Delete: "-"}
+
+var kindNames = map[Kind]string{Create: "create", Update: "update", Replace: "replace", Delete: "delete"}
+
+func (k Kind) String() string {
+	return kindNames[k]
+}
+
+// Action is a change to one saved query.
+type Action struct {
+	Kind     Kind
+	Name     string
+	Language string
+	// Path is the file of the query; empty for deletes.
+	Path string
+	// QueryID is the id of the saved query; empty for creates.
+	QueryID string
+	Content string
+	// Published is whether the query is published once created or updated.
+	Published bool
+}
+
+func (a Action) String() string {
+	if a.Kind == Delete {
+		return fmt.Sprintf("%-3s %s %s (%s)", kindSymbols[a.Kind], a.Kind, a.Name, a.QueryID)
+	}
+	return fmt.Sprintf("%-3s %s %s (%s, %s)", kindSymbols[a.Kind], a.Kind, a.Name, a.Language, a.Path)
+}
+
+// Plan is the list of changes that bring the saved queries of a target in line with a directory.
+type Plan struct {
+	Target  Target
+	Actions []Action
+}
+
+// Empty reports whether the saved queries are already in sync.
+func (p *Plan) Empty() bool {
+	return len(p.Actions) == 0
+}
+
+// String lists the actions one per line.
+func (p *Plan) String() string {
+	if p.Empty() {
+		return fmt.Sprintf("%s: saved queries are up to date\n", p.Target)
+	}
+	var b strings.Builder
+	for _, a := range p.Actions {
+		b.WriteString(a.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Options configures NewPlan.
+type Options struct {
+	// Delete deletes the saved queries that have no file. They are kept by default.
+	Delete bool
+	// Published, if set, publishes or unpublishes the queries that are created or updated, and updates
+	// those whose state differs. Otherwise new queries are unpublished and saved queries keep their
+	// state.
+	Published *bool
+}
+
+// NewPlan compares the query files in dir with the saved queries of target and returns the changes
+// needed to sync them. Saved queries are matched to files by name, so two saved queries of the same
+// name are an error. Options may be nil.
+func NewPlan(q dwapi.QueryAPI, target Target, dir string, options *Options) (*Plan, error) {
+	if options == nil {
+		options = &Options{}
+	}
+	files, err := ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	saved, err := target.list(q)
+	if err != nil {
+		return nil, err
+	}
+	remote := make(map[string]dwapi.QuerySummaryResponse, len(saved))
+	for _, s := range saved {
+		if _, ok := remote[s.Name]; ok {
+			return nil, fmt.Errorf("querysync: %s has more than one saved query named %q", target, s.Name)
+		}
+		remote[s.Name] = s
+	}
+
+	plan := &Plan{Target: target}
+	for _, f := range files {
+		a := Action{Name: f.Name, Language: f.Language, Path: f.Path, Content: f.Content}
+		s, ok := remote[f.Name]
+		delete(remote, f.Name)
+		a.Published = s.Published
+		if options.Published != nil {
+			a.Published = *options.Published
+		}
+		switch {
+		case !ok:
+			a.Kind = Create
+		case !strings.EqualFold(s.Language, f.Language):
+			a.Kind, a.QueryID = Replace, s.ID
+		case normalize(s.Body) != f.Content || a.Published != s.Published:
+			a.Kind, a.QueryID = Update, s.ID
+		default:
+			continue
+		}
+		plan.Actions = append(plan.Actions, a)
+	}
+	if options.Delete {
+		for _, s := range remote {
+			plan.Actions = append(plan.Actions,
+				Action{Kind: Delete, Name: s.Name, Language: strings.ToUpper(s.Language), QueryID: s.ID})
+		}
+	}
+	sort.Slice(plan.Actions, func(i, j int) bool {
+		return plan.Actions[i].Name < plan.Actions[j].Name
+	})
+	return plan, nil
+}
+
+// Apply carries out the actions of a plan in order, stopping at the first that fails. A replace
+// whose old query can't be deleted once the new one is created leaves both, and says so.
+func Apply(q dwapi.QueryAPI, plan *Plan) error {
+	t := plan.Target
+	for _, a := range plan.Actions {
+		var err error
+		switch a.Kind {
+		case Create:
+			_, err = t.create(q, a.createRequest())
+		case Update:
+			err = t.update(q, a.QueryID, &dwapi.QueryUpdateRequest{Name: a.Name, Content: a.Content,
+				Published: a.Published})
+		case Replace:
+			var created dwapi.QuerySummaryResponse
+			if created, err = t.create(q, a.createRequest()); err == nil {
+				if err = t.delete(q, a.QueryID); err != nil {
+					err = fmt.Errorf("created %s but couldn't delete %s, so both exist: %v", created.ID, a.QueryID, err)
+				}
+			}
+		case Delete:
+			err = t.delete(q, a.QueryID)
+		}
+		if err != nil {
+			return fmt.Errorf("querysync: %s %s: %v", a.Kind, a.Name, err)
+		}
+	}
+	return nil
+}
+
+func (a Action) createRequest() *dwapi.QueryCreateRequest {
+	return &dwapi.QueryCreateRequest{Name: a.Name, Content: a.Content, Language: a.Language, Published: a.Published}
+}
+
+// Export writes each saved query of target to dir, which is created if needed, as `<name>.sql` or
+// `<name>.rq`, and returns the paths written. Existing files are overwritten. Names that can't be
+// file names are an error, and nothing is written.
+func Export(q dwapi.QueryAPI, target Target, dir string) ([]string, error) {
+	saved, err := target.list(q)
+	if err != nil {
+		return nil, err
+	}
+	paths := make([]string, len(saved))
+	seen := map[string]bool{}
+	for i, s := range saved {
+		if s.Name == "" || s.Name == "." || s.Name == ".." || strings.ContainsAny(s.Name, `/\`+"\x00") {
+			return nil, fmt.Errorf("querysync: saved query %q can't be written to a file", s.Name)
+		}
+		ext := ".sql"
+		if strings.EqualFold(s.Language, SPARQL) {
+			ext = ".rq"
+		}
+		if seen[s.Name] {
+			return nil, fmt.Errorf("querysync: %s has more than one saved query named %q", target, s.Name)
+		}
+		seen[s.Name] = true
+		paths[i] = filepath.Join(dir, s.Name+ext)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	for i, s := range saved {
+		if err := ioutil.WriteFile(paths[i], []byte(normalize(s.Body)+"\n"), 0644); err != nil {
+			return paths[:i], err
+		}
+	}
+	return paths, nil
+}
@@ -0,0 +1,239 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package querysync
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/datadotworld/dwapi-go/dwapi"
+	"github.com/datadotworld/dwapi-go/dwapimock"
+	"github.com/datadotworld/dwapi-go/dwapitest"
+	"github.com/stretchr/testify/assert"
+)
+
+var target = Target{Owner: "acme", ID: "sales"}
+
+func newServer() (*dwapitest.Server, *dwapi.Client) {
+	srv := dwapitest.NewServer()
+	srv.AddDataset(dwapi.DatasetSummaryResponse{Owner: "acme", ID: "sales"})
+	return srv, srv.NewClient()
+}
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "querysync")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	for name, content := range files {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	return dir
+}
+
+func savedQueries(t *testing.T, dw *dwapi.Client) map[string]dwapi.QuerySummaryResponse {
+	saved, err := dw.Query.ListQueriesAssociatedWithDataset("acme", "sales")
+	assert.NoError(t, err)
+	byName := map[string]dwapi.QuerySummaryResponse{}
+	for _, s := range saved {
+		byName[s.Name] = s
+	}
+	return byName
+}
+
+func TestReadDir(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"totals.sql":     "SELECT SUM(total) FROM sales\n",
+		"labels.rq":      "SELECT ?s ?l WHERE { ?s rdfs:label ?l }",
+		"classes.SPARQL": "SELECT DISTINCT ?c WHERE { ?s a ?c }",
+		"README.md":      "queries",
+	})
+	defer os.RemoveAll(dir)
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "old.sql"), 0755))
+
+	files, err := ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, []File{
+		{Name: "classes", Language: SPARQL, Path: filepath.Join(dir, "classes.SPARQL"),
+			Content: "SELECT DISTINCT ?c WHERE { ?s a ?c }"},
+		{Name: "labels", Language: SPARQL, Path: filepath.Join(dir, "labels.rq"),
+			Content: "SELECT ?s ?l WHERE { ?s rdfs:label ?l }"},
+		{Name: "totals", Language: SQL, Path: filepath.Join(dir, "totals.sql"), Content: "SELECT SUM(total) FROM sales"},
+	}, files)
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "totals.rq"), []byte("ASK {}"), 0644))
+	_, err = ReadDir(dir)
+	assert.EqualError(t, err, `querysync: totals.rq and totals.sql are both named "totals"`)
+}
+
+func TestNewPlan(t *testing.T) {
+	srv, dw := newServer()
+	defer srv.Close()
+	srv.AddSavedQuery("acme", "sales", dwapi.QuerySummaryResponse{Name: "current", Body: "SELECT 1"})
+	changed, _ := srv.AddSavedQuery("acme", "sales", dwapi.QuerySummaryResponse{Name: "changed", Body: "SELECT 1"})
+	language, _ := srv.AddSavedQuery("acme", "sales", dwapi.QuerySummaryResponse{Name: "language", Body: "SELECT 1"})
+	stale, _ := srv.AddSavedQuery("acme", "sales", dwapi.QuerySummaryResponse{Name: "stale", Body: "SELECT 1"})
+	dir := writeFiles(t, map[string]string{
+		"current.sql": "SELECT 1\n",
+		"changed.sql": "SELECT 2\n",
+		"language.rq": "ASK {}\n",
+		"new.sql":     "SELECT 3\n",
+	})
+	defer os.RemoveAll(dir)
+
+	plan, err := NewPlan(dw.Query, target, dir, nil)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []Action{
+			{Kind: Update, Name: "changed", Language: SQL, Path: filepath.Join(dir, "changed.sql"), QueryID: changed.ID,
+				Content: "SELECT 2"},
+			{Kind: Replace, Name: "language", Language: SPARQL, Path: filepath.Join(dir, "language.rq"),
+				QueryID: language.ID, Content: "ASK {}"},
+			{Kind: Create, Name: "new", Language: SQL, Path: filepath.Join(dir, "new.sql"), Content: "SELECT 3"},
+		}, plan.Actions)
+	}
+
+	plan, err = NewPlan(dw.Query, target, dir, &Options{Delete: true})
+	if assert.NoError(t, err) && assert.Len(t, plan.Actions, 4) {
+		assert.Equal(t, Action{Kind: Delete, Name: "stale", Language: SQL, QueryID: stale.ID}, plan.Actions[3])
+		assert.Equal(t, "~   update changed (SQL, "+filepath.Join(dir, "changed.sql")+")\n"+
+			"-/+ replace language (SPARQL, "+filepath.Join(dir, "language.rq")+")\n"+
+			"+   create new (SQL, "+filepath.Join(dir, "new.sql")+")\n"+
+			"-   delete stale ("+stale.ID+")\n", plan.String())
+	}
+
+	srv.AddSavedQuery("acme", "sales", dwapi.QuerySummaryResponse{Name: "stale", Body: "SELECT 4"})
+	_, err = NewPlan(dw.Query, target, dir, nil)
+	assert.EqualError(t, err, `querysync: acme/sales has more than one saved query named "stale"`)
+}
+
+func TestApply(t *testing.T) {
+	srv, dw := newServer()
+	defer srv.Close()
+	srv.AddSavedQuery("acme", "sales", dwapi.QuerySummaryResponse{Name: "changed", Body: "SELECT 1", Published: true})
+	language, _ := srv.AddSavedQuery("acme", "sales", dwapi.QuerySummaryResponse{Name: "language", Body: "SELECT 1",
+		Published: true})
+	srv.AddSavedQuery("acme", "sales", dwapi.QuerySummaryResponse{Name: "stale", Body: "SELECT 1"})
+	dir := writeFiles(t, map[string]string{
+		"changed.sql": "SELECT 2\n",
+		"language.rq": "ASK {}\n",
+		"new.sql":     "SELECT 3\n",
+	})
+	defer os.RemoveAll(dir)
+
+	plan, err := NewPlan(dw.Query, target, dir, &Options{Delete: true})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, Apply(dw.Query, plan))
+
+	saved := savedQueries(t, dw)
+	var names []string
+	for name := range saved {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	assert.Equal(t, []string{"changed", "language", "new"}, names)
+	assert.Equal(t, "SELECT 2", saved["changed"].Body)
+	assert.Equal(t, "SPARQL", saved["language"].Language)
+	assert.Equal(t, "ASK {}", saved["language"].Body)
+	assert.NotEqual(t, language.ID, saved["language"].ID)
+	assert.Equal(t, "SQL", saved["new"].Language)
+	// Queries keep their published state without the Published option.
+	assert.True(t, saved["changed"].Published)
+	assert.True(t, saved["language"].Published)
+	assert.False(t, saved["new"].Published)
+
+	plan, err = NewPlan(dw.Query, target, dir, &Options{Delete: true})
+	if assert.NoError(t, err) {
+		assert.True(t, plan.Empty())
+		assert.Equal(t, "acme/sales: saved queries are up to date\n", plan.String())
+	}
+
+	// The Published option publishes or unpublishes them.
+	published := false
+	plan, err = NewPlan(dw.Query, target, dir, &Options{Published: &published})
+	if assert.NoError(t, err) && assert.Len(t, plan.Actions, 2) {
+		assert.Equal(t, []string{"changed", "language"}, []string{plan.Actions[0].Name, plan.Actions[1].Name})
+		assert.NoError(t, Apply(dw.Query, plan))
+		saved = savedQueries(t, dw)
+		assert.False(t, saved["changed"].Published)
+		assert.False(t, saved["language"].Published)
+	}
+
+	err = Apply(dw.Query, &Plan{Target: Target{Owner: "acme", ID: "missing"},
+		Actions: []Action{{Kind: Create, Name: "q", Language: SQL, Content: "SELECT 1"}}})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "querysync: create q: ")
+	}
+}
+
+func TestApply_ReplaceCreatesFirst(t *testing.T) {
+	q := &dwapimock.QueryAPI{
+		CreateSavedQueryInDatasetFunc: func(owner, datasetid string, body *dwapi.QueryCreateRequest) (
+			dwapi.QuerySummaryResponse, error) {
+			return dwapi.QuerySummaryResponse{ID: "new-id", Name: body.Name}, nil
+		},
+		DeleteSavedQueryInDatasetFunc: func(owner, datasetid, queryid string) (dwapi.SuccessResponse, error) {
+			return dwapi.SuccessResponse{}, errors.New("500 Internal Server Error")
+		},
+	}
+	err := Apply(q, &Plan{Target: target, Actions: []Action{
+		{Kind: Replace, Name: "q", Language: SPARQL, QueryID: "old-id", Content: "ASK {}"},
+	}})
+	assert.EqualError(t, err,
+		"querysync: replace q: created new-id but couldn't delete old-id, so both exist: 500 Internal Server Error")
+	calls := q.Calls()
+	if assert.Len(t, calls, 2) {
+		assert.Equal(t, "CreateSavedQueryInDataset", calls[0].Method)
+		assert.Equal(t, "DeleteSavedQueryInDataset", calls[1].Method)
+	}
+}
+
+func TestExport(t *testing.T) {
+	srv, dw := newServer()
+	defer srv.Close()
+	srv.AddSavedQuery("acme", "sales", dwapi.QuerySummaryResponse{Name: "totals", Body: "SELECT SUM(total) FROM sales"})
+	srv.AddSavedQuery("acme", "sales", dwapi.QuerySummaryResponse{Name: "labels", Language: "SPARQL",
+		Body: "SELECT ?s ?l WHERE { ?s rdfs:label ?l }"})
+	dir := writeFiles(t, nil)
+	defer os.RemoveAll(dir)
+	out := filepath.Join(dir, "queries")
+
+	paths, err := Export(dw.Query, target, out)
+	if assert.NoError(t, err) {
+		sort.Strings(paths)
+		assert.Equal(t, []string{filepath.Join(out, "labels.rq"), filepath.Join(out, "totals.sql")}, paths)
+		content, err := ioutil.ReadFile(filepath.Join(out, "totals.sql"))
+		assert.NoError(t, err)
+		assert.Equal(t, "SELECT SUM(total) FROM sales\n", string(content))
+	}
+
+	// The exported files are in sync.
+	plan, err := NewPlan(dw.Query, target, out, &Options{Delete: true})
+	if assert.NoError(t, err) {
+		assert.True(t, plan.Empty(), plan.String())
+	}
+
+	srv.AddSavedQuery("acme", "sales", dwapi.QuerySummaryResponse{Name: "by region/year", Body: "SELECT 1"})
+	_, err = Export(dw.Query, target, out)
+	assert.EqualError(t, err, `querysync: saved query "by region/year" can't be written to a file`)
+}
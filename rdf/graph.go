@@ -0,0 +1,207 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package rdf
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// RDFType is the rdf:type predicate, written "a" in Turtle.
+const RDFType IRI = RDF + "type"
+
+// Triple is a statement of a graph.
+type Triple struct {
+	Subject   Term
+	Predicate Term
+	Object    Term
+}
+
+// String returns the triple as a line of N-Triples, without the line break.
+func (t Triple) String() string {
+	return t.Subject.String() + " " + t.Predicate.String() + " " + t.Object.String() + " ."
+}
+
+// Quad is a triple in a named graph, or in the default graph if Graph is nil.
+type Quad struct {
+	Triple
+	Graph Term
+}
+
+// String returns the quad as a line of N-Quads, without the line break.
+func (q Quad) String() string {
+	if q.Graph == nil {
+		return q.Triple.String()
+	}
+	return q.Subject.String() + " " + q.Predicate.String() + " " + q.Object.String() + " " + q.Graph.String() + " ."
+}
+
+// Graph is a small in-memory set of triples, kept in the order they were added, with the prefixes
+// used to abbreviate its IRIs. The zero value is not usable; call NewGraph.
+type Graph struct {
+	triples  []Triple
+	set      map[Triple]bool
+	prefixes map[string]IRI
+}
+
+// NewGraph returns an empty graph.
+func NewGraph() *Graph {
+	return &Graph{
+		set:      make(map[Triple]bool),
+		prefixes: make(map[string]IRI),
+	}
+}
+
+// Add adds triples to the graph, ignoring those it already has.
+func (g *Graph) Add(triples ...Triple) {
+	for _, t := range triples {
+		if !g.set[t] {
+			g.set[t] = true
+			g.triples = append(g.triples, t)
+		}
+	}
+}
+
+// Remove removes a triple from the graph, reporting whether it had it.
+func (g *Graph) Remove(t Triple) bool {
+	if !g.set[t] {
+		return false
+	}
+	delete(g.set, t)
+	for i, u := range g.triples {
+		if u == t {
+			g.triples = append(g.triples[:i], g.triples[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// Has reports whether the graph has a triple.
+func (g *Graph) Has(t Triple) bool {
+	return g.set[t]
+}
+
+// Len returns the number of triples of the graph.
+func (g *Graph) Len() int {
+	return len(g.triples)
+}
+
+// Triples returns the triples of the graph in the order they were added.
+func (g *Graph) Triples() []Triple {
+	return append([]Triple(nil), g.triples...)
+}
+
+// Match returns the triples with the given subject, predicate and object, in the order they were
+// added. A nil term matches any term.
+func (g *Graph) Match(subject, predicate, object Term) []Triple {
+	var matches []Triple
+	for _, t := range g.triples {
+		if (subject == nil || t.Subject == subject) && (predicate == nil || t.Predicate == predicate) &&
+			(object == nil || t.Object == object) {
+			matches = append(matches, t)
+		}
+	}
+	return matches
+}
+
+// Objects returns the objects of the triples with the given subject and predicate.
+func (g *Graph) Objects(subject, predicate Term) []Term {
+	var objects []Term
+	for _, t := range g.Match(subject, predicate, nil) {
+		objects = append(objects, t.Object)
+	}
+	return objects
+}
+
+// Subjects returns the distinct subjects of the triples with the given predicate and object.
+func (g *Graph) Subjects(predicate, object Term) []Term {
+	var subjects []Term
+	seen := make(map[Term]bool)
+	for _, t := range g.Match(nil, predicate, object) {
+		if !seen[t.Subject] {
+			seen[t.Subject] = true
+			subjects = append(subjects, t.Subject)
+		}
+	}
+	return subjects
+}
+
+// SetPrefix binds a prefix to a namespace, replacing any previous binding.
+func (g *Graph) SetPrefix(prefix string, namespace IRI) {
+	g.prefixes[prefix] = namespace
+}
+
+// Prefixes returns the prefixes of the graph and their namespaces.
+func (g *Graph) Prefixes() map[string]IRI {
+	prefixes := make(map[string]IRI, len(g.prefixes))
+	for p, ns := range g.prefixes {
+		prefixes[p] = ns
+	}
+	return prefixes
+}
+
+// Expand expands a prefixed name, e.g. "xsd:integer", into an IRI.
+func (g *Graph) Expand(name string) (IRI, error) {
+	i := strings.Index(name, ":")
+	if i < 0 {
+		return "", fmt.Errorf("rdf: %q is not a prefixed name", name)
+	}
+	ns, ok := g.prefixes[name[:i]]
+	if !ok {
+		return "", fmt.Errorf("rdf: prefix %q of %q is not defined", name[:i], name)
+	}
+	return ns + IRI(name[i+1:]), nil
+}
+
+// Compact abbreviates an IRI into a prefixed name with the longest matching namespace, if the rest
+// of the IRI is a valid local name.
+func (g *Graph) Compact(iri IRI) (string, bool) {
+	best, name := "", ""
+	for _, p := range g.sortedPrefixes() {
+		ns := string(g.prefixes[p])
+		local := strings.TrimPrefix(string(iri), ns)
+		if len(ns) > len(best) && strings.HasPrefix(string(iri), ns) && isLocalName(local) {
+			best, name = ns, p+":"+local
+		}
+	}
+	return name, best != ""
+}
+
+func (g *Graph) sortedPrefixes() []string {
+	prefixes := make([]string, 0, len(g.prefixes))
+	for p := range g.prefixes {
+		prefixes = append(prefixes, p)
+	}
+	sort.Strings(prefixes)
+	return prefixes
+}
+
+// isLocalName reports whether s can be written as the local part of a prefixed name without escapes.
+func isLocalName(s string) bool {
+	for i, r := range s {
+		switch {
+		case r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9':
+		case (r == '-' || r == '.') && i > 0 && i < len(s)-1:
+		default:
+			return false
+		}
+	}
+	return true
+}
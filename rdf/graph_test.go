@@ -0,0 +1,82 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package rdf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const ex = "http://example.com/"
+
+func TestGraph_Match(t *testing.T) {
+	alice, bob, knows, name := IRI(ex+"alice"), IRI(ex+"bob"), IRI(ex+"knows"), IRI(ex+"name")
+	g := NewGraph()
+	g.Add(
+		Triple{alice, knows, bob},
+		Triple{alice, name, NewLiteral("Alice", "", "")},
+		Triple{bob, name, NewLiteral("Bob", "", "")},
+		Triple{alice, knows, bob},
+	)
+	assert.Equal(t, 3, g.Len())
+	assert.True(t, g.Has(Triple{alice, knows, bob}))
+
+	assert.Equal(t, []Triple{{alice, knows, bob}, {alice, name, NewLiteral("Alice", "", "")}},
+		g.Match(alice, nil, nil))
+	assert.Len(t, g.Match(nil, name, nil), 2)
+	assert.Equal(t, []Term{NewLiteral("Bob", "", "")}, g.Objects(bob, name))
+	assert.Equal(t, []Term{alice}, g.Subjects(knows, bob))
+	assert.Len(t, g.Match(nil, nil, nil), 3)
+	assert.Empty(t, g.Match(bob, knows, nil))
+
+	assert.True(t, g.Remove(Triple{alice, knows, bob}))
+	assert.False(t, g.Remove(Triple{alice, knows, bob}))
+	assert.Equal(t, 2, g.Len())
+	assert.Empty(t, g.Subjects(knows, bob))
+}
+
+func TestGraph_Prefixes(t *testing.T) {
+	g := NewGraph()
+	g.SetPrefix("ex", ex)
+	g.SetPrefix("people", ex+"people/")
+	g.SetPrefix("xsd", XSD)
+
+	iri, err := g.Expand("xsd:integer")
+	assert.NoError(t, err)
+	assert.Equal(t, XSDInteger, iri)
+	_, err = g.Expand("foaf:name")
+	assert.EqualError(t, err, `rdf: prefix "foaf" of "foaf:name" is not defined`)
+	_, err = g.Expand("name")
+	assert.Error(t, err)
+
+	tests := map[IRI]string{
+		ex + "people/alice": "people:alice",
+		ex + "a-b.c":        "ex:a-b.c",
+		ex:                  "ex:",
+		ex + "a/b":          "",
+		ex + "end.":         "",
+		"urn:x":             "",
+	}
+	for iri, want := range tests {
+		name, ok := g.Compact(iri)
+		assert.Equal(t, want, name, iri)
+		assert.Equal(t, want != "", ok, iri)
+	}
+	assert.Len(t, g.Prefixes(), 3)
+}
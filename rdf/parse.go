@@ -0,0 +1,444 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package rdf
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Content types of the serializations.
+const (
+	NTriples = "application/n-triples"
+	NQuads   = "application/n-quads"
+	Turtle   = "text/turtle"
+)
+
+// Parse parses a graph of the given content type, NTriples, NQuads or Turtle. Relative IRIs of
+// Turtle are resolved against base, if it isn't empty. The graph names of N-Quads are dropped; use
+// NewNQuadsDecoder to keep them.
+func Parse(r io.Reader, contentType, base string) (*Graph, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = contentType
+	}
+	switch mediaType {
+	case Turtle, "application/x-turtle":
+		return ParseTurtle(r, base)
+	case NTriples, NQuads, "text/plain":
+		g := NewGraph()
+		d := NewNQuadsDecoder(r)
+		for {
+			q, err := d.Decode()
+			if err == io.EOF {
+				return g, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			g.Add(q.Triple)
+		}
+	}
+	return nil, fmt.Errorf("rdf: unsupported content type %q", contentType)
+}
+
+// ParseNTriples parses N-Triples.
+func ParseNTriples(r io.Reader) ([]Triple, error) {
+	var triples []Triple
+	d := NewNTriplesDecoder(r)
+	for {
+		q, err := d.Decode()
+		if err == io.EOF {
+			return triples, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		triples = append(triples, q.Triple)
+	}
+}
+
+// ParseNQuads parses N-Quads.
+func ParseNQuads(r io.Reader) ([]Quad, error) {
+	var quads []Quad
+	d := NewNQuadsDecoder(r)
+	for {
+		q, err := d.Decode()
+		if err == io.EOF {
+			return quads, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		quads = append(quads, q)
+	}
+}
+
+// Decoder reads N-Triples or N-Quads a statement at a time, so that documents of any size can be
+// read in constant memory.
+type Decoder struct {
+	s     *bufio.Scanner
+	line  int
+	quads bool
+}
+
+// NewNTriplesDecoder returns a decoder of N-Triples.
+func NewNTriplesDecoder(r io.Reader) *Decoder {
+	return newDecoder(r, false)
+}
+
+// NewNQuadsDecoder returns a decoder of N-Quads, which also reads N-Triples.
+func NewNQuadsDecoder(r io.Reader) *Decoder {
+	return newDecoder(r, true)
+}
+
+func newDecoder(r io.Reader, quads bool) *Decoder {
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	return &Decoder{s: s, quads: quads}
+}
+
+// Decode returns the next statement, with a nil Graph for triples. It returns io.EOF at the end of
+// the input.
+func (d *Decoder) Decode() (Quad, error) {
+	for d.s.Scan() {
+		d.line++
+		l := &lexer{src: d.s.Text(), firstLine: d.line}
+		l.skipSpace()
+		if l.eof() {
+			continue
+		}
+		q, err := l.statement(d.quads)
+		if err != nil {
+			return Quad{}, err
+		}
+		return q, nil
+	}
+	if err := d.s.Err(); err != nil {
+		return Quad{}, err
+	}
+	return Quad{}, io.EOF
+}
+
+// lexer reads the terms of N-Triples and N-Quads, and the tokens that Turtle shares with them.
+type lexer struct {
+	src string
+	pos int
+	// firstLine is the number of the first line of src, for errors.
+	firstLine int
+}
+
+func (l *lexer) errorf(format string, args ...interface{}) error {
+	line := l.firstLine + strings.Count(l.src[:l.pos], "\n")
+	return fmt.Errorf("rdf: line %d: %s", line, fmt.Sprintf(format, args...))
+}
+
+func (l *lexer) eof() bool {
+	return l.pos >= len(l.src)
+}
+
+// peek returns the next byte, or 0 at the end of the input.
+func (l *lexer) peek() byte {
+	if l.eof() {
+		return 0
+	}
+	return l.src[l.pos]
+}
+
+func (l *lexer) rest() string {
+	return l.src[l.pos:]
+}
+
+// skipSpace skips white space and comments.
+func (l *lexer) skipSpace() {
+	for !l.eof() {
+		switch l.src[l.pos] {
+		case ' ', '\t', '\r', '\n':
+			l.pos++
+		case '#':
+			for !l.eof() && l.src[l.pos] != '\n' {
+				l.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// consume skips s if the input continues with it.
+func (l *lexer) consume(s string) bool {
+	if strings.HasPrefix(l.rest(), s) {
+		l.pos += len(s)
+		return true
+	}
+	return false
+}
+
+func (l *lexer) expect(s string) error {
+	l.skipSpace()
+	if !l.consume(s) {
+		return l.errorf("expected %q, found %s", s, l.found())
+	}
+	return nil
+}
+
+// found describes the next token for errors.
+func (l *lexer) found() string {
+	if l.eof() {
+		return "end of input"
+	}
+	end := strings.IndexAny(l.rest(), " \t\r\n")
+	if end < 0 || end > 20 {
+		end = len(l.rest())
+		if end > 20 {
+			end = 20
+		}
+	}
+	return strconv.Quote(l.rest()[:end])
+}
+
+// statement reads a line of N-Triples, or of N-Quads if quads is set.
+func (l *lexer) statement(quads bool) (Quad, error) {
+	var q Quad
+	var err error
+	if q.Subject, err = l.ntTerm(); err != nil {
+		return q, err
+	}
+	if _, ok := q.Subject.(Literal); ok {
+		return q, l.errorf("a literal can't be a subject")
+	}
+	l.skipSpace()
+	if q.Predicate, err = l.ntTerm(); err != nil {
+		return q, err
+	}
+	if _, ok := q.Predicate.(IRI); !ok {
+		return q, l.errorf("the predicate must be an IRI")
+	}
+	l.skipSpace()
+	if q.Object, err = l.ntTerm(); err != nil {
+		return q, err
+	}
+	l.skipSpace()
+	if quads && l.peek() != '.' {
+		if q.Graph, err = l.ntTerm(); err != nil {
+			return q, err
+		}
+		if _, ok := q.Graph.(Literal); ok {
+			return q, l.errorf("a literal can't be a graph name")
+		}
+	}
+	if err = l.expect("."); err != nil {
+		return q, err
+	}
+	l.skipSpace()
+	if !l.eof() {
+		return q, l.errorf("unexpected %s after the statement", l.found())
+	}
+	return q, nil
+}
+
+// ntTerm reads an IRI, a blank node or a literal in N-Triples syntax.
+func (l *lexer) ntTerm() (Term, error) {
+	switch l.peek() {
+	case '<':
+		iri, err := l.iriRef()
+		return IRI(iri), err
+	case '_':
+		label, err := l.blankNodeLabel()
+		return BlankNode(label), err
+	case '"':
+		lexical, err := l.quoted(false)
+		if err != nil {
+			return nil, err
+		}
+		var datatype IRI
+		var language string
+		switch {
+		case l.consume("^^"):
+			if l.peek() != '<' {
+				return nil, l.errorf("expected a datatype IRI, found %s", l.found())
+			}
+			iri, err := l.iriRef()
+			if err != nil {
+				return nil, err
+			}
+			datatype = IRI(iri)
+		case l.peek() == '@':
+			if language, err = l.langTag(); err != nil {
+				return nil, err
+			}
+		}
+		return NewLiteral(lexical, datatype, language), nil
+	}
+	return nil, l.errorf("expected an IRI, a blank node or a literal, found %s", l.found())
+}
+
+// iriRef reads an IRI between angle brackets, unescaping it.
+func (l *lexer) iriRef() (string, error) {
+	l.pos++
+	var b strings.Builder
+	for !l.eof() {
+		c := l.src[l.pos]
+		switch {
+		case c == '>':
+			l.pos++
+			return b.String(), nil
+		case c == '\\':
+			r, err := l.uchar()
+			if err != nil {
+				return "", err
+			}
+			b.WriteRune(r)
+		case c <= ' ' || strings.IndexByte("<\"{}|^`", c) >= 0:
+			return "", l.errorf("invalid character %q in IRI", c)
+		default:
+			b.WriteByte(c)
+			l.pos++
+		}
+	}
+	return "", l.errorf("unterminated IRI")
+}
+
+// uchar reads a \u or \U escape.
+func (l *lexer) uchar() (rune, error) {
+	n := 0
+	switch {
+	case strings.HasPrefix(l.rest(), `\u`):
+		n = 4
+	case strings.HasPrefix(l.rest(), `\U`):
+		n = 8
+	default:
+		return 0, l.errorf("invalid escape %s", l.found())
+	}
+	if len(l.rest()) < n+2 {
+		return 0, l.errorf("truncated escape %s", l.found())
+	}
+	code, err := strconv.ParseUint(l.src[l.pos+2:l.pos+2+n], 16, 32)
+	if err != nil || !utf8.ValidRune(rune(code)) {
+		return 0, l.errorf("invalid escape %s", l.found())
+	}
+	l.pos += n + 2
+	return rune(code), nil
+}
+
+// blankNodeLabel reads a blank node label, without its "_:".
+func (l *lexer) blankNodeLabel() (string, error) {
+	if !l.consume("_:") {
+		return "", l.errorf("expected a blank node, found %s", l.found())
+	}
+	start := l.pos
+	for !l.eof() {
+		r, size := utf8.DecodeRuneInString(l.rest())
+		if !isNameRune(r) && !(r == '.' && l.pos > start) {
+			break
+		}
+		l.pos += size
+	}
+	// A label can't end with a dot, which ends the statement instead.
+	for l.pos > start && l.src[l.pos-1] == '.' {
+		l.pos--
+	}
+	if l.pos == start {
+		return "", l.errorf("empty blank node label")
+	}
+	return l.src[start:l.pos], nil
+}
+
+// isNameRune reports whether r can be in a blank node label or a prefixed name, apart from dots.
+func isNameRune(r rune) bool {
+	return r == '_' || r == '-' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' ||
+		r == 0xB7 || r >= 0xC0 && r != utf8.RuneError && r != 0xD7 && r != 0xF7
+}
+
+// langTag reads a language tag, without its "@".
+func (l *lexer) langTag() (string, error) {
+	l.pos++
+	start := l.pos
+	for !l.eof() {
+		c := l.src[l.pos]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || (c == '-' || c >= '0' && c <= '9') && l.pos > start) {
+			break
+		}
+		l.pos++
+	}
+	if l.pos == start {
+		return "", l.errorf("empty language tag")
+	}
+	return l.src[start:l.pos], nil
+}
+
+// quoted reads a quoted string, unescaping it. Turtle also has single quotes and long strings
+// between triple quotes, which may span lines.
+func (l *lexer) quoted(turtle bool) (string, error) {
+	quote := l.rest()[:1]
+	if turtle && (strings.HasPrefix(l.rest(), `"""`) || strings.HasPrefix(l.rest(), `'''`)) {
+		quote = l.rest()[:3]
+	} else if !turtle && quote != `"` {
+		return "", l.errorf("expected a string, found %s", l.found())
+	}
+	l.pos += len(quote)
+	var b strings.Builder
+	for !l.eof() {
+		if l.consume(quote) {
+			return b.String(), nil
+		}
+		c := l.src[l.pos]
+		switch {
+		case c == '\\':
+			if err := l.echar(&b); err != nil {
+				return "", err
+			}
+		case (c == '\n' || c == '\r') && len(quote) == 1:
+			return "", l.errorf("line break in string")
+		default:
+			b.WriteByte(c)
+			l.pos++
+		}
+	}
+	return "", l.errorf("unterminated string")
+}
+
+// echar reads an escape of a string.
+func (l *lexer) echar(b *strings.Builder) error {
+	if len(l.rest()) < 2 {
+		return l.errorf("truncated escape")
+	}
+	if c := l.src[l.pos+1]; c == 'u' || c == 'U' {
+		r, err := l.uchar()
+		b.WriteRune(r)
+		return err
+	}
+	i := strings.IndexByte(`tbnrf"'\`, l.src[l.pos+1])
+	if i < 0 {
+		return l.errorf("invalid escape %s", l.found())
+	}
+	b.WriteByte("\t\b\n\r\f\"'\\"[i])
+	l.pos += 2
+	return nil
+}
+
+func readAll(r io.Reader) (string, error) {
+	b, err := ioutil.ReadAll(r)
+	return string(b), err
+}
@@ -0,0 +1,102 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package rdf
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseNTriples(t *testing.T) {
+	doc := `# a comment
+<http://example.com/a> <http://example.com/p> "say \"hi\"\n\u00E9" .
+
+_:b0 <http://example.com/p> "chat"@fr-BE . # trailing comment
+_:b0 <http://example.com/p> "42"^^<http://www.w3.org/2001/XMLSchema#integer>.
+<http://example.com/\u0041> <http://example.com/p> _:b1.x .
+`
+	triples, err := ParseNTriples(strings.NewReader(doc))
+	assert.NoError(t, err)
+	p := IRI(ex + "p")
+	assert.Equal(t, []Triple{
+		{IRI(ex + "a"), p, NewLiteral("say \"hi\"\né", "", "")},
+		{BlankNode("b0"), p, NewLiteral("chat", "", "fr-BE")},
+		{BlankNode("b0"), p, NewLiteral("42", XSDInteger, "")},
+		{IRI(ex + "A"), p, BlankNode("b1.x")},
+	}, triples)
+}
+
+func TestParseNTriplesErrors(t *testing.T) {
+	tests := map[string]string{
+		`<a> <p> "x"`:                "rdf: line 1: expected \".\", found end of input",
+		`"x" <p> <o> .`:              "rdf: line 1: a literal can't be a subject",
+		`<a> _:p <o> .`:              "rdf: line 1: the predicate must be an IRI",
+		`<a> <p> <o> <g> .`:          `rdf: line 1: expected ".", found "<g>"`,
+		`<a> <p> "x .`:               "rdf: line 1: unterminated string",
+		`<a b> <p> <o> .`:            "rdf: line 1: invalid character ' ' in IRI",
+		"\n<a> <p> \"\\q\" .":        `rdf: line 2: invalid escape "\\q\""`,
+		`<a> <p> <o> . <a> <p> <o>.`: `rdf: line 1: unexpected "<a>" after the statement`,
+	}
+	for doc, want := range tests {
+		_, err := ParseNTriples(strings.NewReader(doc))
+		assert.EqualError(t, err, want, doc)
+	}
+}
+
+func TestNQuadsDecoder(t *testing.T) {
+	d := NewNQuadsDecoder(strings.NewReader(`<a> <p> <o> <g> .
+<a> <p> "x" .
+<a> <p> <o> _:g .
+`))
+	q, err := d.Decode()
+	assert.NoError(t, err)
+	assert.Equal(t, Quad{Triple{IRI("a"), IRI("p"), IRI("o")}, IRI("g")}, q)
+	assert.Equal(t, "<a> <p> <o> <g> .", q.String())
+
+	q, err = d.Decode()
+	assert.NoError(t, err)
+	assert.Nil(t, q.Graph)
+	assert.Equal(t, `<a> <p> "x" .`, q.String())
+
+	q, err = d.Decode()
+	assert.NoError(t, err)
+	assert.Equal(t, BlankNode("g"), q.Graph)
+
+	_, err = d.Decode()
+	assert.Equal(t, io.EOF, err)
+
+	quads, err := ParseNQuads(strings.NewReader(`<a> <p> <o> "g" .`))
+	assert.EqualError(t, err, "rdf: line 1: a literal can't be a graph name")
+	assert.Nil(t, quads)
+}
+
+func TestParse(t *testing.T) {
+	g, err := Parse(strings.NewReader("<a> <p> <o> <g> .\n<a> <p> <o> .\n"), "application/n-quads", "")
+	assert.NoError(t, err)
+	assert.Equal(t, []Triple{{IRI("a"), IRI("p"), IRI("o")}}, g.Triples())
+
+	g, err = Parse(strings.NewReader("<a> <p> <o> ."), "text/turtle; charset=utf-8", "http://example.com/")
+	assert.NoError(t, err)
+	assert.Equal(t, []Triple{{IRI(ex + "a"), IRI(ex + "p"), IRI(ex + "o")}}, g.Triples())
+
+	_, err = Parse(strings.NewReader(""), "application/rdf+xml", "")
+	assert.EqualError(t, err, `rdf: unsupported content type "application/rdf+xml"`)
+}
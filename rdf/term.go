@@ -0,0 +1,295 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+// Package rdf models RDF terms, the IRIs, blank nodes and literals that data.world's SPARQL queries
+// return, and converts literals to and from Go values. It also parses and writes graphs, such as the
+// results of CONSTRUCT queries, in N-Triples, N-Quads and Turtle.
+package rdf
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Namespaces of the datatypes.
+const (
+	XSD = "http://www.w3.org/2001/XMLSchema#"
+	RDF = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+)
+
+// Common datatypes.
+const (
+	XSDString       IRI = XSD + "string"
+	XSDBoolean      IRI = XSD + "boolean"
+	XSDInteger      IRI = XSD + "integer"
+	XSDLong         IRI = XSD + "long"
+	XSDInt          IRI = XSD + "int"
+	XSDDecimal      IRI = XSD + "decimal"
+	XSDDouble       IRI = XSD + "double"
+	XSDFloat        IRI = XSD + "float"
+	XSDDate         IRI = XSD + "date"
+	XSDDateTime     IRI = XSD + "dateTime"
+	XSDTime         IRI = XSD + "time"
+	XSDBase64Binary IRI = XSD + "base64Binary"
+	XSDHexBinary    IRI = XSD + "hexBinary"
+	RDFLangString   IRI = RDF + "langString"
+)
+
+// Term is an IRI, a BlankNode or a Literal.
+type Term interface {
+	// String returns the term in N-Triples syntax.
+	String() string
+	isTerm()
+}
+
+// IRI is an IRI, e.g. http://data.world/my-username/my-awesome-dataset.
+type IRI string
+
+func (i IRI) String() string {
+	return "<" + escapeIRI(string(i)) + ">"
+}
+
+func (IRI) isTerm() {}
+
+// BlankNode is a blank node, identified by a label that's only meaningful within one result or graph.
+type BlankNode string
+
+func (b BlankNode) String() string {
+	return "_:" + string(b)
+}
+
+func (BlankNode) isTerm() {}
+
+// Literal is a value with a datatype, and a language tag for language-tagged strings. Plain literals
+// have the datatype xsd:string.
+type Literal struct {
+	Lexical  string
+	Datatype IRI
+	Language string
+}
+
+// NewLiteral returns a literal of the given lexical form and datatype; xsd:string if datatype is
+// empty, or rdf:langString if the language isn't.
+func NewLiteral(lexical string, datatype IRI, language string) Literal {
+	switch {
+	case language != "":
+		datatype = RDFLangString
+	case datatype == "":
+		datatype = XSDString
+	}
+	return Literal{Lexical: lexical, Datatype: datatype, Language: language}
+}
+
+func (l Literal) String() string {
+	s := Quote(l.Lexical)
+	switch {
+	case l.Language != "":
+		return s + "@" + l.Language
+	case l.Datatype == "" || l.Datatype == XSDString:
+		return s
+	}
+	return s + "^^" + l.Datatype.String()
+}
+
+func (Literal) isTerm() {}
+
+var literalEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`,
+	"\b", `\b`, "\f", `\f`)
+
+// Quote returns a lexical form as a double-quoted string of Turtle, N-Triples or SPARQL.
+func Quote(lexical string) string {
+	return `"` + literalEscaper.Replace(lexical) + `"`
+}
+
+// escapeIRI writes the characters that N-Triples doesn't allow in IRIs as \u escapes.
+func escapeIRI(s string) string {
+	if strings.IndexFunc(s, invalidInIRI) < 0 {
+		return s
+	}
+	var b strings.Builder
+	for _, r := range s {
+		if invalidInIRI(r) {
+			fmt.Fprintf(&b, "\\u%04X", r)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func invalidInIRI(r rune) bool {
+	return r <= ' ' || strings.ContainsRune("<>\"{}|^`\\", r)
+}
+
+// LiteralOf converts a Go value into a literal of the matching xsd datatype: strings into
+// xsd:string, integers into xsd:integer, floats into xsd:double, bools into xsd:boolean, time.Time
+// into xsd:dateTime and []byte into xsd:base64Binary. Literals are returned as they are.
+func LiteralOf(v interface{}) (Literal, error) {
+	switch v := v.(type) {
+	case Literal:
+		return v, nil
+	case string:
+		return NewLiteral(v, XSDString, ""), nil
+	case []byte:
+		return NewLiteral(base64.StdEncoding.EncodeToString(v), XSDBase64Binary, ""), nil
+	case bool:
+		return NewLiteral(strconv.FormatBool(v), XSDBoolean, ""), nil
+	case int:
+		return NewLiteral(strconv.FormatInt(int64(v), 10), XSDInteger, ""), nil
+	case int8:
+		return NewLiteral(strconv.FormatInt(int64(v), 10), XSDInteger, ""), nil
+	case int16:
+		return NewLiteral(strconv.FormatInt(int64(v), 10), XSDInteger, ""), nil
+	case int32:
+		return NewLiteral(strconv.FormatInt(int64(v), 10), XSDInteger, ""), nil
+	case int64:
+		return NewLiteral(strconv.FormatInt(v, 10), XSDInteger, ""), nil
+	case uint:
+		return NewLiteral(strconv.FormatUint(uint64(v), 10), XSDInteger, ""), nil
+	case uint8:
+		return NewLiteral(strconv.FormatUint(uint64(v), 10), XSDInteger, ""), nil
+	case uint16:
+		return NewLiteral(strconv.FormatUint(uint64(v), 10), XSDInteger, ""), nil
+	case uint32:
+		return NewLiteral(strconv.FormatUint(uint64(v), 10), XSDInteger, ""), nil
+	case uint64:
+		return NewLiteral(strconv.FormatUint(v, 10), XSDInteger, ""), nil
+	case float32:
+		return NewLiteral(formatDouble(float64(v), 32), XSDDouble, ""), nil
+	case float64:
+		return NewLiteral(formatDouble(v, 64), XSDDouble, ""), nil
+	case time.Time:
+		return NewLiteral(v.Format(time.RFC3339Nano), XSDDateTime, ""), nil
+	}
+	return Literal{}, fmt.Errorf("rdf: no literal for values of type %T", v)
+}
+
+func formatDouble(f float64, bits int) string {
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case math.IsInf(f, 1):
+		return "INF"
+	case math.IsInf(f, -1):
+		return "-INF"
+	}
+	return strconv.FormatFloat(f, 'g', -1, bits)
+}
+
+// Value converts the literal into the Go value of its datatype: int64 for xsd:integer and its
+// subtypes, float64 for xsd:decimal, xsd:double and xsd:float, bool for xsd:boolean, time.Time for
+// xsd:dateTime, xsd:date and xsd:time, and []byte for xsd:base64Binary and xsd:hexBinary. Literals of
+// other datatypes are returned as their lexical form.
+func (l Literal) Value() (interface{}, error) {
+	switch {
+	case l.isInteger():
+		return l.Int64()
+	case l.Datatype == XSDDecimal || l.Datatype == XSDDouble || l.Datatype == XSDFloat:
+		return l.Float64()
+	case l.Datatype == XSDBoolean:
+		return l.Bool()
+	case l.Datatype == XSDDateTime || l.Datatype == XSDDate || l.Datatype == XSDTime ||
+		l.Datatype == XSD+"dateTimeStamp":
+		return l.Time()
+	case l.Datatype == XSDBase64Binary:
+		return base64.StdEncoding.DecodeString(strings.TrimSpace(l.Lexical))
+	case l.Datatype == XSDHexBinary:
+		return hex.DecodeString(strings.TrimSpace(l.Lexical))
+	}
+	return l.Lexical, nil
+}
+
+func (l Literal) isInteger() bool {
+	if !strings.HasPrefix(string(l.Datatype), XSD) {
+		return false
+	}
+	switch strings.TrimPrefix(string(l.Datatype), XSD) {
+	case "integer", "int", "long", "short", "byte", "nonNegativeInteger", "positiveInteger",
+		"nonPositiveInteger", "negativeInteger", "unsignedLong", "unsignedInt", "unsignedShort",
+		"unsignedByte":
+		return true
+	}
+	return false
+}
+
+// Int64 parses the literal as an integer.
+func (l Literal) Int64() (int64, error) {
+	n, err := strconv.ParseInt(strings.TrimPrefix(strings.TrimSpace(l.Lexical), "+"), 10, 64)
+	if err != nil {
+		return 0, l.errorf("integer")
+	}
+	return n, nil
+}
+
+// Float64 parses the literal as a number, including the xsd:double forms INF, -INF and NaN.
+func (l Literal) Float64() (float64, error) {
+	s := strings.TrimSpace(l.Lexical)
+	switch s {
+	case "INF", "+INF":
+		return math.Inf(1), nil
+	case "-INF":
+		return math.Inf(-1), nil
+	case "NaN":
+		return math.NaN(), nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, l.errorf("number")
+	}
+	return f, nil
+}
+
+// Bool parses the literal as a boolean: true, false, 1 or 0.
+func (l Literal) Bool() (bool, error) {
+	switch strings.TrimSpace(l.Lexical) {
+	case "true", "1":
+		return true, nil
+	case "false", "0":
+		return false, nil
+	}
+	return false, l.errorf("boolean")
+}
+
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02Z07:00",
+	"2006-01-02",
+	"15:04:05.999999999Z07:00",
+	"15:04:05.999999999",
+}
+
+// Time parses the literal as an xsd:dateTime, xsd:date or xsd:time. Values without a time zone are
+// in UTC.
+func (l Literal) Time() (time.Time, error) {
+	s := strings.TrimSpace(l.Lexical)
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, l.errorf("date or time")
+}
+
+func (l Literal) errorf(what string) error {
+	return fmt.Errorf("rdf: literal %s is not a valid %s", l, what)
+}
@@ -0,0 +1,101 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package rdf
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTerm_String(t *testing.T) {
+	tests := map[Term]string{
+		IRI("http://example.com/a"):                    "<http://example.com/a>",
+		IRI("http://example.com/a b>"):                 `<http://example.com/a\u0020b\u003E>`,
+		BlankNode("b0"):                                "_:b0",
+		NewLiteral("plain", "", ""):                    `"plain"`,
+		NewLiteral("say \"hi\"\n", "", ""):             `"say \"hi\"\n"`,
+		NewLiteral("a\tb\\c", "", ""):                  `"a\tb\\c"`,
+		NewLiteral("chat", XSDInteger, "fr"):           `"chat"@fr`,
+		NewLiteral("42", XSDInteger, ""):               `"42"^^<http://www.w3.org/2001/XMLSchema#integer>`,
+		Literal{Lexical: "no datatype"}:                `"no datatype"`,
+		NewLiteral("x", "http://example.com/type", ""): `"x"^^<http://example.com/type>`,
+	}
+	for term, want := range tests {
+		assert.Equal(t, want, term.String())
+	}
+	assert.Equal(t, RDFLangString, NewLiteral("chat", "", "fr").Datatype)
+}
+
+func TestLiteralOf(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  Literal
+	}{
+		{"a", Literal{"a", XSDString, ""}},
+		{[]byte("hi"), Literal{"aGk=", XSDBase64Binary, ""}},
+		{true, Literal{"true", XSDBoolean, ""}},
+		{42, Literal{"42", XSDInteger, ""}},
+		{int8(-1), Literal{"-1", XSDInteger, ""}},
+		{uint64(math.MaxUint64), Literal{"18446744073709551615", XSDInteger, ""}},
+		{1.5, Literal{"1.5", XSDDouble, ""}},
+		{float32(0.1), Literal{"0.1", XSDDouble, ""}},
+		{math.Inf(-1), Literal{"-INF", XSDDouble, ""}},
+		{time.Date(2018, 8, 3, 15, 56, 41, 0, time.UTC), Literal{"2018-08-03T15:56:41Z", XSDDateTime, ""}},
+		{Literal{"x", XSDDate, ""}, Literal{"x", XSDDate, ""}},
+	}
+	for _, test := range tests {
+		got, err := LiteralOf(test.value)
+		if assert.NoError(t, err) {
+			assert.Equal(t, test.want, got)
+		}
+	}
+	_, err := LiteralOf(struct{}{})
+	assert.EqualError(t, err, "rdf: no literal for values of type struct {}")
+}
+
+func TestLiteral_Value(t *testing.T) {
+	tests := []struct {
+		literal Literal
+		want    interface{}
+	}{
+		{NewLiteral("+7", XSD+"unsignedShort", ""), int64(7)},
+		{NewLiteral("2.50", XSDDecimal, ""), 2.5},
+		{NewLiteral("INF", XSDDouble, ""), math.Inf(1)},
+		{NewLiteral("1", XSDBoolean, ""), true},
+		{NewLiteral("2018-08-03", XSDDate, ""), time.Date(2018, 8, 3, 0, 0, 0, 0, time.UTC)},
+		{NewLiteral("15:56:41", XSDTime, ""), time.Date(0, 1, 1, 15, 56, 41, 0, time.UTC)},
+		{NewLiteral("aGk=", XSDBase64Binary, ""), []byte("hi")},
+		{NewLiteral("6869", XSDHexBinary, ""), []byte("hi")},
+		{NewLiteral("chat", "", "fr"), "chat"},
+		{NewLiteral("P1D", XSD+"duration", ""), "P1D"},
+	}
+	for _, test := range tests {
+		got, err := test.literal.Value()
+		if assert.NoError(t, err, test.literal.String()) {
+			assert.Equal(t, test.want, got, test.literal.String())
+		}
+	}
+
+	_, err := NewLiteral("yes", XSDBoolean, "").Value()
+	assert.EqualError(t, err, `rdf: literal "yes"^^<http://www.w3.org/2001/XMLSchema#boolean> is not a valid boolean`)
+	_, err = NewLiteral("noon", XSDTime, "").Time()
+	assert.EqualError(t, err, `rdf: literal "noon"^^<http://www.w3.org/2001/XMLSchema#time> is not a valid date or time`)
+}
@@ -0,0 +1,454 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package rdf
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// Terms of RDF lists, which Turtle writes as collections.
+const (
+	RDFNil   IRI = RDF + "nil"
+	RDFFirst IRI = RDF + "first"
+	RDFRest  IRI = RDF + "rest"
+)
+
+// ParseTurtle parses a Turtle document into a graph, which keeps the document's prefixes. Relative
+// IRIs are resolved against base, or against the document's @base, and kept relative if there is
+// neither. Blank nodes of [] and of collections get labels starting with "genid", and so do labelled
+// blank nodes whose labels in the document start with "genid", so that the two never clash.
+func ParseTurtle(r io.Reader, base string) (*Graph, error) {
+	src, err := readAll(r)
+	if err != nil {
+		return nil, err
+	}
+	p := &turtleParser{lexer: lexer{src: src, firstLine: 1}, graph: NewGraph()}
+	if base != "" {
+		if p.base, err = url.Parse(base); err != nil {
+			return nil, fmt.Errorf("rdf: invalid base IRI %q: %s", base, err)
+		}
+	}
+	if err = p.parse(); err != nil {
+		return nil, err
+	}
+	return p.graph, nil
+}
+
+type turtleParser struct {
+	lexer
+	base   *url.URL
+	graph  *Graph
+	bnodes int
+	// genids are the nodes of the document's labels that start with "genid".
+	genids map[string]BlankNode
+}
+
+func (p *turtleParser) parse() error {
+	for {
+		p.skipSpace()
+		if p.eof() {
+			return nil
+		}
+		ok, err := p.directive()
+		if err != nil {
+			return err
+		}
+		if ok {
+			continue
+		}
+		if err = p.triples(); err != nil {
+			return err
+		}
+		if err = p.expect("."); err != nil {
+			return err
+		}
+	}
+}
+
+// directive reads a prefix or base declaration, in either the Turtle or the SPARQL style.
+func (p *turtleParser) directive() (bool, error) {
+	var sparql bool
+	var keyword string
+	switch {
+	case p.consume("@prefix"):
+		keyword = "prefix"
+	case p.consume("@base"):
+		keyword = "base"
+	case p.keyword("PREFIX"):
+		keyword, sparql = "prefix", true
+	case p.keyword("BASE"):
+		keyword, sparql = "base", true
+	default:
+		return false, nil
+	}
+
+	var prefix string
+	if keyword == "prefix" {
+		p.skipSpace()
+		start := p.pos
+		for !p.eof() && p.peek() != ':' {
+			r, size := utf8.DecodeRuneInString(p.rest())
+			if !isNameRune(r) && r != '.' {
+				break
+			}
+			p.pos += size
+		}
+		prefix = p.src[start:p.pos]
+		if err := p.expect(":"); err != nil {
+			return false, err
+		}
+	}
+	p.skipSpace()
+	if p.peek() != '<' {
+		return false, p.errorf("expected an IRI, found %s", p.found())
+	}
+	iri, err := p.iri()
+	if err != nil {
+		return false, err
+	}
+	if keyword == "prefix" {
+		p.graph.SetPrefix(prefix, iri)
+	} else if p.base, err = url.Parse(string(iri)); err != nil {
+		return false, p.errorf("invalid base IRI %q", iri)
+	}
+	if !sparql {
+		return true, p.expect(".")
+	}
+	return true, nil
+}
+
+// keyword consumes a case-insensitive keyword followed by white space.
+func (p *turtleParser) keyword(k string) bool {
+	rest := p.rest()
+	if len(rest) <= len(k) || !strings.EqualFold(rest[:len(k)], k) || !strings.ContainsRune(" \t\r\n<", rune(rest[len(k)])) {
+		return false
+	}
+	p.pos += len(k)
+	return true
+}
+
+func (p *turtleParser) triples() error {
+	if p.peek() == '[' {
+		subject, err := p.blankNodePropertyList()
+		if err != nil {
+			return err
+		}
+		p.skipSpace()
+		if p.peek() == '.' {
+			return nil
+		}
+		return p.predicateObjectList(subject)
+	}
+	subject, err := p.subject()
+	if err != nil {
+		return err
+	}
+	return p.predicateObjectList(subject)
+}
+
+func (p *turtleParser) subject() (Term, error) {
+	switch p.peek() {
+	case '_':
+		if strings.HasPrefix(p.rest(), "_:") {
+			return p.labelledBlankNode()
+		}
+	case '(':
+		return p.collection()
+	case '"', '\'':
+		return nil, p.errorf("a literal can't be a subject")
+	}
+	return p.iri()
+}
+
+func (p *turtleParser) predicateObjectList(subject Term) error {
+	for {
+		p.skipSpace()
+		verb, err := p.verb()
+		if err != nil {
+			return err
+		}
+		if err = p.objectList(subject, verb); err != nil {
+			return err
+		}
+		p.skipSpace()
+		if !p.consume(";") {
+			return nil
+		}
+		for p.skipSpace(); p.consume(";"); p.skipSpace() {
+		}
+		if c := p.peek(); c == '.' || c == ']' || c == 0 {
+			return nil
+		}
+	}
+}
+
+func (p *turtleParser) verb() (Term, error) {
+	if strings.HasPrefix(p.rest(), "a") && !p.continuesName(1) {
+		p.pos++
+		return RDFType, nil
+	}
+	return p.iri()
+}
+
+// continuesName reports whether the input after n bytes continues a name, so that the bytes
+// before aren't a keyword.
+func (p *turtleParser) continuesName(n int) bool {
+	r, _ := utf8.DecodeRuneInString(p.src[p.pos+n:])
+	return isNameRune(r) || r == ':' || r == '.' && p.pos+n+1 < len(p.src) && isNameRune(rune(p.src[p.pos+n+1]))
+}
+
+func (p *turtleParser) objectList(subject, predicate Term) error {
+	for {
+		p.skipSpace()
+		object, err := p.object()
+		if err != nil {
+			return err
+		}
+		p.graph.Add(Triple{subject, predicate, object})
+		p.skipSpace()
+		if !p.consume(",") {
+			return nil
+		}
+	}
+}
+
+func (p *turtleParser) object() (Term, error) {
+	switch c := p.peek(); {
+	case c == '[':
+		return p.blankNodePropertyList()
+	case c == '(':
+		return p.collection()
+	case c == '"' || c == '\'':
+		return p.literal()
+	case c == '+' || c == '-' || c == '.' || c >= '0' && c <= '9':
+		return p.number()
+	case c == '_' && strings.HasPrefix(p.rest(), "_:"):
+		return p.labelledBlankNode()
+	}
+	for _, b := range []string{"true", "false"} {
+		if strings.HasPrefix(p.rest(), b) && !p.continuesName(len(b)) {
+			p.pos += len(b)
+			return NewLiteral(b, XSDBoolean, ""), nil
+		}
+	}
+	return p.iri()
+}
+
+func (p *turtleParser) newBlankNode() BlankNode {
+	p.bnodes++
+	return BlankNode(fmt.Sprintf("genid%d", p.bnodes))
+}
+
+// labelledBlankNode reads _:label. A label starting with "genid" is given a new blank node, the same
+// one each time it's used, as it could be the label of a new blank node.
+func (p *turtleParser) labelledBlankNode() (Term, error) {
+	label, err := p.blankNodeLabel()
+	if err != nil || !strings.HasPrefix(label, "genid") {
+		return BlankNode(label), err
+	}
+	node, ok := p.genids[label]
+	if !ok {
+		if p.genids == nil {
+			p.genids = map[string]BlankNode{}
+		}
+		node = p.newBlankNode()
+		p.genids[label] = node
+	}
+	return node, nil
+}
+
+// blankNodePropertyList reads [ predicate object ; ... ], whose subject is a new blank node.
+func (p *turtleParser) blankNodePropertyList() (Term, error) {
+	p.pos++
+	node := p.newBlankNode()
+	p.skipSpace()
+	if p.consume("]") {
+		return node, nil
+	}
+	if err := p.predicateObjectList(node); err != nil {
+		return nil, err
+	}
+	return node, p.expect("]")
+}
+
+// collection reads ( object ... ) as an RDF list.
+func (p *turtleParser) collection() (Term, error) {
+	p.pos++
+	var head, last Term = RDFNil, nil
+	for {
+		p.skipSpace()
+		if p.consume(")") {
+			if last != nil {
+				p.graph.Add(Triple{last, RDFRest, RDFNil})
+			}
+			return head, nil
+		}
+		if p.eof() {
+			return nil, p.errorf("unterminated collection")
+		}
+		item, err := p.object()
+		if err != nil {
+			return nil, err
+		}
+		node := p.newBlankNode()
+		if last == nil {
+			head = node
+		} else {
+			p.graph.Add(Triple{last, RDFRest, node})
+		}
+		p.graph.Add(Triple{node, RDFFirst, item})
+		last = node
+	}
+}
+
+func (p *turtleParser) literal() (Term, error) {
+	lexical, err := p.quoted(true)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case p.peek() == '@':
+		language, err := p.langTag()
+		if err != nil {
+			return nil, err
+		}
+		return NewLiteral(lexical, "", language), nil
+	case p.consume("^^"):
+		datatype, err := p.iri()
+		if err != nil {
+			return nil, err
+		}
+		return NewLiteral(lexical, datatype, ""), nil
+	}
+	return NewLiteral(lexical, "", ""), nil
+}
+
+// number reads an integer, a decimal or a double.
+func (p *turtleParser) number() (Term, error) {
+	start := p.pos
+	if c := p.peek(); c == '+' || c == '-' {
+		p.pos++
+	}
+	digits := p.digits()
+	datatype := XSDInteger
+	if p.peek() == '.' && p.pos+1 < len(p.src) && p.src[p.pos+1] >= '0' && p.src[p.pos+1] <= '9' {
+		p.pos++
+		digits += p.digits()
+		datatype = XSDDecimal
+	}
+	if c := p.peek(); (c == 'e' || c == 'E') && digits > 0 {
+		p.pos++
+		if c := p.peek(); c == '+' || c == '-' {
+			p.pos++
+		}
+		if p.digits() == 0 {
+			return nil, p.errorf("invalid number %q", p.src[start:p.pos])
+		}
+		datatype = XSDDouble
+	}
+	if digits == 0 {
+		p.pos = start
+		return nil, p.errorf("invalid number %s", p.found())
+	}
+	return NewLiteral(p.src[start:p.pos], datatype, ""), nil
+}
+
+func (p *turtleParser) digits() int {
+	start := p.pos
+	for c := p.peek(); c >= '0' && c <= '9'; c = p.peek() {
+		p.pos++
+	}
+	return p.pos - start
+}
+
+// iri reads an IRI, between angle brackets or as a prefixed name.
+func (p *turtleParser) iri() (IRI, error) {
+	if p.peek() == '<' {
+		ref, err := p.iriRef()
+		if err != nil {
+			return "", err
+		}
+		return p.resolve(ref), nil
+	}
+	return p.prefixedName()
+}
+
+func (p *turtleParser) resolve(ref string) IRI {
+	if p.base == nil {
+		return IRI(ref)
+	}
+	u, err := url.Parse(ref)
+	if err != nil || u.IsAbs() {
+		return IRI(ref)
+	}
+	iri := p.base.ResolveReference(u).String()
+	// url drops empty fragments, which namespaces such as xsd's end with.
+	if strings.HasSuffix(ref, "#") && !strings.HasSuffix(iri, "#") {
+		iri += "#"
+	}
+	return IRI(iri)
+}
+
+// prefixedName reads a name such as xsd:integer and expands it.
+func (p *turtleParser) prefixedName() (IRI, error) {
+	start := p.pos
+	for !p.eof() && p.peek() != ':' {
+		r, size := utf8.DecodeRuneInString(p.rest())
+		if !isNameRune(r) && r != '.' {
+			break
+		}
+		p.pos += size
+	}
+	if p.peek() != ':' {
+		p.pos = start
+		return "", p.errorf("expected an IRI, found %s", p.found())
+	}
+	prefix := p.src[start:p.pos]
+	ns, ok := p.graph.prefixes[prefix]
+	if !ok {
+		p.pos = start
+		return "", p.errorf("prefix %q is not defined", prefix)
+	}
+	p.pos++
+
+	var local strings.Builder
+	for !p.eof() {
+		r, size := utf8.DecodeRuneInString(p.rest())
+		switch {
+		case r == '\\' && size < len(p.rest()) && strings.IndexByte("_~.-!$&'()*+,;=/?#@%", p.src[p.pos+1]) >= 0:
+			local.WriteByte(p.src[p.pos+1])
+			p.pos += 2
+			continue
+		case r == '%' && len(p.rest()) >= 3:
+			local.WriteString(p.src[p.pos : p.pos+3])
+			p.pos += 3
+			continue
+		case isNameRune(r) || r == ':':
+		case r == '.' && p.continuesName(1) && local.Len() > 0:
+		default:
+			return ns + IRI(local.String()), nil
+		}
+		local.WriteRune(r)
+		p.pos += size
+	}
+	return ns + IRI(local.String()), nil
+}
@@ -0,0 +1,121 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package rdf
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTurtle(t *testing.T) {
+	doc := `@base <http://example.com/> .
+@prefix : <http://example.com/> .
+PREFIX foaf: <http://xmlns.com/foaf/0.1/>
+prefix xsd: <http://www.w3.org/2001/XMLSchema#>
+
+<alice> a foaf:Person ;
+    foaf:name "Alice", 'Alicia'@es ;
+    foaf:age 42 ;
+    :height 1.65 ;
+    :mass 6.1e1 ;
+    :member true ;
+    :born "1980-01-02"^^xsd:date ;
+    :bio """two
+lines""" ;
+    foaf:knows [ foaf:name "Bob" ], _:carol ;
+    :tags ( "a" :b ) ;
+    :empty () ;
+    :v1.0 :x\,y ;
+    .
+[] :seen <alice> .
+`
+	g, err := ParseTurtle(strings.NewReader(doc), "")
+	assert.NoError(t, err)
+
+	foaf := "http://xmlns.com/foaf/0.1/"
+	alice := IRI(ex + "alice")
+	assert.Equal(t, map[string]IRI{"": ex, "foaf": IRI(foaf), "xsd": XSD}, g.Prefixes())
+	objects := func(p IRI) []Term { return g.Objects(alice, p) }
+	assert.Equal(t, []Term{IRI(foaf + "Person")}, objects(RDFType))
+	assert.Equal(t, []Term{NewLiteral("Alice", "", ""), NewLiteral("Alicia", "", "es")}, objects(IRI(foaf+"name")))
+	assert.Equal(t, []Term{NewLiteral("42", XSDInteger, "")}, objects(IRI(foaf+"age")))
+	assert.Equal(t, []Term{NewLiteral("1.65", XSDDecimal, "")}, objects(ex+"height"))
+	assert.Equal(t, []Term{NewLiteral("6.1e1", XSDDouble, "")}, objects(ex+"mass"))
+	assert.Equal(t, []Term{NewLiteral("true", XSDBoolean, "")}, objects(ex+"member"))
+	assert.Equal(t, []Term{NewLiteral("1980-01-02", XSDDate, "")}, objects(ex+"born"))
+	assert.Equal(t, []Term{NewLiteral("two\nlines", "", "")}, objects(ex+"bio"))
+	assert.Equal(t, []Term{RDFNil}, objects(ex+"empty"))
+	assert.Equal(t, []Term{IRI(ex + "x,y")}, objects(ex+"v1.0"))
+
+	knows := objects(IRI(foaf + "knows"))
+	assert.Len(t, knows, 2)
+	assert.Equal(t, []Term{NewLiteral("Bob", "", "")}, g.Objects(knows[0], IRI(foaf+"name")))
+	assert.Equal(t, BlankNode("carol"), knows[1])
+
+	// The collection is a list of two items.
+	list := objects(ex + "tags")[0]
+	assert.Equal(t, []Term{NewLiteral("a", "", "")}, g.Objects(list, RDFFirst))
+	rest := g.Objects(list, RDFRest)[0]
+	assert.Equal(t, []Term{IRI(ex + "b")}, g.Objects(rest, RDFFirst))
+	assert.Equal(t, []Term{RDFNil}, g.Objects(rest, RDFRest))
+
+	seen := g.Subjects(IRI(ex+"seen"), alice)
+	assert.Len(t, seen, 1)
+	assert.IsType(t, BlankNode(""), seen[0])
+}
+
+func TestParseTurtleBase(t *testing.T) {
+	g, err := ParseTurtle(strings.NewReader(`<a> <#p> <../o> .`), "http://example.com/x/y")
+	assert.NoError(t, err)
+	assert.Equal(t, []Triple{{IRI(ex + "x/a"), IRI(ex + "x/y#p"), IRI(ex + "o")}}, g.Triples())
+
+	g, err = ParseTurtle(strings.NewReader(`<a> <p> <o> .`), "")
+	assert.NoError(t, err)
+	assert.Equal(t, []Triple{{IRI("a"), IRI("p"), IRI("o")}}, g.Triples())
+}
+
+func TestParseTurtleGeneratedLabels(t *testing.T) {
+	g, err := ParseTurtle(strings.NewReader(`@prefix ex: <http://example.com/> .
+_:genid1 ex:p "x" . [] ex:q "y" . _:genid1 ex:r "z" .`), "")
+	assert.NoError(t, err)
+	labelled := g.Subjects(IRI(ex+"p"), NewLiteral("x", "", ""))
+	anonymous := g.Subjects(IRI(ex+"q"), NewLiteral("y", "", ""))
+	if assert.Len(t, labelled, 1) && assert.Len(t, anonymous, 1) {
+		assert.NotEqual(t, labelled[0], anonymous[0])
+		assert.Equal(t, labelled, g.Subjects(IRI(ex+"r"), NewLiteral("z", "", "")))
+	}
+}
+
+func TestParseTurtleErrors(t *testing.T) {
+	tests := map[string]string{
+		"<a> <p> <o>":                `rdf: line 1: expected ".", found end of input`,
+		"<a> <p> ex:o .":             `rdf: line 1: prefix "ex" is not defined`,
+		"\n\"x\" <p> <o> .":          "rdf: line 2: a literal can't be a subject",
+		"<a> <p> ( <o> .":            `rdf: line 1: invalid number "."`,
+		"@prefix ex <http://e/> .":   `rdf: line 1: expected ":", found "<http://e/>"`,
+		"<a> <p> [ <q> <o> .":        `rdf: line 1: expected "]", found "."`,
+		"<a> <p> \"\"\"x\n\n<b> <p>": "rdf: line 3: unterminated string",
+		"<a> <p> +x .":               `rdf: line 1: invalid number "+x"`,
+	}
+	for doc, want := range tests {
+		_, err := ParseTurtle(strings.NewReader(doc), "")
+		assert.EqualError(t, err, want, doc)
+	}
+}
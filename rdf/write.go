@@ -0,0 +1,124 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package rdf
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+)
+
+// WriteNTriples writes triples as N-Triples.
+func WriteNTriples(w io.Writer, triples []Triple) error {
+	bw := bufio.NewWriter(w)
+	for _, t := range triples {
+		if _, err := bw.WriteString(t.String() + "\n"); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// WriteNQuads writes quads as N-Quads.
+func WriteNQuads(w io.Writer, quads []Quad) error {
+	bw := bufio.NewWriter(w)
+	for _, q := range quads {
+		if _, err := bw.WriteString(q.String() + "\n"); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// WriteTurtle writes a graph as Turtle, declaring its prefixes and abbreviating IRIs with them. The
+// triples of a subject are grouped, in the order the subjects were first added.
+func WriteTurtle(w io.Writer, g *Graph) error {
+	bw := bufio.NewWriter(w)
+	prefixes := g.sortedPrefixes()
+	for _, p := range prefixes {
+		bw.WriteString("@prefix " + p + ": " + g.prefixes[p].String() + " .\n")
+	}
+	if len(prefixes) > 0 && g.Len() > 0 {
+		bw.WriteString("\n")
+	}
+
+	var subjects []Term
+	bySubject := make(map[Term][]Triple)
+	for _, t := range g.triples {
+		if _, ok := bySubject[t.Subject]; !ok {
+			subjects = append(subjects, t.Subject)
+		}
+		bySubject[t.Subject] = append(bySubject[t.Subject], t)
+	}
+
+	for _, s := range subjects {
+		bw.WriteString(g.turtleTerm(s))
+		// The objects of a predicate are written together, after the predicate's first triple.
+		triples := bySubject[s]
+		written := make([]bool, len(triples))
+		for i, t := range triples {
+			if written[i] {
+				continue
+			}
+			if i > 0 {
+				bw.WriteString(" ;\n   ")
+			}
+			predicate := g.turtleTerm(t.Predicate)
+			if t.Predicate == RDFType {
+				predicate = "a"
+			}
+			bw.WriteString(" " + predicate + " " + g.turtleTerm(t.Object))
+			for j := i + 1; j < len(triples); j++ {
+				if !written[j] && triples[j].Predicate == t.Predicate {
+					written[j] = true
+					bw.WriteString(", " + g.turtleTerm(triples[j].Object))
+				}
+			}
+		}
+		bw.WriteString(" .\n")
+	}
+	return bw.Flush()
+}
+
+// Lexical forms that Turtle writes without quotes.
+var (
+	turtleInteger = regexp.MustCompile(`^[+-]?[0-9]+$`)
+	turtleDecimal = regexp.MustCompile(`^[+-]?[0-9]*\.[0-9]+$`)
+)
+
+// turtleTerm writes a term in Turtle, abbreviated where possible.
+func (g *Graph) turtleTerm(t Term) string {
+	switch t := t.(type) {
+	case IRI:
+		if name, ok := g.Compact(t); ok {
+			return name
+		}
+	case Literal:
+		switch {
+		case t.Datatype == XSDInteger && turtleInteger.MatchString(t.Lexical),
+			t.Datatype == XSDDecimal && turtleDecimal.MatchString(t.Lexical),
+			t.Datatype == XSDBoolean && (t.Lexical == "true" || t.Lexical == "false"):
+			return t.Lexical
+		case t.Language == "" && t.Datatype != "" && t.Datatype != XSDString:
+			if name, ok := g.Compact(t.Datatype); ok {
+				return Quote(t.Lexical) + "^^" + name
+			}
+		}
+	}
+	return t.String()
+}
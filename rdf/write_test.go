@@ -0,0 +1,81 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package rdf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteNTriples(t *testing.T) {
+	triples := []Triple{
+		{IRI(ex + "a"), IRI(ex + "p"), NewLiteral("line\nbreak", "", "")},
+		{BlankNode("b0"), IRI(ex + "p"), NewLiteral("1", XSDInteger, "")},
+	}
+	var buf bytes.Buffer
+	assert.NoError(t, WriteNTriples(&buf, triples))
+	assert.Equal(t, `<http://example.com/a> <http://example.com/p> "line\nbreak" .
+_:b0 <http://example.com/p> "1"^^<http://www.w3.org/2001/XMLSchema#integer> .
+`, buf.String())
+
+	parsed, err := ParseNTriples(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, triples, parsed)
+
+	buf.Reset()
+	assert.NoError(t, WriteNQuads(&buf, []Quad{{triples[1], IRI(ex + "g")}}))
+	assert.Equal(t, "_:b0 <http://example.com/p> \"1\"^^<http://www.w3.org/2001/XMLSchema#integer> <http://example.com/g> .\n",
+		buf.String())
+}
+
+func TestWriteTurtle(t *testing.T) {
+	doc := `@prefix ex: <http://example.com/> .
+@prefix xsd: <http://www.w3.org/2001/XMLSchema#> .
+
+ex:alice a ex:Person ;
+    ex:name "Alice", "Alicia"@es ;
+    ex:age 42, 4.2, "4e1"^^xsd:double, true ;
+    ex:born "1980-01-02"^^xsd:date ;
+    ex:home <http://other.example/home> ;
+    ex:path <http://example.com/a/b> .
+_:b1 ex:p "x"^^<http://example.com/t/y> .
+`
+	g, err := ParseTurtle(strings.NewReader(doc), "")
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	assert.NoError(t, WriteTurtle(&buf, g))
+	assert.Equal(t, `@prefix ex: <http://example.com/> .
+@prefix xsd: <http://www.w3.org/2001/XMLSchema#> .
+
+ex:alice a ex:Person ;
+    ex:name "Alice", "Alicia"@es ;
+    ex:age 42, 4.2, "4e1"^^xsd:double, true ;
+    ex:born "1980-01-02"^^xsd:date ;
+    ex:home <http://other.example/home> ;
+    ex:path <http://example.com/a/b> .
+_:b1 ex:p "x"^^<http://example.com/t/y> .
+`, buf.String())
+
+	again, err := ParseTurtle(&buf, "")
+	assert.NoError(t, err)
+	assert.Equal(t, g.Triples(), again.Triples())
+}
@@ -0,0 +1,448 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package sparql
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/datadotworld/dwapi-go/dwapi"
+	"github.com/datadotworld/dwapi-go/rdf"
+)
+
+// Prefixes are the well-known prefixes that every Builder starts with.
+var Prefixes = map[string]rdf.IRI{
+	"dct":  "http://purl.org/dc/terms/",
+	"dwo":  "https://ontology.data.world/v0#",
+	"owl":  "http://www.w3.org/2002/07/owl#",
+	"rdf":  rdf.RDF,
+	"rdfs": "http://www.w3.org/2000/01/rdf-schema#",
+	"xsd":  rdf.XSD,
+}
+
+// DatasetIRI returns the namespace of the linked data of a dataset or project, under which the rows and
+// columns of its tables are named.
+func DatasetIRI(owner, id string) rdf.IRI {
+	return rdf.IRI("https://" + owner + ".linked.data.world/d/" + id + "/")
+}
+
+var (
+	variable     = regexp.MustCompile(`^[?$][A-Za-z0-9_]+$`)
+	prefixName   = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9_.-]*)?$`)
+	languageTag  = regexp.MustCompile(`^[A-Za-z]+(-[A-Za-z0-9]+)*$`)
+	prefixedName = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9_.-]*)?:([A-Za-z0-9_]([A-Za-z0-9_.-]*[A-Za-z0-9_-])?)?$`)
+)
+
+// Pattern is a triple pattern, of a WHERE clause or of the template of a CONSTRUCT query.
+type Pattern struct {
+	Subject, Predicate, Object interface{}
+}
+
+// Triple returns a triple pattern. Each of its parts is either a string written into the query as it
+// is, which must be a variable (?s or $s), a prefixed name (rdfs:label), an IRI in angle brackets,
+// a blank node (_:b0) or "a"; or an rdf.Term; or any other value that rdf.LiteralOf converts into a
+// literal. Strings are never taken as literals: pass rdf.NewLiteral("Ada", "", "") for a plain string.
+func Triple(subject, predicate, object interface{}) Pattern {
+	return Pattern{Subject: subject, Predicate: predicate, Object: object}
+}
+
+// Builder builds a SELECT, ASK or CONSTRUCT query. Patterns are checked and literals escaped as they
+// are added; the first error is returned by Query and Request. Expressions of filters, projections,
+// grouping and ordering are written as they are, with values bound by Bind rather than written into
+// them:
+//
+//	req, err := sparql.NewSelect("?person").SelectAs("COUNT(?visit)", "visits").
+//		Dataset("my-username", "my-awesome-dataset", "visits").
+//		Where(sparql.Triple("?visit", "visits:person", "?person"),
+//			sparql.Triple("?visit", "visits:date", "?date")).
+//		Filter("?date >= $since").
+//		Bind("since", since).
+//		GroupBy("?person").
+//		OrderBy("DESC(?visits)").
+//		Limit(10).
+//		Request()
+type Builder struct {
+	form       string
+	distinct   bool
+	projection []string
+	template   []Pattern
+	prefixes   map[string]rdf.IRI
+	where      []string
+	groupBy    []string
+	having     []string
+	orderBy    []string
+	limit      int
+	offset     int
+	params     map[string]interface{}
+	err        error
+}
+
+func newBuilder(form string) *Builder {
+	b := &Builder{form: form, prefixes: make(map[string]rdf.IRI, len(Prefixes)), limit: -1}
+	for p, iri := range Prefixes {
+		b.prefixes[p] = iri
+	}
+	return b
+}
+
+// NewSelect starts a SELECT query of variables. Without variables, all variables are selected.
+func NewSelect(vars ...string) *Builder {
+	b := newBuilder("SELECT")
+	for _, v := range vars {
+		if !variable.MatchString(v) {
+			b.fail(fmt.Errorf("sparql: invalid variable %q", v))
+		}
+		b.projection = append(b.projection, v)
+	}
+	return b
+}
+
+// NewAsk starts an ASK query.
+func NewAsk() *Builder {
+	return newBuilder("ASK")
+}
+
+// NewConstruct starts a CONSTRUCT query of a template of triple patterns. The template is checked
+// when the query is built, so it may use prefixes declared afterwards.
+func NewConstruct(template ...Pattern) *Builder {
+	b := newBuilder("CONSTRUCT")
+	b.template = template
+	return b
+}
+
+// Prefix declares a prefix, replacing any earlier declaration of the same prefix. Prefixed names
+// must be declared before they're used.
+func (b *Builder) Prefix(prefix string, iri rdf.IRI) *Builder {
+	if !prefixName.MatchString(prefix) {
+		b.fail(fmt.Errorf("sparql: invalid prefix %q", prefix))
+		return b
+	}
+	b.prefixes[prefix] = iri
+	return b
+}
+
+// Dataset declares the default prefix, as in :column, for the linked data of a dataset or project,
+// and a prefix for each of the given tables, as in table:column.
+func (b *Builder) Dataset(owner, id string, tables ...string) *Builder {
+	ns := DatasetIRI(owner, id)
+	b.Prefix("", ns)
+	for _, t := range tables {
+		b.Prefix(t, ns+rdf.IRI(t)+"/")
+	}
+	return b
+}
+
+// Distinct selects only distinct solutions.
+func (b *Builder) Distinct() *Builder {
+	b.distinct = true
+	return b
+}
+
+// SelectAs selects an expression, such as an aggregate, as a variable: (COUNT(?s) AS ?n).
+func (b *Builder) SelectAs(expr, as string) *Builder {
+	as = "?" + strings.TrimLeft(as, "?$")
+	if !variable.MatchString(as) {
+		b.fail(fmt.Errorf("sparql: invalid variable %q", as))
+		return b
+	}
+	b.projection = append(b.projection, "("+expr+" AS "+as+")")
+	return b
+}
+
+// Where adds triple patterns.
+func (b *Builder) Where(patterns ...Pattern) *Builder {
+	for _, p := range patterns {
+		if s, ok := b.pattern(p); ok {
+			b.where = append(b.where, s)
+		}
+	}
+	return b
+}
+
+// Optional adds triple patterns that solutions may match.
+func (b *Builder) Optional(patterns ...Pattern) *Builder {
+	group := make([]string, 0, len(patterns))
+	for _, p := range patterns {
+		s, ok := b.pattern(p)
+		if !ok {
+			return b
+		}
+		group = append(group, s)
+	}
+	b.where = append(b.where, "OPTIONAL { "+strings.Join(group, " ")+" }")
+	return b
+}
+
+// Filter adds a condition that solutions must meet.
+func (b *Builder) Filter(expr string) *Builder {
+	b.where = append(b.where, "FILTER ("+expr+")")
+	return b
+}
+
+// Values adds inline data binding variables to rows of values, with nil leaving a variable unbound.
+// Values are terms as for Triple.
+func (b *Builder) Values(vars []string, rows ...[]interface{}) *Builder {
+	for _, v := range vars {
+		if !variable.MatchString(v) {
+			b.fail(fmt.Errorf("sparql: invalid variable %q", v))
+			return b
+		}
+	}
+	data := make([]string, len(rows))
+	for i, row := range rows {
+		if len(row) != len(vars) {
+			b.fail(fmt.Errorf("sparql: VALUES row %d has %d values for %d variables", i+1, len(row), len(vars)))
+			return b
+		}
+		terms := make([]string, len(row))
+		for j, v := range row {
+			if v == nil {
+				terms[j] = "UNDEF"
+				continue
+			}
+			t, err := b.term(v)
+			if err != nil {
+				b.fail(err)
+				return b
+			}
+			terms[j] = t
+		}
+		data[i] = "(" + strings.Join(terms, " ") + ")"
+	}
+	b.where = append(b.where, "VALUES ("+strings.Join(vars, " ")+") { "+strings.Join(data, " ")+" }")
+	return b
+}
+
+// Bind binds a variable of the query, as in $name, to a value sent as a parameter of the request.
+func (b *Builder) Bind(name string, value interface{}) *Builder {
+	if b.params == nil {
+		b.params = make(map[string]interface{})
+	}
+	b.params[name] = value
+	return b
+}
+
+// GroupBy adds grouping expressions, usually variables.
+func (b *Builder) GroupBy(exprs ...string) *Builder {
+	b.groupBy = append(b.groupBy, exprs...)
+	return b
+}
+
+// Having adds a condition on groups.
+func (b *Builder) Having(expr string) *Builder {
+	b.having = append(b.having, "("+expr+")")
+	return b
+}
+
+// OrderBy adds sort keys, e.g. ?name or DESC(?total).
+func (b *Builder) OrderBy(keys ...string) *Builder {
+	b.orderBy = append(b.orderBy, keys...)
+	return b
+}
+
+// Limit limits the number of solutions.
+func (b *Builder) Limit(n int) *Builder {
+	if n < 0 {
+		b.fail(fmt.Errorf("sparql: invalid limit %d", n))
+	}
+	b.limit = n
+	return b
+}
+
+// Offset skips solutions.
+func (b *Builder) Offset(n int) *Builder {
+	if n < 0 {
+		b.fail(fmt.Errorf("sparql: invalid offset %d", n))
+	}
+	b.offset = n
+	return b
+}
+
+// Query returns the text of the query.
+func (b *Builder) Query() (string, error) {
+	template := make([]string, 0, len(b.template))
+	for _, t := range b.template {
+		if s, ok := b.pattern(t); ok {
+			template = append(template, s)
+		}
+	}
+	if b.err != nil {
+		return "", b.err
+	}
+	var sb strings.Builder
+	prefixes := make([]string, 0, len(b.prefixes))
+	for p := range b.prefixes {
+		prefixes = append(prefixes, p)
+	}
+	sort.Strings(prefixes)
+	for _, p := range prefixes {
+		fmt.Fprintf(&sb, "PREFIX %s: %s\n", p, b.prefixes[p])
+	}
+
+	sb.WriteString(b.form)
+	switch {
+	case b.form == "CONSTRUCT":
+		sb.WriteString(" {\n")
+		for _, t := range template {
+			sb.WriteString("  " + t + "\n")
+		}
+		sb.WriteString("}")
+	case b.form == "SELECT" && b.distinct:
+		sb.WriteString(" DISTINCT")
+	}
+	if b.form == "SELECT" {
+		if len(b.projection) == 0 {
+			sb.WriteString(" *")
+		} else {
+			sb.WriteString(" " + strings.Join(b.projection, " "))
+		}
+	}
+
+	sb.WriteString("\nWHERE {\n")
+	for _, w := range b.where {
+		sb.WriteString("  " + w + "\n")
+	}
+	sb.WriteString("}")
+	if len(b.groupBy) > 0 {
+		sb.WriteString("\nGROUP BY " + strings.Join(b.groupBy, " "))
+	}
+	if len(b.having) > 0 {
+		sb.WriteString("\nHAVING " + strings.Join(b.having, " "))
+	}
+	if len(b.orderBy) > 0 {
+		sb.WriteString("\nORDER BY " + strings.Join(b.orderBy, " "))
+	}
+	if b.limit >= 0 {
+		sb.WriteString("\nLIMIT " + strconv.Itoa(b.limit))
+	}
+	if b.offset > 0 {
+		sb.WriteString("\nOFFSET " + strconv.Itoa(b.offset))
+	}
+	return sb.String(), nil
+}
+
+// Request returns the query as a request for QueryService.ExecuteSPARQL, with the values of Bind as
+// its parameters.
+func (b *Builder) Request() (*dwapi.SPARQLQueryRequest, error) {
+	query, err := b.Query()
+	if err != nil {
+		return nil, err
+	}
+	return dwapi.NewSPARQLQueryRequest(query, b.params)
+}
+
+func (b *Builder) fail(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+func (b *Builder) pattern(p Pattern) (string, bool) {
+	terms := make([]string, 3)
+	for i, v := range []interface{}{p.Subject, p.Predicate, p.Object} {
+		t, err := b.term(v)
+		if err != nil {
+			b.fail(err)
+			return "", false
+		}
+		terms[i] = t
+	}
+	return strings.Join(terms, " ") + " .", true
+}
+
+// term writes a part of a pattern or a value in SPARQL syntax.
+func (b *Builder) term(v interface{}) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", errors.New("sparql: nil is not a term")
+	case string:
+		return b.name(v)
+	case rdf.IRI:
+		return iriRef(v)
+	case rdf.BlankNode:
+		return b.name("_:" + string(v))
+	case rdf.Literal:
+		return literal(v)
+	}
+	l, err := rdf.LiteralOf(v)
+	if err != nil {
+		return "", fmt.Errorf("sparql: %s", strings.TrimPrefix(err.Error(), "rdf: "))
+	}
+	return literal(l)
+}
+
+func (b *Builder) name(s string) (string, error) {
+	switch {
+	case s == "a" || variable.MatchString(s):
+		return s, nil
+	case strings.HasPrefix(s, "<") && strings.HasSuffix(s, ">"):
+		return iriRef(rdf.IRI(s[1 : len(s)-1]))
+	case strings.HasPrefix(s, "_:") && len(s) > 2 && isLabel(s[2:]):
+		return s, nil
+	}
+	m := prefixedName.FindStringSubmatch(s)
+	if m == nil {
+		return "", fmt.Errorf("sparql: %q is not a variable or a name; literal strings are passed as rdf.Literal", s)
+	}
+	if _, ok := b.prefixes[m[1]]; !ok {
+		return "", fmt.Errorf("sparql: undeclared prefix %q in %s", m[1], s)
+	}
+	return s, nil
+}
+
+func isLabel(s string) bool {
+	for _, r := range s {
+		if !(r == '_' || r == '-' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// iriRef writes an IRI in angle brackets. SPARQL decodes \u escapes before parsing a query, so the
+// characters that can't be written in an IRI are rejected rather than escaped.
+func iriRef(iri rdf.IRI) (string, error) {
+	if strings.IndexFunc(string(iri), func(r rune) bool {
+		return r <= ' ' || strings.ContainsRune("<>\"{}|^`\\", r)
+	}) >= 0 {
+		return "", fmt.Errorf("sparql: invalid IRI %q", string(iri))
+	}
+	return "<" + string(iri) + ">", nil
+}
+
+func literal(l rdf.Literal) (string, error) {
+	s := rdf.Quote(l.Lexical)
+	switch {
+	case l.Language != "":
+		if !languageTag.MatchString(l.Language) {
+			return "", fmt.Errorf("sparql: invalid language tag %q", l.Language)
+		}
+		return s + "@" + l.Language, nil
+	case l.Datatype == "" || l.Datatype == rdf.XSDString:
+		return s, nil
+	}
+	dt, err := iriRef(l.Datatype)
+	if err != nil {
+		return "", err
+	}
+	return s + "^^" + dt, nil
+}
@@ -0,0 +1,142 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package sparql
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/datadotworld/dwapi-go/dwapi"
+	"github.com/datadotworld/dwapi-go/rdf"
+	"github.com/stretchr/testify/assert"
+)
+
+const wellKnownPrefixes = `PREFIX dct: <http://purl.org/dc/terms/>
+PREFIX dwo: <https://ontology.data.world/v0#>
+PREFIX owl: <http://www.w3.org/2002/07/owl#>
+PREFIX rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#>
+PREFIX rdfs: <http://www.w3.org/2000/01/rdf-schema#>
+PREFIX xsd: <http://www.w3.org/2001/XMLSchema#>
+`
+
+func TestBuilder_Select(t *testing.T) {
+	query, err := NewSelect("?person").SelectAs("COUNT(?visit)", "visits").Distinct().
+		Dataset("my-username", "my-awesome-dataset", "visits").
+		Where(Triple("?visit", "visits:person", "?person"), Triple("?visit", "visits:channel", "?channel")).
+		Optional(Triple("?person", "rdfs:label", "?name")).
+		Filter("?channel != \"store\"").
+		Values([]string{"?channel"}, []interface{}{rdf.NewLiteral("web", "", "")}, []interface{}{nil}).
+		GroupBy("?person").
+		Having("COUNT(?visit) > 2").
+		OrderBy("DESC(?visits)", "?person").
+		Limit(10).
+		Offset(20).
+		Query()
+	if assert.NoError(t, err) {
+		assert.Equal(t, "PREFIX : <https://my-username.linked.data.world/d/my-awesome-dataset/>\n"+
+			strings.Replace(wellKnownPrefixes, "PREFIX xsd:",
+				"PREFIX visits: <https://my-username.linked.data.world/d/my-awesome-dataset/visits/>\nPREFIX xsd:", 1)+
+			`SELECT DISTINCT ?person (COUNT(?visit) AS ?visits)
+WHERE {
+  ?visit visits:person ?person .
+  ?visit visits:channel ?channel .
+  OPTIONAL { ?person rdfs:label ?name . }
+  FILTER (?channel != "store")
+  VALUES (?channel) { ("web") (UNDEF) }
+}
+GROUP BY ?person
+HAVING (COUNT(?visit) > 2)
+ORDER BY DESC(?visits) ?person
+LIMIT 10
+OFFSET 20`, query)
+	}
+}
+
+func TestBuilder_Construct(t *testing.T) {
+	query, err := NewConstruct(Triple("?s", "a", "ex:Person"), Triple("?s", "ex:seen", "_:b0")).
+		Prefix("ex", "http://example.com/").
+		Where(Triple("?s", rdf.RDFType, rdf.IRI("http://example.com/Person"))).
+		Query()
+	if assert.NoError(t, err) {
+		assert.Equal(t, strings.Replace(wellKnownPrefixes, "PREFIX owl:", "PREFIX ex: <http://example.com/>\nPREFIX owl:", 1)+
+			`CONSTRUCT {
+  ?s a ex:Person .
+  ?s ex:seen _:b0 .
+}
+WHERE {
+  ?s <http://www.w3.org/1999/02/22-rdf-syntax-ns#type> <http://example.com/Person> .
+}`, query)
+	}
+
+	query, err = NewAsk().Where(Triple("?s", "dwo:name", "?o")).Query()
+	if assert.NoError(t, err) {
+		assert.Equal(t, wellKnownPrefixes+"ASK\nWHERE {\n  ?s dwo:name ?o .\n}", query)
+	}
+}
+
+func TestBuilder_Literals(t *testing.T) {
+	tests := map[interface{}]string{
+		rdf.NewLiteral("say \"hi\"\n\t\\", "", ""): `"say \"hi\"\n\t\\"`,
+		rdf.NewLiteral("chat", "", "fr"):           `"chat"@fr`,
+		42:                                         `"42"^^<http://www.w3.org/2001/XMLSchema#integer>`,
+		true:                                       `"true"^^<http://www.w3.org/2001/XMLSchema#boolean>`,
+		time.Date(2018, 8, 3, 0, 0, 0, 0, time.UTC): `"2018-08-03T00:00:00Z"^^<http://www.w3.org/2001/XMLSchema#dateTime>`,
+		rdf.IRI("http://example.com/a"):             "<http://example.com/a>",
+		"<http://example.com/b>":                    "<http://example.com/b>",
+	}
+	for v, want := range tests {
+		query, err := NewAsk().Where(Triple("?s", "?p", v)).Query()
+		if assert.NoError(t, err) {
+			assert.Contains(t, query, "?s ?p "+want+" .")
+		}
+	}
+}
+
+func TestBuilder_Request(t *testing.T) {
+	req, err := NewSelect("?s").Where(Triple("?s", "dwo:year", "?year")).Filter("?year >= $since").
+		Bind("since", 2017).Request()
+	if assert.NoError(t, err) {
+		assert.Equal(t, &dwapi.SPARQLQueryRequest{
+			Query: wellKnownPrefixes + "SELECT ?s\nWHERE {\n  ?s dwo:year ?year .\n  FILTER (?year >= $since)\n}",
+			Parameters: map[string]string{
+				"$since": `"2017"^^<http://www.w3.org/2001/XMLSchema#integer>`,
+			},
+		}, req)
+	}
+}
+
+func TestBuilder_Errors(t *testing.T) {
+	tests := map[*Builder]string{
+		NewSelect("s"): `sparql: invalid variable "s"`,
+		NewAsk().Where(Triple("?s", "rdfs:label", "Ada")):                    `sparql: "Ada" is not a variable or a name; literal strings are passed as rdf.Literal`,
+		NewAsk().Where(Triple("?s", "ex:knows", "?o")):                       `sparql: undeclared prefix "ex" in ex:knows`,
+		NewAsk().Where(Triple("?s", "?p", rdf.IRI("http://example.com/a>"))): `sparql: invalid IRI "http://example.com/a>"`,
+		NewAsk().Where(Triple("?s", "?p", rdf.NewLiteral("x", "", "fr\""))):  `sparql: invalid language tag "fr\""`,
+		NewAsk().Where(Triple("?s", "?p", struct{}{})):                       "sparql: no literal for values of type struct {}",
+		NewAsk().Where(Triple(nil, "?p", "?o")):                              "sparql: nil is not a term",
+		NewAsk().Values([]string{"?a", "?b"}, []interface{}{1}):              "sparql: VALUES row 1 has 1 values for 2 variables",
+		NewAsk().Prefix("1x", "http://example.com/"):                         `sparql: invalid prefix "1x"`,
+		NewSelect().Limit(-1):                                                "sparql: invalid limit -1",
+		NewSelect().Where(Triple("?s", "?p", "?o")).Bind("missing", 1):       "dwapi: parameter $missing is not a variable of the query",
+	}
+	for b, want := range tests {
+		_, err := b.Request()
+		assert.EqualError(t, err, want)
+	}
+}
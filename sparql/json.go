@@ -0,0 +1,63 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package sparql
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/datadotworld/dwapi-go/dwapi"
+)
+
+// ParseJSON parses results in the SPARQL 1.1 Query Results JSON Format.
+func ParseJSON(r io.Reader) (*Results, error) {
+	sr, err := dwapi.NewSPARQLJSONReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("sparql: invalid JSON results: %s", err)
+	}
+	if !sr.HasBindings() && sr.Boolean() == nil {
+		return nil, fmt.Errorf("sparql: JSON results have neither results nor a boolean")
+	}
+
+	res := &Results{Vars: sr.Vars(), Links: sr.Links(), Boolean: sr.Boolean()}
+	if !sr.HasBindings() {
+		return res, nil
+	}
+	res.Bindings = []Binding{}
+	for {
+		row, err := sr.Next()
+		if err == io.EOF {
+			return res, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("sparql: invalid JSON results: %s", err)
+		}
+		b := make(Binding, len(row))
+		for i, rt := range row {
+			if rt == nil {
+				continue
+			}
+			t, err := term(rt.Type, rt.Value, rt.Datatype, rt.Lang)
+			if err != nil {
+				return nil, err
+			}
+			b[sr.Vars()[i]] = t
+		}
+		res.Bindings = append(res.Bindings, b)
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+/*
+Package sparql parses the results of SPARQL SELECT and ASK queries, in the SPARQL 1.1 JSON and XML
+result formats, into typed bindings. The graphs of CONSTRUCT and DESCRIBE queries are parsed into an
+`rdf.Graph`.
+
+	results, err := sparql.Query(dw.Query, "my-username", "my-awesome-dataset",
+		&dwapi.SPARQLQueryRequest{Query: "SELECT ?s ?label WHERE { ?s rdfs:label ?label }"})
+	for _, b := range results.Bindings {
+		if label, ok := b["label"].(rdf.Literal); ok {
+			fmt.Println(b["s"], label.Lexical, label.Language)
+		}
+	}
+
+NewSelect, NewAsk and NewConstruct build queries with declared prefixes, checked triple patterns and
+escaped literals.
+*/
+package sparql
+
+import (
+	"fmt"
+	"io"
+	"mime"
+
+	"github.com/datadotworld/dwapi-go/dwapi"
+	"github.com/datadotworld/dwapi-go/rdf"
+)
+
+// Content types of the result formats.
+const (
+	JSONResults = "application/sparql-results+json"
+	XMLResults  = "application/sparql-results+xml"
+)
+
+// Results are the results of a SELECT or an ASK query.
+type Results struct {
+	// Vars are the variables of a SELECT query, in order.
+	Vars []string
+	// Bindings are the solutions of a SELECT query. Unbound variables are missing from a binding.
+	Bindings []Binding
+	// Boolean is the result of an ASK query, and nil for a SELECT query.
+	Boolean *bool
+	// Links are the links of the head of the results.
+	Links []string
+}
+
+// Binding maps the variables of a solution to their values.
+type Binding map[string]rdf.Term
+
+// IsAsk reports whether the results are those of an ASK query.
+func (r *Results) IsAsk() bool {
+	return r.Boolean != nil
+}
+
+// Values converts the values of a binding to Go values: strings for IRIs and blank nodes, and the
+// values of their datatype for literals, as converted by `rdf.Literal.Value`.
+func (b Binding) Values() (map[string]interface{}, error) {
+	values := make(map[string]interface{}, len(b))
+	for name, term := range b {
+		switch t := term.(type) {
+		case rdf.IRI:
+			values[name] = string(t)
+		case rdf.BlankNode:
+			values[name] = string(t)
+		case rdf.Literal:
+			v, err := t.Value()
+			if err != nil {
+				return nil, fmt.Errorf("sparql: ?%s: %s", name, err)
+			}
+			values[name] = v
+		}
+	}
+	return values, nil
+}
+
+// Parse parses results of the given content type, JSONResults or XMLResults.
+func Parse(r io.Reader, contentType string) (*Results, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = contentType
+	}
+	switch mediaType {
+	case JSONResults, "application/json":
+		return ParseJSON(r)
+	case XMLResults, "application/xml", "text/xml":
+		return ParseXML(r)
+	}
+	return nil, fmt.Errorf("sparql: unsupported results type %q", contentType)
+}
+
+// Query runs a SELECT or ASK query against a dataset or project and parses its results.
+func Query(q dwapi.QueryAPI, owner, id string, body *dwapi.SPARQLQueryRequest) (*Results, error) {
+	r, err := q.ExecuteSPARQL(owner, id, JSONResults, body)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ParseJSON(r)
+}
+
+// Construct runs a CONSTRUCT or DESCRIBE query against a dataset or project and parses the resulting
+// graph, which it requests as Turtle.
+func Construct(q dwapi.QueryAPI, owner, id string, body *dwapi.SPARQLQueryRequest) (*rdf.Graph, error) {
+	r, err := q.ExecuteSPARQL(owner, id, rdf.Turtle, body)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return rdf.ParseTurtle(r, "")
+}
+
+// term builds the term of a binding from its type, value, datatype and language.
+func term(typ, value, datatype, lang string) (rdf.Term, error) {
+	switch typ {
+	case "uri":
+		return rdf.IRI(value), nil
+	case "bnode":
+		return rdf.BlankNode(value), nil
+	case "literal", "typed-literal":
+		return rdf.NewLiteral(value, rdf.IRI(datatype), lang), nil
+	}
+	return nil, fmt.Errorf("sparql: unknown term type %q", typ)
+}
@@ -0,0 +1,173 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package sparql
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/datadotworld/dwapi-go/dwapi"
+	"github.com/datadotworld/dwapi-go/dwapitest"
+	"github.com/datadotworld/dwapi-go/rdf"
+	"github.com/stretchr/testify/assert"
+)
+
+const selectJSON = `{
+	"head": {"vars": ["s", "label", "n", "when"], "link": ["http://example.com/about"]},
+	"results": {"bindings": [
+		{
+			"s": {"type": "uri", "value": "http://example.com/a"},
+			"label": {"type": "literal", "value": "Chat", "xml:lang": "fr"},
+			"n": {"type": "literal", "value": "42", "datatype": "http://www.w3.org/2001/XMLSchema#integer"},
+			"when": {"type": "typed-literal", "value": "2018-08-03T15:56:41Z", "datatype": "http://www.w3.org/2001/XMLSchema#dateTime"}
+		},
+		{"s": {"type": "bnode", "value": "b0"}, "label": {"type": "literal", "value": "plain"}}
+	]}
+}`
+
+const selectXML = `<?xml version="1.0"?>
+<sparql xmlns="http://www.w3.org/2005/sparql-results#">
+  <head>
+    <variable name="s"/><variable name="label"/><variable name="n"/><variable name="when"/>
+    <link href="http://example.com/about"/>
+  </head>
+  <results>
+    <result>
+      <binding name="s"><uri>http://example.com/a</uri></binding>
+      <binding name="label"><literal xml:lang="fr">Chat</literal></binding>
+      <binding name="n"><literal datatype="http://www.w3.org/2001/XMLSchema#integer">42</literal></binding>
+      <binding name="when"><literal datatype="http://www.w3.org/2001/XMLSchema#dateTime">2018-08-03T15:56:41Z</literal></binding>
+    </result>
+    <result>
+      <binding name="s"><bnode>b0</bnode></binding>
+      <binding name="label"><literal>plain</literal></binding>
+    </result>
+  </results>
+</sparql>`
+
+var selectResults = &Results{
+	Vars:  []string{"s", "label", "n", "when"},
+	Links: []string{"http://example.com/about"},
+	Bindings: []Binding{
+		{
+			"s":     rdf.IRI("http://example.com/a"),
+			"label": rdf.Literal{Lexical: "Chat", Datatype: rdf.RDFLangString, Language: "fr"},
+			"n":     rdf.Literal{Lexical: "42", Datatype: rdf.XSDInteger},
+			"when":  rdf.Literal{Lexical: "2018-08-03T15:56:41Z", Datatype: rdf.XSDDateTime},
+		},
+		{
+			"s":     rdf.BlankNode("b0"),
+			"label": rdf.Literal{Lexical: "plain", Datatype: rdf.XSDString},
+		},
+	},
+}
+
+func TestParse_Select(t *testing.T) {
+	for contentType, body := range map[string]string{
+		JSONResults:                    selectJSON,
+		XMLResults + "; charset=utf-8": selectXML,
+	} {
+		got, err := Parse(strings.NewReader(body), contentType)
+		if assert.NoError(t, err, contentType) {
+			assert.Equal(t, selectResults, got, contentType)
+			assert.False(t, got.IsAsk())
+		}
+	}
+}
+
+func TestParse_Ask(t *testing.T) {
+	for contentType, body := range map[string]string{
+		JSONResults: `{"head": {}, "boolean": true}`,
+		XMLResults:  `<sparql xmlns="http://www.w3.org/2005/sparql-results#"><head/><boolean>true</boolean></sparql>`,
+	} {
+		got, err := Parse(strings.NewReader(body), contentType)
+		if assert.NoError(t, err, contentType) && assert.True(t, got.IsAsk()) {
+			assert.True(t, *got.Boolean)
+			assert.Empty(t, got.Bindings)
+		}
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		body, contentType, want string
+	}{
+		{`{"head": {}}`, JSONResults, "sparql: JSON results have neither results nor a boolean"},
+		{`{"head": {"vars": ["s"]}, "results": {"bindings": [{"s": {"type": "iri", "value": "x"}}]}}`, JSONResults,
+			`sparql: unknown term type "iri"`},
+		{`{`, JSONResults, "sparql: invalid JSON results: unexpected end of JSON input"},
+		{`<sparql xmlns="http://www.w3.org/2005/sparql-results#"><head/><boolean>yes</boolean></sparql>`, XMLResults,
+			`sparql: invalid boolean "yes" in XML results`},
+		{`<sparql xmlns="http://www.w3.org/2005/sparql-results#"><head/><results><result><binding name="s"/></result></results></sparql>`,
+			XMLResults, "sparql: binding of ?s has no value"},
+		{`s,p,o`, "text/csv", `sparql: unsupported results type "text/csv"`},
+	}
+	for _, test := range tests {
+		_, err := Parse(strings.NewReader(test.body), test.contentType)
+		assert.EqualError(t, err, test.want)
+	}
+}
+
+func TestBinding_Values(t *testing.T) {
+	got, err := selectResults.Bindings[0].Values()
+	if assert.NoError(t, err) {
+		assert.Equal(t, map[string]interface{}{
+			"s":     "http://example.com/a",
+			"label": "Chat",
+			"n":     int64(42),
+			"when":  time.Date(2018, 8, 3, 15, 56, 41, 0, time.UTC),
+		}, got)
+	}
+
+	_, err = Binding{"n": rdf.Literal{Lexical: "many", Datatype: rdf.XSDInteger}}.Values()
+	assert.EqualError(t, err, `sparql: ?n: rdf: literal "many"^^<http://www.w3.org/2001/XMLSchema#integer> is not a valid integer`)
+}
+
+func TestQuery(t *testing.T) {
+	srv := dwapitest.NewServer()
+	defer srv.Close()
+	srv.AddDataset(dwapi.DatasetSummaryResponse{Owner: "my-username", ID: "my-awesome-dataset"})
+	query := "SELECT ?s ?label ?n ?when WHERE { ?s ?p ?o }"
+	srv.SetSPARQLResult(query, JSONResults, []byte(selectJSON))
+
+	got, err := Query(srv.NewClient().Query, "my-username", "my-awesome-dataset", &dwapi.SPARQLQueryRequest{Query: query})
+	if assert.NoError(t, err) {
+		assert.Equal(t, selectResults, got)
+	}
+}
+
+func TestConstruct(t *testing.T) {
+	srv := dwapitest.NewServer()
+	defer srv.Close()
+	srv.AddDataset(dwapi.DatasetSummaryResponse{Owner: "my-username", ID: "my-awesome-dataset"})
+	query := "CONSTRUCT { ?s a ?type } WHERE { ?s a ?type }"
+	srv.SetSPARQLResult(query, rdf.Turtle, []byte(`@prefix ex: <http://example.com/> .
+ex:alice a ex:Person .
+`))
+
+	g, err := Construct(srv.NewClient().Query, "my-username", "my-awesome-dataset", &dwapi.SPARQLQueryRequest{Query: query})
+	if assert.NoError(t, err) {
+		assert.Equal(t, []rdf.Triple{{
+			Subject:   rdf.IRI("http://example.com/alice"),
+			Predicate: rdf.RDFType,
+			Object:    rdf.IRI("http://example.com/Person"),
+		}}, g.Triples())
+		assert.Equal(t, map[string]rdf.IRI{"ex": "http://example.com/"}, g.Prefixes())
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright © 2018 data.world, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// This product includes software developed at
+// data.world, Inc.(http://data.world/).
+
+package sparql
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type xmlResults struct {
+	XMLName xml.Name `xml:"http://www.w3.org/2005/sparql-results# sparql"`
+	Head    struct {
+		Variables []struct {
+			Name string `xml:"name,attr"`
+		} `xml:"variable"`
+		Links []struct {
+			Href string `xml:"href,attr"`
+		} `xml:"link"`
+	} `xml:"head"`
+	Results *struct {
+		Results []struct {
+			Bindings []xmlBinding `xml:"binding"`
+		} `xml:"result"`
+	} `xml:"results"`
+	Boolean *string `xml:"boolean"`
+}
+
+type xmlBinding struct {
+	Name    string  `xml:"name,attr"`
+	URI     *string `xml:"uri"`
+	BNode   *string `xml:"bnode"`
+	Literal *struct {
+		Value    string `xml:",chardata"`
+		Datatype string `xml:"datatype,attr"`
+		Lang     string `xml:"http://www.w3.org/XML/1998/namespace lang,attr"`
+	} `xml:"literal"`
+}
+
+// ParseXML parses results in the SPARQL Query Results XML Format.
+func ParseXML(r io.Reader) (*Results, error) {
+	var doc xmlResults
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("sparql: invalid XML results: %s", err)
+	}
+
+	res := &Results{}
+	for _, v := range doc.Head.Variables {
+		res.Vars = append(res.Vars, v.Name)
+	}
+	for _, l := range doc.Head.Links {
+		res.Links = append(res.Links, l.Href)
+	}
+	if doc.Boolean != nil {
+		b, err := strconv.ParseBool(strings.TrimSpace(*doc.Boolean))
+		if err != nil {
+			return nil, fmt.Errorf("sparql: invalid boolean %q in XML results", *doc.Boolean)
+		}
+		res.Boolean = &b
+		return res, nil
+	}
+	if doc.Results == nil {
+		return nil, fmt.Errorf("sparql: XML results have neither results nor a boolean")
+	}
+
+	res.Bindings = make([]Binding, 0, len(doc.Results.Results))
+	for _, xr := range doc.Results.Results {
+		b := make(Binding, len(xr.Bindings))
+		for _, xb := range xr.Bindings {
+			var err error
+			switch {
+			case xb.URI != nil:
+				b[xb.Name], err = term("uri", strings.TrimSpace(*xb.URI), "", "")
+			case xb.BNode != nil:
+				b[xb.Name], err = term("bnode", strings.TrimSpace(*xb.BNode), "", "")
+			case xb.Literal != nil:
+				b[xb.Name], err = term("literal", xb.Literal.Value, xb.Literal.Datatype, xb.Literal.Lang)
+			default:
+				err = fmt.Errorf("sparql: binding of ?%s has no value", xb.Name)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		res.Bindings = append(res.Bindings, b)
+	}
+	return res, nil
+}